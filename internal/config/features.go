@@ -6,6 +6,8 @@ type Features struct {
 	Combat                 bool
 	Events                 bool
 	FailExpiredExpeditions bool
+	BattleAttempts         bool
+	AdaptiveDifficulty     bool
 }
 
 // DefaultFeatures returns the default feature configuration.
@@ -15,5 +17,7 @@ func DefaultFeatures() Features {
 		Combat:                 true,
 		Events:                 false,
 		FailExpiredExpeditions: true,
+		BattleAttempts:         false,
+		AdaptiveDifficulty:     false,
 	}
 }
@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"solo-leveling/internal/models"
@@ -12,9 +13,22 @@ import (
 // ============================================================
 
 func (db *DB) InsertEnemy(e *models.Enemy) error {
+	return db.insertEnemy(0, e)
+}
+
+// InsertEndlessFloor stores a generated tower floor as the character's own enemy.
+func (db *DB) InsertEndlessFloor(charID int64, e *models.Enemy) error {
+	return db.insertEnemy(charID, e)
+}
+
+func (db *DB) insertEnemy(charID int64, e *models.Enemy) error {
+	phases, err := marshalBossPhases(e.BossPhases)
+	if err != nil {
+		return err
+	}
 	res, err := db.conn.Exec(
-		`INSERT INTO enemies (name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO enemies (name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max, image, endless, char_id, boss_phases)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		e.Name,
 		e.Description,
 		string(e.Rank),
@@ -30,6 +44,10 @@ func (db *DB) InsertEnemy(e *models.Enemy) error {
 		boolToInt(e.IsTransition),
 		e.TargetWinRateMin,
 		e.TargetWinRateMax,
+		e.Image,
+		boolToInt(e.Endless),
+		charID,
+		phases,
 	)
 	if err != nil {
 		return err
@@ -38,10 +56,12 @@ func (db *DB) InsertEnemy(e *models.Enemy) error {
 	return nil
 }
 
+// GetAllEnemies returns the catalog enemies; endless floors are kept per character.
 func (db *DB) GetAllEnemies() ([]models.Enemy, error) {
 	rows, err := db.conn.Query(
-		`SELECT id, name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max
+		`SELECT id, name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max, image, endless, boss_phases
 		 FROM enemies
+		 WHERE endless = 0
 		 ORDER BY zone, level, id`,
 	)
 	if err != nil {
@@ -54,6 +74,8 @@ func (db *DB) GetAllEnemies() ([]models.Enemy, error) {
 		var e models.Enemy
 		var isBoss int
 		var isTransition int
+		var endless int
+		var phases string
 		if err := rows.Scan(
 			&e.ID,
 			&e.Name,
@@ -71,11 +93,18 @@ func (db *DB) GetAllEnemies() ([]models.Enemy, error) {
 			&isTransition,
 			&e.TargetWinRateMin,
 			&e.TargetWinRateMax,
+			&e.Image,
+			&endless,
+			&phases,
 		); err != nil {
 			return nil, err
 		}
 		e.IsBoss = isBoss == 1
 		e.IsTransition = isTransition == 1
+		e.Endless = endless == 1
+		if err := json.Unmarshal([]byte(phases), &e.BossPhases); err != nil {
+			return nil, err
+		}
 		enemies = append(enemies, e)
 	}
 	return enemies, nil
@@ -87,7 +116,9 @@ func (db *DB) GetEnemyCount() (int, error) {
 	return count, err
 }
 
-// EnemyCatalogNeedsReseed checks whether DB enemies differ from preset catalog.
+// EnemyCatalogNeedsReseed checks whether DB enemies differ from the preset
+// catalog in shape: names, zones, floors, levels or bosses. Tuned numbers are
+// applied in place by SyncEnemyCatalogStats.
 func (db *DB) EnemyCatalogNeedsReseed(preset []models.Enemy) (bool, error) {
 	if len(preset) == 0 {
 		return false, nil
@@ -103,6 +134,7 @@ func (db *DB) EnemyCatalogNeedsReseed(preset []models.Enemy) (bool, error) {
 
 	type sig struct {
 		Zone   int
+		Floor  int
 		Level  int
 		IsBoss bool
 	}
@@ -111,6 +143,7 @@ func (db *DB) EnemyCatalogNeedsReseed(preset []models.Enemy) (bool, error) {
 	for _, e := range preset {
 		expected[e.Name] = sig{
 			Zone:   e.Zone,
+			Floor:  e.Floor,
 			Level:  e.Level,
 			IsBoss: e.IsBoss || e.Type == models.EnemyBoss,
 		}
@@ -122,7 +155,7 @@ func (db *DB) EnemyCatalogNeedsReseed(preset []models.Enemy) (bool, error) {
 			return true, nil
 		}
 		isBoss := e.IsBoss || e.Type == models.EnemyBoss
-		if e.Zone != want.Zone || e.Level != want.Level || isBoss != want.IsBoss {
+		if e.Zone != want.Zone || e.Floor != want.Floor || e.Level != want.Level || isBoss != want.IsBoss {
 			return true, nil
 		}
 	}
@@ -145,6 +178,13 @@ func (db *DB) ReplaceEnemyCatalog(enemies []models.Enemy) error {
 	if _, err := tx.Exec("DELETE FROM enemy_unlocks"); err != nil {
 		return err
 	}
+	// A saved fight would point at an enemy ID that no longer exists.
+	if _, err := tx.Exec("DELETE FROM active_battles"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM battle_rounds"); err != nil {
+		return err
+	}
 	if _, err := tx.Exec("DELETE FROM battles"); err != nil {
 		return err
 	}
@@ -154,9 +194,13 @@ func (db *DB) ReplaceEnemyCatalog(enemies []models.Enemy) error {
 
 	for i := range enemies {
 		e := enemies[i]
+		phases, err := marshalBossPhases(e.BossPhases)
+		if err != nil {
+			return err
+		}
 		if _, err := tx.Exec(
-			`INSERT INTO enemies (name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max)
-			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			`INSERT INTO enemies (name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max, image, endless, boss_phases)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			e.Name,
 			e.Description,
 			string(e.Rank),
@@ -172,6 +216,9 @@ func (db *DB) ReplaceEnemyCatalog(enemies []models.Enemy) error {
 			boolToInt(e.IsTransition),
 			e.TargetWinRateMin,
 			e.TargetWinRateMax,
+			e.Image,
+			boolToInt(e.Endless),
+			phases,
 		); err != nil {
 			return err
 		}
@@ -180,12 +227,60 @@ func (db *DB) ReplaceEnemyCatalog(enemies []models.Enemy) error {
 	return tx.Commit()
 }
 
+// SyncEnemyCatalogStats copies the tunable fields of the preset catalog onto
+// the stored enemies, matched by name. Enemy IDs and battle progress are kept.
+func (db *DB) SyncEnemyCatalogStats(preset []models.Enemy) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range preset {
+		phases, err := marshalBossPhases(e.BossPhases)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
+			`UPDATE enemies
+			 SET description = ?, rank = ?, hp = ?, attack = ?, biome = ?, role = ?, is_transition = ?, target_winrate_min = ?, target_winrate_max = ?, image = ?, boss_phases = ?
+			 WHERE name = ? AND endless = 0`,
+			e.Description,
+			string(e.Rank),
+			e.HP,
+			e.Attack,
+			e.Biome,
+			e.Role,
+			boolToInt(e.IsTransition),
+			e.TargetWinRateMin,
+			e.TargetWinRateMax,
+			e.Image,
+			phases,
+			e.Name,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// marshalBossPhases stores a boss script as JSON; regular enemies keep an empty list.
+func marshalBossPhases(phases []models.BossPhase) (string, error) {
+	if phases == nil {
+		phases = []models.BossPhase{}
+	}
+	data, err := json.Marshal(phases)
+	return string(data), err
+}
+
 func (db *DB) GetEnemyByID(id int64) (*models.Enemy, error) {
 	var e models.Enemy
 	var isBoss int
 	var isTransition int
+	var endless int
+	var phases string
 	err := db.conn.QueryRow(
-		`SELECT id, name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max
+		`SELECT id, name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max, image, endless, boss_phases
 		 FROM enemies
 		 WHERE id = ?`,
 		id,
@@ -206,20 +301,43 @@ func (db *DB) GetEnemyByID(id int64) (*models.Enemy, error) {
 		&isTransition,
 		&e.TargetWinRateMin,
 		&e.TargetWinRateMax,
+		&e.Image,
+		&endless,
+		&phases,
 	)
 	if err != nil {
 		return nil, err
 	}
 	e.IsBoss = isBoss == 1
 	e.IsTransition = isTransition == 1
+	e.Endless = endless == 1
+	if err := json.Unmarshal([]byte(phases), &e.BossPhases); err != nil {
+		return nil, err
+	}
 	return &e, nil
 }
 
+// GetEndlessFloor returns the character's stored endless floor, nil until it is first fought.
+func (db *DB) GetEndlessFloor(charID int64, floor int) (*models.Enemy, error) {
+	var id int64
+	err := db.conn.QueryRow(
+		"SELECT id FROM enemies WHERE endless = 1 AND char_id = ? AND floor = ?",
+		charID, floor,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return db.GetEnemyByID(id)
+}
+
 func (db *DB) GetEnemiesByFloor(floor int) ([]models.Enemy, error) {
 	rows, err := db.conn.Query(
-		`SELECT id, name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max
+		`SELECT id, name, description, rank, type, level, hp, attack, floor, zone, is_boss, biome, role, is_transition, target_winrate_min, target_winrate_max, image, endless, boss_phases
 		 FROM enemies
-		 WHERE floor = ?
+		 WHERE floor = ? AND endless = 0
 		 ORDER BY id`,
 		floor,
 	)
@@ -233,6 +351,8 @@ func (db *DB) GetEnemiesByFloor(floor int) ([]models.Enemy, error) {
 		var e models.Enemy
 		var isBoss int
 		var isTransition int
+		var endless int
+		var phases string
 		if err := rows.Scan(
 			&e.ID,
 			&e.Name,
@@ -250,11 +370,18 @@ func (db *DB) GetEnemiesByFloor(floor int) ([]models.Enemy, error) {
 			&isTransition,
 			&e.TargetWinRateMin,
 			&e.TargetWinRateMax,
+			&e.Image,
+			&endless,
+			&phases,
 		); err != nil {
 			return nil, err
 		}
 		e.IsBoss = isBoss == 1
 		e.IsTransition = isTransition == 1
+		e.Endless = endless == 1
+		if err := json.Unmarshal([]byte(phases), &e.BossPhases); err != nil {
+			return nil, err
+		}
 		enemies = append(enemies, e)
 	}
 	return enemies, nil
@@ -262,7 +389,7 @@ func (db *DB) GetEnemiesByFloor(floor int) ([]models.Enemy, error) {
 
 func (db *DB) GetMaxFloor() (int, error) {
 	var maxFloor int
-	err := db.conn.QueryRow("SELECT COALESCE(MAX(floor), 0) FROM enemies").Scan(&maxFloor)
+	err := db.conn.QueryRow("SELECT COALESCE(MAX(floor), 0) FROM enemies WHERE endless = 0").Scan(&maxFloor)
 	return maxFloor, err
 }
 
@@ -278,7 +405,8 @@ func (db *DB) NormalizeEnemyZones() error {
 		return err
 	}
 
-	rows, err := tx.Query("SELECT DISTINCT zone FROM enemies ORDER BY zone")
+	// Endless zones get their boss when the floor is generated.
+	rows, err := tx.Query("SELECT DISTINCT zone FROM enemies WHERE endless = 0 ORDER BY zone")
 	if err != nil {
 		return err
 	}
@@ -368,9 +496,11 @@ func (db *DB) NormalizeEnemyZones() error {
 
 func (db *DB) GetDefeatedEnemies(charID int64) ([]models.DefeatedEnemy, error) {
 	rows, err := db.conn.Query(`
-		SELECT e.id, e.name, e.description, e.rank, e.zone, e.is_boss, MAX(b.fought_at) AS defeated_at
+		SELECT e.id, e.name, e.description, e.rank, e.zone, e.is_boss, MAX(b.fought_at) AS defeated_at,
+		       COALESCE(r.title, ''), COALESCE(r.badge, ''), COALESCE(r.cosmetic, '')
 		FROM battles b
 		JOIN enemies e ON e.id = b.enemy_id
+		LEFT JOIN battle_rewards r ON r.char_id = b.char_id AND r.enemy_id = e.id
 		WHERE b.char_id = ? AND b.result = ?
 		GROUP BY e.id, e.name, e.description, e.rank, e.zone, e.is_boss, e.level
 		ORDER BY e.zone ASC, e.level ASC, e.is_boss ASC, e.id ASC
@@ -385,7 +515,8 @@ func (db *DB) GetDefeatedEnemies(charID int64) ([]models.DefeatedEnemy, error) {
 		var item models.DefeatedEnemy
 		var isBoss int
 		var defeatedAt sql.NullString
-		if err := rows.Scan(&item.EnemyID, &item.Name, &item.Description, &item.Rank, &item.Zone, &isBoss, &defeatedAt); err != nil {
+		if err := rows.Scan(&item.EnemyID, &item.Name, &item.Description, &item.Rank, &item.Zone, &isBoss, &defeatedAt,
+			&item.RewardTitle, &item.RewardBadge, &item.RewardCosmetic); err != nil {
 			return nil, err
 		}
 		item.IsBoss = isBoss == 1
@@ -468,7 +599,11 @@ func (db *DB) GetUnlockedEnemyIDs(charID int64) (map[int64]bool, error) {
 }
 
 func (db *DB) UnlockEnemy(charID, enemyID int64) error {
-	_, err := db.conn.Exec(
+	return unlockEnemy(db.conn, charID, enemyID)
+}
+
+func unlockEnemy(exec sqlExecer, charID, enemyID int64) error {
+	_, err := exec.Exec(
 		"INSERT OR IGNORE INTO enemy_unlocks (char_id, enemy_id) VALUES (?, ?)",
 		charID, enemyID,
 	)
@@ -482,9 +617,9 @@ func (db *DB) UnlockEnemy(charID, enemyID int64) error {
 func (db *DB) GetBattleReward(charID, enemyID int64) (*models.BattleReward, error) {
 	var r models.BattleReward
 	err := db.conn.QueryRow(
-		"SELECT id, char_id, enemy_id, title, badge, awarded_at FROM battle_rewards WHERE char_id = ? AND enemy_id = ?",
+		"SELECT id, char_id, enemy_id, title, badge, cosmetic, awarded_at FROM battle_rewards WHERE char_id = ? AND enemy_id = ?",
 		charID, enemyID,
-	).Scan(&r.ID, &r.CharID, &r.EnemyID, &r.Title, &r.Badge, &r.AwardedAt)
+	).Scan(&r.ID, &r.CharID, &r.EnemyID, &r.Title, &r.Badge, &r.Cosmetic, &r.AwardedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -496,7 +631,7 @@ func (db *DB) GetBattleReward(charID, enemyID int64) (*models.BattleReward, erro
 
 func (db *DB) GetAllBattleRewards(charID int64) ([]models.BattleReward, error) {
 	rows, err := db.conn.Query(
-		"SELECT id, char_id, enemy_id, title, badge, awarded_at FROM battle_rewards WHERE char_id = ? ORDER BY awarded_at",
+		"SELECT id, char_id, enemy_id, title, badge, cosmetic, awarded_at FROM battle_rewards WHERE char_id = ? ORDER BY awarded_at",
 		charID,
 	)
 	if err != nil {
@@ -507,7 +642,7 @@ func (db *DB) GetAllBattleRewards(charID int64) ([]models.BattleReward, error) {
 	var rewards []models.BattleReward
 	for rows.Next() {
 		var r models.BattleReward
-		if err := rows.Scan(&r.ID, &r.CharID, &r.EnemyID, &r.Title, &r.Badge, &r.AwardedAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.CharID, &r.EnemyID, &r.Title, &r.Badge, &r.Cosmetic, &r.AwardedAt); err != nil {
 			return nil, err
 		}
 		rewards = append(rewards, r)
@@ -516,9 +651,13 @@ func (db *DB) GetAllBattleRewards(charID int64) ([]models.BattleReward, error) {
 }
 
 func (db *DB) InsertBattleReward(r *models.BattleReward) error {
-	res, err := db.conn.Exec(
-		"INSERT INTO battle_rewards (char_id, enemy_id, title, badge) VALUES (?, ?, ?, ?)",
-		r.CharID, r.EnemyID, r.Title, r.Badge,
+	return insertBattleReward(db.conn, r)
+}
+
+func insertBattleReward(exec sqlExecer, r *models.BattleReward) error {
+	res, err := exec.Exec(
+		"INSERT INTO battle_rewards (char_id, enemy_id, title, badge, cosmetic) VALUES (?, ?, ?, ?, ?)",
+		r.CharID, r.EnemyID, r.Title, r.Badge, r.Cosmetic,
 	)
 	if err != nil {
 		return err
@@ -531,23 +670,209 @@ func (db *DB) InsertBattleReward(r *models.BattleReward) error {
 // Battles
 // ============================================================
 
+// InsertBattle stores the battle together with its round recordings.
 func (db *DB) InsertBattle(b *models.BattleRecord) error {
-	res, err := db.conn.Exec(
-		`INSERT INTO battles (char_id, enemy_id, enemy_name, result, damage_dealt, damage_taken, accuracy, critical_hits, dodges, fought_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	battleID, err := insertBattle(tx, b, now)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	b.ID = battleID
+	b.FoughtAt = now
+	return nil
+}
+
+// BattleVictory bundles everything a won tower fight writes so it can be
+// applied in one transaction.
+type BattleVictory struct {
+	Battle *models.BattleRecord
+	// Reward is the first-win reward, nil when the enemy was beaten before.
+	Reward *models.BattleReward
+	// UnlockEnemyID is the enemy the first win opens, or 0.
+	UnlockEnemyID int64
+	// EndlessFloor raises the endless record when positive.
+	EndlessFloor int
+	// Drop is the item granted with the win, if any.
+	Drop *models.InventoryItem
+}
+
+// RecordVictory stores the battle with its rounds, grants the first-win reward,
+// unlock, endless record and item drop, and clears the saved fight atomically,
+// so a failed write never leaves a recorded win that can be finished again.
+func (db *DB) RecordVictory(v BattleVictory) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	b := v.Battle
+	now := time.Now()
+	battleID, err := insertBattle(tx, b, now)
+	if err != nil {
+		return err
+	}
+	if v.Reward != nil {
+		if err := insertBattleReward(tx, v.Reward); err != nil {
+			return err
+		}
+	}
+	if v.UnlockEnemyID > 0 {
+		if err := unlockEnemy(tx, b.CharID, v.UnlockEnemyID); err != nil {
+			return err
+		}
+	}
+	if v.EndlessFloor > 0 {
+		if err := raiseEndlessRecord(tx, b.CharID, v.EndlessFloor); err != nil {
+			return err
+		}
+	}
+	if v.Drop != nil {
+		if err := insertInventoryItem(tx, v.Drop); err != nil {
+			return err
+		}
+	}
+	if err := deleteActiveBattle(tx, b.CharID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	b.ID = battleID
+	b.FoughtAt = now
+	return nil
+}
+
+func insertBattle(exec sqlExecer, b *models.BattleRecord, now time.Time) (int64, error) {
+	res, err := exec.Exec(
+		`INSERT INTO battles (char_id, enemy_id, enemy_name, result, damage_dealt, damage_taken, accuracy, critical_hits, dodges, seed, challenge_day, fought_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		b.CharID, b.EnemyID, b.EnemyName, string(b.Result), b.DamageDealt, b.DamageTaken,
-		b.Accuracy, b.CriticalHits, b.Dodges, time.Now(),
+		b.Accuracy, b.CriticalHits, b.Dodges, b.Seed, b.ChallengeDay, now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	battleID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range b.Rounds {
+		r := &b.Rounds[i]
+		shown, err := json.Marshal(r.ShownCells)
+		if err != nil {
+			return 0, err
+		}
+		decoys, err := json.Marshal(r.DecoyCells)
+		if err != nil {
+			return 0, err
+		}
+		choices, err := json.Marshal(r.Choices)
+		if err != nil {
+			return 0, err
+		}
+		res, err := exec.Exec(
+			`INSERT INTO battle_rounds (battle_id, round, minigame, grid_size, shown_cells, decoy_cells, choices, accuracy, damage_dealt, damage_taken, is_crit, show_time_ms, duration_ms)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			battleID, r.Round, string(r.Minigame), r.GridSize, string(shown), string(decoys), string(choices), r.Accuracy,
+			r.DamageDealt, r.DamageTaken, r.IsCrit, r.ShowTimeMs, r.DurationMs,
+		)
+		if err != nil {
+			return 0, err
+		}
+		r.ID, _ = res.LastInsertId()
+		r.BattleID = battleID
+	}
+	return battleID, nil
+}
+
+// GetBattleRounds returns the recorded rounds of a battle in order.
+func (db *DB) GetBattleRounds(battleID int64) ([]models.BattleRound, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, battle_id, round, minigame, grid_size, shown_cells, decoy_cells, choices, accuracy, damage_dealt, damage_taken, is_crit, show_time_ms, duration_ms
+		FROM battle_rounds WHERE battle_id = ? ORDER BY round, id`,
+		battleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rounds []models.BattleRound
+	for rows.Next() {
+		var r models.BattleRound
+		var shown, decoys, choices string
+		if err := rows.Scan(&r.ID, &r.BattleID, &r.Round, &r.Minigame, &r.GridSize, &shown, &decoys, &choices, &r.Accuracy,
+			&r.DamageDealt, &r.DamageTaken, &r.IsCrit, &r.ShowTimeMs, &r.DurationMs); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(shown), &r.ShownCells); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(decoys), &r.DecoyCells); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(choices), &r.Choices); err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, r)
+	}
+	return rounds, rows.Err()
+}
+
+// SaveActiveBattle stores the live state of a character's fight, replacing any previous one.
+func (db *DB) SaveActiveBattle(b *models.ActiveBattle) error {
+	now := time.Now()
+	_, err := db.conn.Exec(
+		`INSERT INTO active_battles (char_id, kind, enemy_id, state, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(char_id) DO UPDATE SET kind = excluded.kind, enemy_id = excluded.enemy_id, state = excluded.state, updated_at = excluded.updated_at`,
+		b.CharID, string(b.Kind), b.EnemyID, b.State, now,
 	)
 	if err != nil {
 		return err
 	}
-	b.ID, _ = res.LastInsertId()
+	b.UpdatedAt = now
 	return nil
 }
 
+// GetActiveBattle returns the character's unfinished fight, or nil if there is none.
+func (db *DB) GetActiveBattle(charID int64) (*models.ActiveBattle, error) {
+	var b models.ActiveBattle
+	err := db.conn.QueryRow(
+		"SELECT char_id, kind, enemy_id, state, updated_at FROM active_battles WHERE char_id = ?",
+		charID,
+	).Scan(&b.CharID, &b.Kind, &b.EnemyID, &b.State, &b.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (db *DB) DeleteActiveBattle(charID int64) error {
+	return deleteActiveBattle(db.conn, charID)
+}
+
+func deleteActiveBattle(exec sqlExecer, charID int64) error {
+	_, err := exec.Exec("DELETE FROM active_battles WHERE char_id = ?", charID)
+	return err
+}
+
 func (db *DB) GetBattleHistory(charID int64, limit int) ([]models.BattleRecord, error) {
 	rows, err := db.conn.Query(
-		`SELECT id, char_id, enemy_id, enemy_name, result, damage_dealt, damage_taken, accuracy, critical_hits, dodges, fought_at
+		`SELECT id, char_id, enemy_id, enemy_name, result, damage_dealt, damage_taken, accuracy, critical_hits, dodges, seed, challenge_day, fought_at
 		FROM battles WHERE char_id = ? ORDER BY fought_at DESC LIMIT ?`,
 		charID, limit,
 	)
@@ -560,7 +885,7 @@ func (db *DB) GetBattleHistory(charID int64, limit int) ([]models.BattleRecord,
 	for rows.Next() {
 		var b models.BattleRecord
 		if err := rows.Scan(&b.ID, &b.CharID, &b.EnemyID, &b.EnemyName, &b.Result, &b.DamageDealt,
-			&b.DamageTaken, &b.Accuracy, &b.CriticalHits, &b.Dodges, &b.FoughtAt); err != nil {
+			&b.DamageTaken, &b.Accuracy, &b.CriticalHits, &b.Dodges, &b.Seed, &b.ChallengeDay, &b.FoughtAt); err != nil {
 			return nil, err
 		}
 		battles = append(battles, b)
@@ -568,6 +893,88 @@ func (db *DB) GetBattleHistory(charID int64, limit int) ([]models.BattleRecord,
 	return battles, nil
 }
 
+// InsertPracticeRun stores a finished practice fight.
+func (db *DB) InsertPracticeRun(r *models.PracticeRun) error {
+	now := time.Now()
+	res, err := db.conn.Exec(
+		`INSERT INTO practice_runs (char_id, enemy_id, enemy_name, grid_size, cells, result, accuracy, rounds, played_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.CharID, r.EnemyID, r.EnemyName, r.GridSize, r.Cells, string(r.Result), r.Accuracy, r.Rounds, now,
+	)
+	if err != nil {
+		return err
+	}
+	r.ID, _ = res.LastInsertId()
+	r.PlayedAt = now
+	return nil
+}
+
+// GetPracticeRuns returns the character's latest practice fights, newest first.
+func (db *DB) GetPracticeRuns(charID int64, limit int) ([]models.PracticeRun, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, char_id, enemy_id, enemy_name, grid_size, cells, result, accuracy, rounds, played_at
+		FROM practice_runs WHERE char_id = ? ORDER BY played_at DESC, id DESC LIMIT ?`,
+		charID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.PracticeRun
+	for rows.Next() {
+		var r models.PracticeRun
+		if err := rows.Scan(&r.ID, &r.CharID, &r.EnemyID, &r.EnemyName, &r.GridSize, &r.Cells,
+			&r.Result, &r.Accuracy, &r.Rounds, &r.PlayedAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// GetChallengeResult returns the character's daily challenge battle for day, or nil if not fought.
+func (db *DB) GetChallengeResult(charID int64, day string) (*models.BattleRecord, error) {
+	var b models.BattleRecord
+	err := db.conn.QueryRow(
+		`SELECT id, char_id, enemy_id, enemy_name, result, damage_dealt, damage_taken, accuracy, critical_hits, dodges, seed, challenge_day, fought_at
+		FROM battles WHERE char_id = ? AND challenge_day = ? ORDER BY fought_at DESC LIMIT 1`,
+		charID, day,
+	).Scan(&b.ID, &b.CharID, &b.EnemyID, &b.EnemyName, &b.Result, &b.DamageDealt,
+		&b.DamageTaken, &b.Accuracy, &b.CriticalHits, &b.Dodges, &b.Seed, &b.ChallengeDay, &b.FoughtAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetChallengeWinDays returns the days the character cleared the daily challenge, newest first.
+func (db *DB) GetChallengeWinDays(charID int64) ([]string, error) {
+	rows, err := db.conn.Query(
+		`SELECT DISTINCT challenge_day FROM battles
+		WHERE char_id = ? AND challenge_day != '' AND result = ?
+		ORDER BY challenge_day DESC`,
+		charID, string(models.BattleWin),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
 func (db *DB) GetBattleStats(charID int64) (*models.BattleStatistics, error) {
 	stats := &models.BattleStatistics{
 		EnemiesDefeated: make(map[string]int),
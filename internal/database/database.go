@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"solo-leveling/internal/config"
 )
 
 type DB struct {
@@ -14,12 +16,10 @@ type DB struct {
 }
 
 func New() (*DB, error) {
-	homeDir, err := os.UserHomeDir()
+	dbDir, err := config.DataDir()
 	if err != nil {
-		return nil, fmt.Errorf("get home dir: %w", err)
+		return nil, err
 	}
-
-	dbDir := filepath.Join(homeDir, ".solo-leveling")
 	if err := os.MkdirAll(dbDir, 0755); err != nil {
 		return nil, fmt.Errorf("create db dir: %w", err)
 	}
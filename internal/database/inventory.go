@@ -0,0 +1,84 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Inventory
+// ============================================================
+
+// InsertInventoryItem adds an item to the character's inventory.
+// Only the item key and slot are stored; effects come from the item catalog.
+func (db *DB) InsertInventoryItem(it *models.InventoryItem) error {
+	return insertInventoryItem(db.conn, it)
+}
+
+func insertInventoryItem(exec sqlExecer, it *models.InventoryItem) error {
+	now := time.Now()
+	res, err := exec.Exec(
+		`INSERT INTO inventory (char_id, item_key, slot, source, equipped, acquired_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		it.CharID, it.Item.Key, string(it.Item.Slot), it.Source, it.Equipped, now,
+	)
+	if err != nil {
+		return err
+	}
+	it.ID, _ = res.LastInsertId()
+	it.AcquiredAt = now
+	return nil
+}
+
+// GetInventory returns the character's items, newest first. Item carries only Key and Slot.
+func (db *DB) GetInventory(charID int64) ([]models.InventoryItem, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, char_id, item_key, slot, source, equipped, acquired_at
+		FROM inventory WHERE char_id = ? ORDER BY acquired_at DESC, id DESC`,
+		charID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.InventoryItem
+	for rows.Next() {
+		var it models.InventoryItem
+		if err := rows.Scan(&it.ID, &it.CharID, &it.Item.Key, &it.Item.Slot, &it.Source, &it.Equipped, &it.AcquiredAt); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// SetItemEquipped equips or unequips an item. Equipping takes off whatever
+// else the character wears in the same slot.
+func (db *DB) SetItemEquipped(charID, itemID int64, equipped bool) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var slot string
+	err = tx.QueryRow("SELECT slot FROM inventory WHERE id = ? AND char_id = ?", itemID, charID).Scan(&slot)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("предмет не найден")
+	}
+	if err != nil {
+		return err
+	}
+	if equipped {
+		if _, err := tx.Exec("UPDATE inventory SET equipped = 0 WHERE char_id = ? AND slot = ?", charID, slot); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("UPDATE inventory SET equipped = ? WHERE id = ?", equipped, itemID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
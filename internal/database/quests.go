@@ -232,6 +232,37 @@ func (db *DB) FailActiveQuestsByExpedition(charID int64, expeditionID int64) err
 	return err
 }
 
+// CompleteActiveQuestsByExpeditionTask closes active quests of a task that was
+// finished through quantity logging. EXP for those units is already awarded.
+func (db *DB) CompleteActiveQuestsByExpeditionTask(charID int64, taskID int64) error {
+	return completeActiveQuestsByExpeditionTask(db.conn, charID, taskID)
+}
+
+func completeActiveQuestsByExpeditionTask(exec sqlExecer, charID int64, taskID int64) error {
+	_, err := exec.Exec(
+		"UPDATE quests SET status = ?, completed_at = ? WHERE char_id = ? AND expedition_task_id = ? AND status = ?",
+		string(models.QuestCompleted),
+		time.Now(),
+		charID,
+		taskID,
+		string(models.QuestActive),
+	)
+	return err
+}
+
+// ArchiveActiveQuestsByExpedition closes active expedition quests without
+// counting them as failed.
+func (db *DB) ArchiveActiveQuestsByExpedition(charID int64, expeditionID int64) error {
+	_, err := db.conn.Exec(
+		"UPDATE quests SET status = ? WHERE char_id = ? AND expedition_id = ? AND status = ?",
+		string(models.QuestArchived),
+		charID,
+		expeditionID,
+		string(models.QuestActive),
+	)
+	return err
+}
+
 // ============================================================
 // Daily Quest Templates
 // ============================================================
@@ -316,24 +347,73 @@ func (db *DB) GetExpeditionCount() (int, error) {
 	return count, err
 }
 
+// InsertExpedition stores an expedition definition with its tasks atomically.
 func (db *DB) InsertExpedition(e *models.Expedition) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertExpedition(tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateExpedition stores an expedition definition, its tasks and the
+// character's first run in one transaction, so a failure leaves nothing behind.
+func (db *DB) CreateExpedition(charID int64, e *models.Expedition) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertExpedition(tx, e); err != nil {
+		return err
+	}
+	runID, err := db.insertExpeditionRun(tx, charID, e)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	e.RunID = runID
+	return nil
+}
+
+func insertExpedition(exec sqlExecer, e *models.Expedition) error {
 	rewardStats, err := marshalRewardStats(e.RewardStats)
 	if err != nil {
 		return err
 	}
+	rewards, err := marshalRewards(e.Rewards)
+	if err != nil {
+		return err
+	}
+	tiers, err := json.Marshal(e.Tiers)
+	if err != nil {
+		return err
+	}
 	if e.Status == "" {
 		e.Status = models.ExpeditionActive
 	}
 
-	res, err := db.conn.Exec(
-		"INSERT INTO expeditions (name, description, deadline, reward_exp, reward_stats, is_repeatable, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	// Only the definition is stored here; status and progress belong to runs
+	// (see CreateExpedition).
+	res, err := exec.Exec(
+		"INSERT INTO expeditions (name, description, deadline, reward_exp, reward_stats, rewards, tiers, is_repeatable, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		e.Name,
 		e.Description,
 		e.Deadline,
 		e.RewardEXP,
 		rewardStats,
+		rewards,
+		string(tiers),
 		boolToSQLiteInt(e.IsRepeatable),
-		string(e.Status),
+		string(models.ExpeditionActive),
 		time.Now(),
 		time.Now(),
 	)
@@ -355,23 +435,28 @@ func (db *DB) InsertExpedition(e *models.Expedition) error {
 			t.TargetStat = models.StatStrength
 		}
 		if t.RewardEXP <= 0 {
-			t.RewardEXP = 20
+			t.RewardEXP = models.DefaultTaskRewardEXP
 		}
 		if t.ProgressCurrent >= t.ProgressTarget {
 			t.IsCompleted = true
 			t.ProgressCurrent = t.ProgressTarget
 		}
+		taskRewards, err := marshalRewards(t.Rewards)
+		if err != nil {
+			return err
+		}
 
-		resTask, err := db.conn.Exec(
-			"INSERT INTO expedition_tasks (expedition_id, title, description, is_completed, progress_current, progress_target, reward_exp, target_stat, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		resTask, err := exec.Exec(
+			"INSERT INTO expedition_tasks (expedition_id, title, description, is_completed, progress_current, progress_target, reward_exp, target_stat, rewards, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			t.ExpeditionID,
 			t.Title,
 			t.Description,
-			boolToSQLiteInt(t.IsCompleted),
-			t.ProgressCurrent,
+			0,
+			0,
 			t.ProgressTarget,
 			t.RewardEXP,
 			string(t.TargetStat),
+			taskRewards,
 			time.Now(),
 			time.Now(),
 		)
@@ -384,10 +469,22 @@ func (db *DB) InsertExpedition(e *models.Expedition) error {
 	return nil
 }
 
-func (db *DB) GetAllExpeditions() ([]models.Expedition, error) {
-	rows, err := db.conn.Query(
-		"SELECT id, name, description, deadline, reward_exp, reward_stats, is_repeatable, status, created_at, updated_at FROM expeditions ORDER BY id",
-	)
+// Expedition definitions (expeditions, expedition_tasks) are a catalog shared by
+// all characters. Status, deadline and task progress live in runs owned by a
+// character; the latest run for a (character, expedition) pair is the current one.
+
+const expeditionSelect = `SELECT e.id, e.name, e.description, e.deadline, e.reward_exp, e.reward_stats, e.rewards, e.tiers, e.is_repeatable, e.created_at, e.updated_at,
+		r.id, r.status, r.deadline, r.updated_at, r.paused_at, r.extensions, r.started_at
+	FROM expeditions e
+	LEFT JOIN expedition_runs r ON r.id = (SELECT MAX(id) FROM expedition_runs WHERE char_id = ? AND expedition_id = e.id)`
+
+const expeditionTaskSelect = `SELECT t.id, t.expedition_id, t.title, t.description, rt.is_completed, rt.progress_current, t.progress_target, t.reward_exp, t.target_stat, t.rewards, t.created_at, t.updated_at, rt.updated_at
+	FROM expedition_tasks t
+	LEFT JOIN expedition_run_tasks rt ON rt.task_id = t.id
+		AND rt.run_id = (SELECT MAX(id) FROM expedition_runs WHERE char_id = ? AND expedition_id = t.expedition_id)`
+
+func (db *DB) GetAllExpeditions(charID int64) ([]models.Expedition, error) {
+	rows, err := db.conn.Query(expeditionSelect+" ORDER BY e.id", charID)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +496,7 @@ func (db *DB) GetAllExpeditions() ([]models.Expedition, error) {
 		if err != nil {
 			return nil, err
 		}
-		tasks, err := db.GetExpeditionTasks(e.ID)
+		tasks, err := db.GetExpeditionTasks(charID, e.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -409,11 +506,8 @@ func (db *DB) GetAllExpeditions() ([]models.Expedition, error) {
 	return expeditions, nil
 }
 
-func (db *DB) GetExpeditionByID(expeditionID int64) (*models.Expedition, error) {
-	rows, err := db.conn.Query(
-		"SELECT id, name, description, deadline, reward_exp, reward_stats, is_repeatable, status, created_at, updated_at FROM expeditions WHERE id = ?",
-		expeditionID,
-	)
+func (db *DB) GetExpeditionByID(charID int64, expeditionID int64) (*models.Expedition, error) {
+	rows, err := db.conn.Query(expeditionSelect+" WHERE e.id = ?", charID, expeditionID)
 	if err != nil {
 		return nil, err
 	}
@@ -425,7 +519,7 @@ func (db *DB) GetExpeditionByID(expeditionID int64) (*models.Expedition, error)
 	if err != nil {
 		return nil, err
 	}
-	tasks, err := db.GetExpeditionTasks(e.ID)
+	tasks, err := db.GetExpeditionTasks(charID, e.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -437,7 +531,16 @@ func scanExpeditionRow(rows *sql.Rows) (*models.Expedition, error) {
 	var e models.Expedition
 	var deadline sql.NullTime
 	var rewardStatsRaw string
+	var rewardsRaw string
+	var tiersRaw string
 	var isRepeatable int
+	var runID sql.NullInt64
+	var runStatus sql.NullString
+	var runDeadline sql.NullTime
+	var runUpdatedAt sql.NullTime
+	var pausedAt sql.NullTime
+	var extensions sql.NullInt64
+	var startedAt sql.NullTime
 	if err := rows.Scan(
 		&e.ID,
 		&e.Name,
@@ -445,13 +548,39 @@ func scanExpeditionRow(rows *sql.Rows) (*models.Expedition, error) {
 		&deadline,
 		&e.RewardEXP,
 		&rewardStatsRaw,
+		&rewardsRaw,
+		&tiersRaw,
 		&isRepeatable,
-		&e.Status,
 		&e.CreatedAt,
 		&e.UpdatedAt,
+		&runID,
+		&runStatus,
+		&runDeadline,
+		&runUpdatedAt,
+		&pausedAt,
+		&extensions,
+		&startedAt,
 	); err != nil {
 		return nil, err
 	}
+	e.Status = models.ExpeditionActive
+	if runID.Valid {
+		e.RunID = runID.Int64
+		e.Status = models.ExpeditionStatus(runStatus.String)
+		deadline = runDeadline
+		if runUpdatedAt.Valid {
+			e.UpdatedAt = runUpdatedAt.Time
+		}
+		if pausedAt.Valid {
+			t := pausedAt.Time
+			e.PausedAt = &t
+		}
+		e.Extensions = int(extensions.Int64)
+		if startedAt.Valid {
+			t := startedAt.Time
+			e.StartedAt = &t
+		}
+	}
 	if deadline.Valid {
 		t := deadline.Time
 		e.Deadline = &t
@@ -462,14 +591,19 @@ func scanExpeditionRow(rows *sql.Rows) (*models.Expedition, error) {
 		return nil, err
 	}
 	e.RewardStats = rewardStats
+	if e.Rewards, err = unmarshalRewards(rewardsRaw); err != nil {
+		return nil, err
+	}
+	if tiersRaw != "" {
+		if err := json.Unmarshal([]byte(tiersRaw), &e.Tiers); err != nil {
+			return nil, err
+		}
+	}
 	return &e, nil
 }
 
-func (db *DB) GetExpeditionTasks(expeditionID int64) ([]models.ExpeditionTask, error) {
-	rows, err := db.conn.Query(
-		"SELECT id, expedition_id, title, description, is_completed, progress_current, progress_target, reward_exp, target_stat, created_at, updated_at FROM expedition_tasks WHERE expedition_id = ? ORDER BY id",
-		expeditionID,
-	)
+func (db *DB) GetExpeditionTasks(charID int64, expeditionID int64) ([]models.ExpeditionTask, error) {
+	rows, err := db.conn.Query(expeditionTaskSelect+" WHERE t.expedition_id = ? ORDER BY t.id", charID, expeditionID)
 	if err != nil {
 		return nil, err
 	}
@@ -486,11 +620,8 @@ func (db *DB) GetExpeditionTasks(expeditionID int64) ([]models.ExpeditionTask, e
 	return tasks, nil
 }
 
-func (db *DB) GetExpeditionTaskByID(taskID int64) (*models.ExpeditionTask, error) {
-	rows, err := db.conn.Query(
-		"SELECT id, expedition_id, title, description, is_completed, progress_current, progress_target, reward_exp, target_stat, created_at, updated_at FROM expedition_tasks WHERE id = ?",
-		taskID,
-	)
+func (db *DB) GetExpeditionTaskByID(charID int64, taskID int64) (*models.ExpeditionTask, error) {
+	rows, err := db.conn.Query(expeditionTaskSelect+" WHERE t.id = ?", charID, taskID)
 	if err != nil {
 		return nil, err
 	}
@@ -503,34 +634,48 @@ func (db *DB) GetExpeditionTaskByID(taskID int64) (*models.ExpeditionTask, error
 
 func scanExpeditionTaskRow(rows *sql.Rows) (*models.ExpeditionTask, error) {
 	var t models.ExpeditionTask
-	var completed int
+	var completed sql.NullInt64
+	var current sql.NullInt64
+	var runUpdatedAt sql.NullTime
+	var rewardsRaw string
 	if err := rows.Scan(
 		&t.ID,
 		&t.ExpeditionID,
 		&t.Title,
 		&t.Description,
 		&completed,
-		&t.ProgressCurrent,
+		&current,
 		&t.ProgressTarget,
 		&t.RewardEXP,
 		&t.TargetStat,
+		&rewardsRaw,
 		&t.CreatedAt,
 		&t.UpdatedAt,
+		&runUpdatedAt,
 	); err != nil {
 		return nil, err
 	}
+	rewards, err := unmarshalRewards(rewardsRaw)
+	if err != nil {
+		return nil, err
+	}
+	t.Rewards = rewards
+	t.ProgressCurrent = int(current.Int64)
+	if runUpdatedAt.Valid {
+		t.UpdatedAt = runUpdatedAt.Time
+	}
 	if t.ProgressTarget <= 0 {
 		t.ProgressTarget = 1
 	}
 	if t.ProgressCurrent < 0 {
 		t.ProgressCurrent = 0
 	}
-	t.IsCompleted = completed == 1 || t.ProgressCurrent >= t.ProgressTarget
+	t.IsCompleted = completed.Int64 == 1 || t.ProgressCurrent >= t.ProgressTarget
 	if t.IsCompleted && t.ProgressCurrent < t.ProgressTarget {
 		t.ProgressCurrent = t.ProgressTarget
 	}
 	if t.RewardEXP <= 0 {
-		t.RewardEXP = 20
+		t.RewardEXP = models.DefaultTaskRewardEXP
 	}
 	if t.TargetStat == "" {
 		t.TargetStat = models.StatStrength
@@ -538,8 +683,8 @@ func scanExpeditionTaskRow(rows *sql.Rows) (*models.ExpeditionTask, error) {
 	return &t, nil
 }
 
-func (db *DB) IncrementExpeditionTaskProgress(taskID int64, delta int) (*models.ExpeditionTask, error) {
-	task, err := db.GetExpeditionTaskByID(taskID)
+func (db *DB) IncrementExpeditionTaskProgress(charID int64, taskID int64, delta int) (*models.ExpeditionTask, error) {
+	task, err := db.GetExpeditionTaskByID(charID, taskID)
 	if err != nil {
 		return nil, err
 	}
@@ -556,22 +701,173 @@ func (db *DB) IncrementExpeditionTaskProgress(taskID int64, delta int) (*models.
 		next = task.ProgressTarget
 	}
 
-	_, err = db.conn.Exec(
-		"UPDATE expedition_tasks SET progress_current = ?, is_completed = ?, updated_at = ? WHERE id = ?",
-		next,
-		boolToSQLiteInt(completed),
-		time.Now(),
+	runID, err := db.ensureExpeditionRun(charID, task.ExpeditionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.setExpeditionRunTask(db.conn, runID, taskID, next, completed); err != nil {
+		return nil, err
+	}
+	return db.GetExpeditionTaskByID(charID, taskID)
+}
+
+// InsertExpeditionTaskLog records the entry against the character's current run
+// and credits its EXP to that run.
+func (db *DB) InsertExpeditionTaskLog(entry *models.ExpeditionTaskLogEntry) error {
+	runID, err := db.ensureExpeditionRun(entry.CharID, entry.ExpeditionID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertExpeditionTaskLog(tx, runID, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertExpeditionTaskLog writes the log entry and credits its EXP to runID.
+func insertExpeditionTaskLog(exec sqlExecer, runID int64, entry *models.ExpeditionTaskLogEntry) error {
+	now := time.Now()
+	res, err := exec.Exec(
+		"INSERT INTO expedition_task_log (char_id, expedition_id, run_id, task_id, quantity, exp_awarded, logged_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		entry.CharID,
+		entry.ExpeditionID,
+		runID,
+		entry.TaskID,
+		entry.Quantity,
+		entry.EXPAwarded,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.Exec(
+		"UPDATE expedition_runs SET exp_earned = exp_earned + ?, updated_at = ? WHERE id = ?",
+		entry.EXPAwarded, now, runID,
+	); err != nil {
+		return err
+	}
+	entry.ID, _ = res.LastInsertId()
+	entry.RunID = runID
+	entry.LoggedAt = now
+	return nil
+}
+
+// ExpeditionTaskProgress bundles everything LogExpeditionTaskProgress writes so
+// it can be applied in one transaction.
+type ExpeditionTaskProgress struct {
+	Entry models.ExpeditionTaskLogEntry
+	// Progress and Completed are the task's new state in the current run.
+	Progress  int
+	Completed bool
+	// Stat receives the awarded EXP; nil when nothing was awarded.
+	Stat *models.StatLevel
+}
+
+// LogExpeditionTaskProgress moves a task forward, pays its EXP, records the log
+// entry and the day's activity and, once the task is done, closes its quests,
+// all atomically.
+func (db *DB) LogExpeditionTaskProgress(p *ExpeditionTaskProgress) error {
+	entry := &p.Entry
+	runID, err := db.ensureExpeditionRun(entry.CharID, entry.ExpeditionID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := db.setExpeditionRunTask(tx, runID, entry.TaskID, p.Progress, p.Completed); err != nil {
+		return err
+	}
+	if p.Stat != nil {
+		if _, err := tx.Exec(
+			"UPDATE stat_levels SET level = ?, current_exp = ?, total_exp = ? WHERE id = ?",
+			p.Stat.Level, p.Stat.CurrentEXP, p.Stat.TotalEXP, p.Stat.ID,
+		); err != nil {
+			return err
+		}
+	}
+	if err := insertExpeditionTaskLog(tx, runID, entry); err != nil {
+		return err
+	}
+	if err := recordDailyActivity(tx, entry.CharID, 0, 0, entry.EXPAwarded); err != nil {
+		return err
+	}
+	if p.Completed {
+		if err := completeActiveQuestsByExpeditionTask(tx, entry.CharID, entry.TaskID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (db *DB) GetExpeditionTaskLog(charID int64, taskID int64) ([]models.ExpeditionTaskLogEntry, error) {
+	rows, err := db.conn.Query(
+		"SELECT id, char_id, expedition_id, run_id, task_id, quantity, exp_awarded, logged_at FROM expedition_task_log WHERE char_id = ? AND task_id = ? ORDER BY logged_at DESC, id DESC",
+		charID,
 		taskID,
 	)
 	if err != nil {
 		return nil, err
 	}
-	return db.GetExpeditionTaskByID(taskID)
+	defer rows.Close()
+
+	var entries []models.ExpeditionTaskLogEntry
+	for rows.Next() {
+		var entry models.ExpeditionTaskLogEntry
+		if err := rows.Scan(&entry.ID, &entry.CharID, &entry.ExpeditionID, &entry.RunID, &entry.TaskID, &entry.Quantity, &entry.EXPAwarded, &entry.LoggedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetExpeditionDailyProgress sums logged units per local day since the given time.
+func (db *DB) GetExpeditionDailyProgress(charID int64, expeditionID int64, since time.Time) ([]models.DailyProgress, error) {
+	rows, err := db.conn.Query(
+		"SELECT quantity, logged_at FROM expedition_task_log WHERE char_id = ? AND expedition_id = ? AND logged_at >= ? ORDER BY logged_at",
+		charID,
+		expeditionID,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []models.DailyProgress
+	for rows.Next() {
+		var quantity int
+		var loggedAt time.Time
+		if err := rows.Scan(&quantity, &loggedAt); err != nil {
+			return nil, err
+		}
+		local := loggedAt.Local()
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+		if n := len(days); n > 0 && days[n-1].Date.Equal(day) {
+			days[n-1].Units += quantity
+			continue
+		}
+		days = append(days, models.DailyProgress{Date: day, Units: quantity})
+	}
+	return days, rows.Err()
 }
 
-func (db *DB) FindNextIncompleteExpeditionTaskByTitle(expeditionID int64, title string) (*models.ExpeditionTask, error) {
+func (db *DB) FindNextIncompleteExpeditionTaskByTitle(charID int64, expeditionID int64, title string) (*models.ExpeditionTask, error) {
 	rows, err := db.conn.Query(
-		"SELECT id, expedition_id, title, description, is_completed, progress_current, progress_target, reward_exp, target_stat, created_at, updated_at FROM expedition_tasks WHERE expedition_id = ? AND title = ? AND is_completed = 0 ORDER BY id LIMIT 1",
+		expeditionTaskSelect+" WHERE t.expedition_id = ? AND t.title = ? AND COALESCE(rt.is_completed, 0) = 0 ORDER BY t.id LIMIT 1",
+		charID,
 		expeditionID,
 		title,
 	)
@@ -585,38 +881,344 @@ func (db *DB) FindNextIncompleteExpeditionTaskByTitle(expeditionID int64, title
 	return scanExpeditionTaskRow(rows)
 }
 
-func (db *DB) ResetExpeditionTasks(expeditionID int64) error {
+// ============================================================
+// Expedition Runs
+// ============================================================
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// CurrentExpeditionRunID returns the latest run of an expedition for a character, or 0.
+func (db *DB) CurrentExpeditionRunID(charID int64, expeditionID int64) (int64, error) {
+	var runID sql.NullInt64
+	err := db.conn.QueryRow(
+		"SELECT MAX(id) FROM expedition_runs WHERE char_id = ? AND expedition_id = ?",
+		charID,
+		expeditionID,
+	).Scan(&runID)
+	if err != nil {
+		return 0, err
+	}
+	return runID.Int64, nil
+}
+
+func (db *DB) ensureExpeditionRun(charID int64, expeditionID int64) (int64, error) {
+	runID, err := db.CurrentExpeditionRunID(charID, expeditionID)
+	if err != nil || runID > 0 {
+		return runID, err
+	}
+	return db.StartExpeditionRun(charID, expeditionID)
+}
+
+// StartExpeditionRun opens a fresh active run with zero progress and the
+// definition deadline. Earlier runs are kept untouched.
+func (db *DB) StartExpeditionRun(charID int64, expeditionID int64) (int64, error) {
+	now := time.Now()
+	res, err := db.conn.Exec(
+		`INSERT INTO expedition_runs (char_id, expedition_id, status, deadline, started_at, updated_at)
+		 SELECT ?, id, ?, deadline, ?, ? FROM expeditions WHERE id = ?`,
+		charID,
+		string(models.ExpeditionActive),
+		now,
+		now,
+		expeditionID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, fmt.Errorf("expedition not found: %d", expeditionID)
+	}
+	return res.LastInsertId()
+}
+
+// insertExpeditionRun stores the status, deadline and task progress carried by
+// an expedition model (e.g. from an import) as a new run for the character.
+func (db *DB) insertExpeditionRun(exec sqlExecer, charID int64, e *models.Expedition) (int64, error) {
+	status := e.Status
+	if status == "" {
+		status = models.ExpeditionActive
+	}
+	now := time.Now()
+	var endedAt *time.Time
+	if status != models.ExpeditionActive {
+		endedAt = &now
+	}
+	res, err := exec.Exec(
+		"INSERT INTO expedition_runs (char_id, expedition_id, status, deadline, started_at, ended_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		charID,
+		e.ID,
+		string(status),
+		e.Deadline,
+		now,
+		endedAt,
+		now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	runID, _ := res.LastInsertId()
+
+	for _, t := range e.Tasks {
+		if t.ProgressCurrent <= 0 && !t.IsCompleted {
+			continue
+		}
+		if err := db.setExpeditionRunTask(exec, runID, t.ID, t.ProgressCurrent, t.IsCompleted); err != nil {
+			return 0, err
+		}
+	}
+	return runID, nil
+}
+
+func (db *DB) setExpeditionRunTask(exec sqlExecer, runID int64, taskID int64, progress int, completed bool) error {
+	_, err := exec.Exec(
+		`INSERT INTO expedition_run_tasks (run_id, task_id, progress_current, is_completed, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(run_id, task_id) DO UPDATE SET
+			progress_current = excluded.progress_current,
+			is_completed = excluded.is_completed,
+			updated_at = excluded.updated_at`,
+		runID,
+		taskID,
+		progress,
+		boolToSQLiteInt(completed),
+		time.Now(),
+	)
+	return err
+}
+
+func (db *DB) PauseExpeditionRun(runID int64, at time.Time) error {
 	_, err := db.conn.Exec(
-		"UPDATE expedition_tasks SET is_completed = 0, progress_current = 0, updated_at = ? WHERE expedition_id = ?",
+		"UPDATE expedition_runs SET status = ?, paused_at = ?, updated_at = ? WHERE id = ?",
+		string(models.ExpeditionPaused),
+		at,
 		time.Now(),
-		expeditionID,
+		runID,
 	)
 	return err
 }
 
-func (db *DB) UpdateExpeditionStatus(expeditionID int64, status models.ExpeditionStatus) error {
+// ResumeExpeditionRun reactivates a paused run with its shifted deadline.
+func (db *DB) ResumeExpeditionRun(runID int64, deadline *time.Time) error {
 	_, err := db.conn.Exec(
-		"UPDATE expeditions SET status = ?, updated_at = ? WHERE id = ?",
-		string(status),
+		"UPDATE expedition_runs SET status = ?, paused_at = NULL, deadline = ?, updated_at = ? WHERE id = ?",
+		string(models.ExpeditionActive),
+		deadline,
 		time.Now(),
-		expeditionID,
+		runID,
 	)
 	return err
 }
 
-func (db *DB) CompleteExpedition(charID int64, expeditionID int64) error {
+func (db *DB) ExtendExpeditionRunDeadline(runID int64, deadline time.Time) error {
 	_, err := db.conn.Exec(
-		"INSERT INTO completed_expeditions (char_id, expedition_id, completed_at) VALUES (?, ?, ?)",
-		charID,
-		expeditionID,
+		"UPDATE expedition_runs SET deadline = ?, extensions = extensions + 1, updated_at = ? WHERE id = ?",
+		deadline,
 		time.Now(),
+		runID,
 	)
 	return err
 }
 
+func (db *DB) UpdateExpeditionStatus(charID int64, expeditionID int64, status models.ExpeditionStatus) error {
+	runID, err := db.ensureExpeditionRun(charID, expeditionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var endedAt *time.Time
+	if status != models.ExpeditionActive {
+		endedAt = &now
+	}
+	_, err = db.conn.Exec(
+		"UPDATE expedition_runs SET status = ?, ended_at = ?, updated_at = ? WHERE id = ?",
+		string(status),
+		endedAt,
+		now,
+		runID,
+	)
+	return err
+}
+
+// ExpeditionCompletion bundles everything CompleteExpedition writes so it can
+// be applied in one transaction.
+type ExpeditionCompletion struct {
+	CharID       int64
+	ExpeditionID int64
+	Stats        []models.StatLevel
+	Rewards      []models.Reward
+	Tier         models.ExpeditionTier
+	// EXPEarned is the total EXP paid out on completion, credited to the run.
+	EXPEarned int
+	// AttemptReserveCap banks attempt rewards above MaxAttempts; 0 drops the overflow.
+	AttemptReserveCap int
+	// Drop is the item granted with the completion, if any.
+	Drop *models.InventoryItem
+}
+
+// CompleteExpedition stores stat gains, marks the current run completed, records
+// the completion and grants/logs every reward atomically.
+func (db *DB) CompleteExpedition(c ExpeditionCompletion) error {
+	runID, err := db.ensureExpeditionRun(c.CharID, c.ExpeditionID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tier := c.Tier
+	if tier == models.TierNone {
+		tier = models.TierGold
+	}
+	now := time.Now()
+	for _, stat := range c.Stats {
+		if _, err := tx.Exec(
+			"UPDATE stat_levels SET level = ?, current_exp = ?, total_exp = ? WHERE id = ?",
+			stat.Level, stat.CurrentEXP, stat.TotalEXP, stat.ID,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(
+		"UPDATE expedition_runs SET status = ?, tier = ?, exp_earned = exp_earned + ?, ended_at = ?, updated_at = ? WHERE id = ?",
+		string(models.ExpeditionCompleted), string(tier), c.EXPEarned, now, now, runID,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO completed_expeditions (char_id, expedition_id, tier, completed_at) VALUES (?, ?, ?, ?)",
+		c.CharID, c.ExpeditionID, string(tier), now,
+	); err != nil {
+		return err
+	}
+
+	for _, r := range c.Rewards {
+		if r.Kind == models.RewardAttempts && r.Amount > 0 {
+			if err := addAttemptsWithReserve(tx, c.CharID, r.Amount, c.AttemptReserveCap); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(
+			"INSERT INTO expedition_reward_log (char_id, expedition_id, run_id, kind, value, amount, granted_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			c.CharID, c.ExpeditionID, runID, string(r.Kind), r.Value, r.Amount, now,
+		); err != nil {
+			return err
+		}
+	}
+	if c.Drop != nil {
+		if err := insertInventoryItem(tx, c.Drop); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetGrantedRewards returns expedition rewards granted to a character, optionally
+// filtered by kind (empty kind returns all).
+func (db *DB) GetGrantedRewards(charID int64, kind models.RewardKind) ([]models.GrantedReward, error) {
+	query := "SELECT id, char_id, expedition_id, run_id, kind, value, amount, granted_at FROM expedition_reward_log WHERE char_id = ?"
+	args := []any{charID}
+	if kind != "" {
+		query += " AND kind = ?"
+		args = append(args, string(kind))
+	}
+	rows, err := db.conn.Query(query+" ORDER BY granted_at, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.GrantedReward
+	for rows.Next() {
+		var g models.GrantedReward
+		if err := rows.Scan(&g.ID, &g.CharID, &g.ExpeditionID, &g.RunID, &g.Reward.Kind, &g.Reward.Value, &g.Reward.Amount, &g.GrantedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, g)
+	}
+	return results, rows.Err()
+}
+
+// GetExpeditionRuns returns every run of a character at an expedition, newest
+// first, with the per-task results of each run.
+func (db *DB) GetExpeditionRuns(charID int64, expeditionID int64) ([]models.ExpeditionRun, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, char_id, expedition_id, status, tier, deadline, started_at, ended_at, extensions, exp_earned
+		FROM expedition_runs WHERE char_id = ? AND expedition_id = ? ORDER BY id DESC`,
+		charID,
+		expeditionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.ExpeditionRun
+	for rows.Next() {
+		var r models.ExpeditionRun
+		var deadline, endedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.CharID, &r.ExpeditionID, &r.Status, &r.Tier, &deadline, &r.StartedAt, &endedAt, &r.Extensions, &r.EXPEarned); err != nil {
+			return nil, err
+		}
+		if deadline.Valid {
+			t := deadline.Time
+			r.Deadline = &t
+		}
+		if endedAt.Valid {
+			t := endedAt.Time
+			r.EndedAt = &t
+		}
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range runs {
+		tasks, err := db.getExpeditionRunTasks(runs[i].ID, expeditionID)
+		if err != nil {
+			return nil, err
+		}
+		runs[i].Tasks = tasks
+	}
+	return runs, nil
+}
+
+func (db *DB) getExpeditionRunTasks(runID int64, expeditionID int64) ([]models.ExpeditionRunTask, error) {
+	rows, err := db.conn.Query(
+		`SELECT t.id, t.title, COALESCE(rt.progress_current, 0), t.progress_target, COALESCE(rt.is_completed, 0)
+		FROM expedition_tasks t
+		LEFT JOIN expedition_run_tasks rt ON rt.task_id = t.id AND rt.run_id = ?
+		WHERE t.expedition_id = ?
+		ORDER BY t.id`,
+		runID,
+		expeditionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.ExpeditionRunTask
+	for rows.Next() {
+		var task models.ExpeditionRunTask
+		if err := rows.Scan(&task.TaskID, &task.Title, &task.ProgressCurrent, &task.ProgressTarget, &task.IsCompleted); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
 func (db *DB) GetCompletedExpeditions(charID int64) ([]models.CompletedExpedition, error) {
 	rows, err := db.conn.Query(
-		"SELECT id, char_id, expedition_id, completed_at FROM completed_expeditions WHERE char_id = ? ORDER BY completed_at DESC",
+		"SELECT id, char_id, expedition_id, tier, completed_at FROM completed_expeditions WHERE char_id = ? ORDER BY completed_at DESC, id DESC",
 		charID,
 	)
 	if err != nil {
@@ -627,7 +1229,7 @@ func (db *DB) GetCompletedExpeditions(charID int64) ([]models.CompletedExpeditio
 	var results []models.CompletedExpedition
 	for rows.Next() {
 		var c models.CompletedExpedition
-		if err := rows.Scan(&c.ID, &c.CharID, &c.ExpeditionID, &c.CompletedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.CharID, &c.ExpeditionID, &c.Tier, &c.CompletedAt); err != nil {
 			return nil, err
 		}
 		results = append(results, c)
@@ -675,6 +1277,28 @@ func unmarshalRewardStats(raw string) (map[models.StatType]int, error) {
 	return result, nil
 }
 
+func marshalRewards(rewards []models.Reward) (string, error) {
+	if len(rewards) == 0 {
+		return "[]", nil
+	}
+	b, err := json.Marshal(rewards)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func unmarshalRewards(raw string) ([]models.Reward, error) {
+	if raw == "" || raw == "[]" {
+		return nil, nil
+	}
+	var rewards []models.Reward
+	if err := json.Unmarshal([]byte(raw), &rewards); err != nil {
+		return nil, err
+	}
+	return rewards, nil
+}
+
 func boolToSQLiteInt(v bool) int {
 	if v {
 		return 1
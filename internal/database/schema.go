@@ -1,6 +1,9 @@
 package database
 
-import "fmt"
+import (
+	"database/sql"
+	"fmt"
+)
 
 func (db *DB) migrate() error {
 	schema := `
@@ -142,6 +145,53 @@ func (db *DB) migrate() error {
 			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 		);
 
+		CREATE TABLE IF NOT EXISTS expedition_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			char_id INTEGER NOT NULL REFERENCES character(id),
+			expedition_id INTEGER NOT NULL REFERENCES expeditions(id),
+			status TEXT NOT NULL DEFAULT 'active',
+			deadline DATETIME,
+			started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			ended_at DATETIME,
+			paused_at DATETIME,
+			extensions INTEGER NOT NULL DEFAULT 0,
+			tier TEXT NOT NULL DEFAULT '',
+			exp_earned INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_expedition_runs_char ON expedition_runs(char_id, expedition_id);
+
+		CREATE TABLE IF NOT EXISTS expedition_run_tasks (
+			run_id INTEGER NOT NULL REFERENCES expedition_runs(id),
+			task_id INTEGER NOT NULL REFERENCES expedition_tasks(id),
+			progress_current INTEGER NOT NULL DEFAULT 0,
+			is_completed INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (run_id, task_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS expedition_task_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			char_id INTEGER NOT NULL REFERENCES character(id),
+			expedition_id INTEGER NOT NULL REFERENCES expeditions(id),
+			run_id INTEGER NOT NULL DEFAULT 0,
+			task_id INTEGER NOT NULL REFERENCES expedition_tasks(id),
+			quantity INTEGER NOT NULL DEFAULT 0,
+			exp_awarded INTEGER NOT NULL DEFAULT 0,
+			logged_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS expedition_reward_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			char_id INTEGER NOT NULL REFERENCES character(id),
+			expedition_id INTEGER NOT NULL REFERENCES expeditions(id),
+			run_id INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			value TEXT NOT NULL DEFAULT '',
+			amount INTEGER NOT NULL DEFAULT 0,
+			granted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
 		CREATE TABLE IF NOT EXISTS completed_expeditions (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			char_id INTEGER NOT NULL REFERENCES character(id),
@@ -165,11 +215,13 @@ func (db *DB) migrate() error {
 		role TEXT NOT NULL DEFAULT 'NORMAL',
 		is_transition INTEGER NOT NULL DEFAULT 0,
 		target_winrate_min REAL NOT NULL DEFAULT 0,
-		target_winrate_max REAL NOT NULL DEFAULT 0
+		target_winrate_max REAL NOT NULL DEFAULT 0,
+		image TEXT NOT NULL DEFAULT '',
+		endless INTEGER NOT NULL DEFAULT 0,
+		char_id INTEGER NOT NULL DEFAULT 0,
+		boss_phases TEXT NOT NULL DEFAULT '[]'
 	);
 
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_enemies_name_unique ON enemies(name);
-
 	CREATE TABLE IF NOT EXISTS streak_titles (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		char_id INTEGER NOT NULL REFERENCES character(id),
@@ -194,6 +246,8 @@ func (db *DB) migrate() error {
 		accuracy REAL NOT NULL DEFAULT 0.0,
 		critical_hits INTEGER NOT NULL DEFAULT 0,
 		dodges INTEGER NOT NULL DEFAULT 0,
+		seed INTEGER NOT NULL DEFAULT 0,
+		challenge_day TEXT NOT NULL DEFAULT '',
 		fought_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 	);
 
@@ -201,6 +255,57 @@ func (db *DB) migrate() error {
 	
 	
 	
+	CREATE TABLE IF NOT EXISTS battle_rounds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		battle_id INTEGER NOT NULL REFERENCES battles(id),
+		round INTEGER NOT NULL,
+		minigame TEXT NOT NULL DEFAULT 'grid_memory',
+		grid_size INTEGER NOT NULL,
+		shown_cells TEXT NOT NULL DEFAULT '[]',
+		decoy_cells TEXT NOT NULL DEFAULT '[]',
+		choices TEXT NOT NULL DEFAULT '[]',
+		accuracy REAL NOT NULL DEFAULT 0.0,
+		damage_dealt INTEGER NOT NULL DEFAULT 0,
+		damage_taken INTEGER NOT NULL DEFAULT 0,
+		is_crit INTEGER NOT NULL DEFAULT 0,
+		show_time_ms INTEGER NOT NULL DEFAULT 0,
+		duration_ms INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_battle_rounds_battle ON battle_rounds(battle_id, round);
+
+	CREATE TABLE IF NOT EXISTS practice_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		char_id INTEGER NOT NULL REFERENCES character(id),
+		enemy_id INTEGER NOT NULL DEFAULT 0,
+		enemy_name TEXT NOT NULL DEFAULT '',
+		grid_size INTEGER NOT NULL DEFAULT 0,
+		cells INTEGER NOT NULL DEFAULT 0,
+		result TEXT NOT NULL DEFAULT 'lose',
+		accuracy REAL NOT NULL DEFAULT 0.0,
+		rounds INTEGER NOT NULL DEFAULT 0,
+		played_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_practice_runs_char ON practice_runs(char_id, played_at);
+
+	CREATE TABLE IF NOT EXISTS inventory (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		char_id INTEGER NOT NULL REFERENCES character(id),
+		item_key TEXT NOT NULL,
+		slot TEXT NOT NULL,
+		source TEXT NOT NULL DEFAULT '',
+		equipped INTEGER NOT NULL DEFAULT 0,
+		acquired_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_inventory_char ON inventory(char_id, slot);
+
+	CREATE TABLE IF NOT EXISTS active_battles (
+		char_id INTEGER PRIMARY KEY REFERENCES character(id),
+		kind TEXT NOT NULL,
+		enemy_id INTEGER NOT NULL,
+		state TEXT NOT NULL,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
 	CREATE TABLE IF NOT EXISTS enemy_unlocks (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		char_id INTEGER NOT NULL REFERENCES character(id),
@@ -215,6 +320,7 @@ func (db *DB) migrate() error {
 		enemy_id INTEGER NOT NULL REFERENCES enemies(id),
 		title TEXT NOT NULL DEFAULT '',
 		badge TEXT NOT NULL DEFAULT '',
+		cosmetic TEXT NOT NULL DEFAULT '',
 		awarded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		UNIQUE(char_id, enemy_id)
 	);
@@ -265,6 +371,43 @@ func (db *DB) migrate() error {
 	if err := db.migrateQuestDungeonLinksToExpeditions(); err != nil {
 		return err
 	}
+	if err := db.migrateExpeditionStateToRuns(); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("expeditions", "rewards", "TEXT NOT NULL DEFAULT '[]'"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("expedition_tasks", "rewards", "TEXT NOT NULL DEFAULT '[]'"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("expeditions", "tiers", "TEXT NOT NULL DEFAULT '{}'"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("completed_expeditions", "tier", "TEXT NOT NULL DEFAULT 'gold'"); err != nil {
+		return err
+	}
+
+	if err := db.addColumnIfMissing("battles", "seed", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("battles", "challenge_day", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("battle_rewards", "cosmetic", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("character", "attempt_reserve", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("character", "free_attempt_day", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("character", "endless_seed", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("character", "endless_record", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
 
 	if err := db.addColumnIfMissing("enemies", "zone", "INTEGER NOT NULL DEFAULT 1"); err != nil {
 		return err
@@ -290,7 +433,23 @@ func (db *DB) migrate() error {
 	if err := db.addColumnIfMissing("enemies", "target_winrate_max", "REAL NOT NULL DEFAULT 0"); err != nil {
 		return err
 	}
-	if _, err := db.conn.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_enemies_name_unique ON enemies(name)"); err != nil {
+	if err := db.addColumnIfMissing("enemies", "image", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("enemies", "endless", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("enemies", "boss_phases", "TEXT NOT NULL DEFAULT '[]'"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("enemies", "char_id", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	// Endless floors belong to one character, so names only need to be unique per owner.
+	if _, err := db.conn.Exec("DROP INDEX IF EXISTS idx_enemies_name_unique"); err != nil {
+		return err
+	}
+	if _, err := db.conn.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_enemies_char_name_unique ON enemies(char_id, name)"); err != nil {
 		return err
 	}
 	if err := db.NormalizeEnemyZones(); err != nil {
@@ -381,6 +540,60 @@ func (db *DB) migrateDungeonDataToExpeditions() error {
 	return nil
 }
 
+// migrateExpeditionStateToRuns moves status and task progress that used to live
+// on the shared expedition rows into runs owned by the first character.
+func (db *DB) migrateExpeditionStateToRuns() error {
+	var charID int64
+	err := db.conn.QueryRow("SELECT id FROM character ORDER BY id LIMIT 1").Scan(&charID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO expedition_runs (char_id, expedition_id, status, deadline, started_at, ended_at, updated_at)
+		SELECT ?, e.id, e.status, e.deadline, e.created_at,
+			CASE WHEN e.status = 'active' THEN NULL ELSE e.updated_at END,
+			e.updated_at
+		FROM expeditions e
+		WHERE e.status != 'active'
+			OR EXISTS (
+				SELECT 1 FROM expedition_tasks t
+				WHERE t.expedition_id = e.id AND (t.progress_current > 0 OR t.is_completed = 1)
+			)
+	`, charID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT OR IGNORE INTO expedition_run_tasks (run_id, task_id, progress_current, is_completed, updated_at)
+		SELECT r.id, t.id, t.progress_current, t.is_completed, t.updated_at
+		FROM expedition_tasks t
+		JOIN expedition_runs r ON r.id = (SELECT MAX(id) FROM expedition_runs WHERE char_id = ? AND expedition_id = t.expedition_id)
+		WHERE t.progress_current > 0 OR t.is_completed = 1
+	`, charID)
+	if err != nil {
+		return err
+	}
+
+	// Reset legacy state so the definitions stay neutral and this runs only once.
+	if _, err := tx.Exec("UPDATE expeditions SET status = 'active' WHERE status != 'active'"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE expedition_tasks SET progress_current = 0, is_completed = 0 WHERE progress_current > 0 OR is_completed = 1"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (db *DB) migrateQuestDungeonLinksToExpeditions() error {
 	if !db.columnExistsFast("quests", "expedition_id") || !db.columnExistsFast("quests", "dungeon_id") {
 		return nil
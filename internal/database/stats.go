@@ -17,24 +17,7 @@ func (db *DB) GetOrCreateCharacter(name string) (*models.Character, error) {
 	var activeTitle sql.NullString
 	err := db.conn.QueryRow("SELECT id, name, attempts, COALESCE(active_title,'') FROM character LIMIT 1").Scan(&char.ID, &char.Name, &char.Attempts, &activeTitle)
 	if err == sql.ErrNoRows {
-		res, err := db.conn.Exec("INSERT INTO character (name, attempts) VALUES (?, 0)", name)
-		if err != nil {
-			return nil, err
-		}
-		char.ID, _ = res.LastInsertId()
-		char.Name = name
-		char.Attempts = 0
-
-		for _, stat := range models.AllStats {
-			_, err := db.conn.Exec(
-				"INSERT INTO stat_levels (char_id, stat_type, level, current_exp, total_exp) VALUES (?, ?, 1, 0, 0)",
-				char.ID, string(stat),
-			)
-			if err != nil {
-				return nil, err
-			}
-		}
-		return &char, nil
+		return db.CreateCharacter(name)
 	}
 	if err != nil {
 		return nil, err
@@ -45,6 +28,27 @@ func (db *DB) GetOrCreateCharacter(name string) (*models.Character, error) {
 	return &char, nil
 }
 
+// CreateCharacter inserts a new character with level 1 stats.
+func (db *DB) CreateCharacter(name string) (*models.Character, error) {
+	res, err := db.conn.Exec("INSERT INTO character (name, attempts) VALUES (?, 0)", name)
+	if err != nil {
+		return nil, err
+	}
+	char := models.Character{Name: name}
+	char.ID, _ = res.LastInsertId()
+
+	for _, stat := range models.AllStats {
+		_, err := db.conn.Exec(
+			"INSERT INTO stat_levels (char_id, stat_type, level, current_exp, total_exp) VALUES (?, ?, 1, 0, 0)",
+			char.ID, string(stat),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &char, nil
+}
+
 func (db *DB) AddAttempts(charID int64, amount int) (int, error) {
 	_, err := db.conn.Exec(
 		"UPDATE character SET attempts = MIN(attempts + ?, ?) WHERE id = ?",
@@ -58,8 +62,72 @@ func (db *DB) AddAttempts(charID int64, amount int) (int, error) {
 	return current, err
 }
 
+// AddAttemptsWithReserve adds attempts like AddAttempts, but keeps whatever
+// overflows MaxAttempts in the reserve, up to reserveCap.
+func (db *DB) AddAttemptsWithReserve(charID int64, amount int, reserveCap int) (int, error) {
+	if err := addAttemptsWithReserve(db.conn, charID, amount, reserveCap); err != nil {
+		return 0, err
+	}
+	var current int
+	err := db.conn.QueryRow("SELECT attempts FROM character WHERE id = ?", charID).Scan(&current)
+	return current, err
+}
+
+// addAttemptsWithReserve is AddAttemptsWithReserve for use inside a transaction.
+// A zero reserveCap means the reserve is off: the overflow is dropped and the
+// reserve is left as it is.
+func addAttemptsWithReserve(exec sqlExecer, charID int64, amount int, reserveCap int) error {
+	if reserveCap <= 0 {
+		_, err := exec.Exec(
+			"UPDATE character SET attempts = MIN(attempts + ?, ?) WHERE id = ?",
+			amount, models.MaxAttempts, charID,
+		)
+		return err
+	}
+	_, err := exec.Exec(
+		`UPDATE character SET
+			attempt_reserve = MIN(attempt_reserve + MAX(attempts + ? - ?, 0), ?),
+			attempts = MIN(attempts + ?, ?)
+		WHERE id = ?`,
+		amount, models.MaxAttempts, reserveCap, amount, models.MaxAttempts, charID,
+	)
+	return err
+}
+
+// ClaimDailyAttempts grants amount attempts the first time it is called on day.
+// Returns false if the day was already claimed.
+func (db *DB) ClaimDailyAttempts(charID int64, day string, amount int, reserveCap int) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		"UPDATE character SET free_attempt_day = ? WHERE id = ? AND free_attempt_day != ?",
+		day, charID, day,
+	)
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return false, nil
+	}
+	if err := addAttemptsWithReserve(tx, charID, amount, reserveCap); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// SpendAttempt takes one attempt and refills the pool from the reserve.
 func (db *DB) SpendAttempt(charID int64) error {
-	res, err := db.conn.Exec("UPDATE character SET attempts = attempts - 1 WHERE id = ? AND attempts > 0", charID)
+	res, err := db.conn.Exec(
+		`UPDATE character SET
+			attempts = attempts - 1 + MIN(attempt_reserve, 1),
+			attempt_reserve = MAX(attempt_reserve - 1, 0)
+		WHERE id = ? AND attempts > 0`,
+		charID,
+	)
 	if err != nil {
 		return err
 	}
@@ -76,6 +144,58 @@ func (db *DB) GetAttempts(charID int64) (int, error) {
 	return attempts, err
 }
 
+func (db *DB) GetAttemptReserve(charID int64) (int, error) {
+	var reserve int
+	err := db.conn.QueryRow("SELECT attempt_reserve FROM character WHERE id = ?", charID).Scan(&reserve)
+	return reserve, err
+}
+
+// GetFreeAttemptDay returns the last day the daily free attempts were claimed.
+func (db *DB) GetFreeAttemptDay(charID int64) (string, error) {
+	var day string
+	err := db.conn.QueryRow("SELECT free_attempt_day FROM character WHERE id = ?", charID).Scan(&day)
+	return day, err
+}
+
+// EnsureEndlessSeed stores seed as the character's endless-tower seed unless
+// one is already set, and returns the seed in effect.
+func (db *DB) EnsureEndlessSeed(charID, seed int64) (int64, error) {
+	if _, err := db.conn.Exec(
+		"UPDATE character SET endless_seed = ? WHERE id = ? AND endless_seed = 0",
+		seed, charID,
+	); err != nil {
+		return 0, err
+	}
+	var current int64
+	err := db.conn.QueryRow("SELECT endless_seed FROM character WHERE id = ?", charID).Scan(&current)
+	return current, err
+}
+
+func (db *DB) GetEndlessSeed(charID int64) (int64, error) {
+	var seed int64
+	err := db.conn.QueryRow("SELECT endless_seed FROM character WHERE id = ?", charID).Scan(&seed)
+	return seed, err
+}
+
+// RaiseEndlessRecord keeps the highest endless floor the character has won.
+func (db *DB) RaiseEndlessRecord(charID int64, floor int) error {
+	return raiseEndlessRecord(db.conn, charID, floor)
+}
+
+func raiseEndlessRecord(exec sqlExecer, charID int64, floor int) error {
+	_, err := exec.Exec(
+		"UPDATE character SET endless_record = MAX(endless_record, ?) WHERE id = ?",
+		floor, charID,
+	)
+	return err
+}
+
+func (db *DB) GetEndlessRecord(charID int64) (int, error) {
+	var record int
+	err := db.conn.QueryRow("SELECT endless_record FROM character WHERE id = ?", charID).Scan(&record)
+	return record, err
+}
+
 // Streak titles
 func (db *DB) InsertStreakTitle(charID int64, title string, streakDays int) error {
 	_, err := db.conn.Exec(
@@ -135,6 +255,22 @@ func (db *DB) GetAllTitles(charID int64) ([]string, error) {
 		}
 	}
 
+	// Expedition reward titles
+	rows3, err := db.conn.Query("SELECT value FROM expedition_reward_log WHERE char_id = ? AND kind = ? GROUP BY value ORDER BY MIN(id)", charID, string(models.RewardTitle))
+	if err != nil {
+		return nil, err
+	}
+	defer rows3.Close()
+	for rows3.Next() {
+		var t string
+		if err := rows3.Scan(&t); err != nil {
+			return nil, err
+		}
+		if t != "" {
+			titles = append(titles, t)
+		}
+	}
+
 	// Streak titles
 	rows2, err := db.conn.Query("SELECT title FROM streak_titles WHERE char_id = ? ORDER BY streak_days", charID)
 	if err != nil {
@@ -241,8 +377,12 @@ func (db *DB) ToggleSkill(skillID int64, active bool) error {
 // ============================================================
 
 func (db *DB) RecordDailyActivity(charID int64, questsCompleted, questsFailed, expEarned int) error {
+	return recordDailyActivity(db.conn, charID, questsCompleted, questsFailed, expEarned)
+}
+
+func recordDailyActivity(exec sqlExecer, charID int64, questsCompleted, questsFailed, expEarned int) error {
 	today := time.Now().Format("2006-01-02")
-	_, err := db.conn.Exec(`
+	_, err := exec.Exec(`
 		INSERT INTO daily_activity (char_id, date, quests_completed, quests_failed, exp_earned)
 		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(char_id, date) DO UPDATE SET
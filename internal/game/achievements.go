@@ -11,6 +11,8 @@ const (
 	AchievementFirstBattle     = "first_battle"
 	AchievementStreak7         = "streak_7"
 	AchievementFirstExpedition = "first_expedition"
+	AchievementChallenge3      = "challenge_streak_3"
+	AchievementChallenge7      = "challenge_streak_7"
 )
 
 func defaultAchievements() []models.Achievement {
@@ -39,6 +41,18 @@ func defaultAchievements() []models.Achievement {
 			Description: "Первая завершённая экспедиция в истории охотника.",
 			Category:    "expedition",
 		},
+		{
+			Key:         AchievementChallenge3,
+			Title:       "Испытатель",
+			Description: "Три испытания дня подряд. Награда: рамка «Испытатель».",
+			Category:    "combat",
+		},
+		{
+			Key:         AchievementChallenge7,
+			Title:       "Неделя испытаний",
+			Description: "Семь испытаний дня подряд. Награда: аура «Неделя испытаний».",
+			Category:    "combat",
+		},
 	}
 }
 
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"math"
+
+	"solo-leveling/internal/game/combat/formulas"
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Adaptive difficulty
+// ============================================================
+
+// adaptiveEstimateSeed keeps the win-rate estimate stable between calls.
+const adaptiveEstimateSeed = 4049
+
+// AdaptiveDifficulty explains the nudge adaptive difficulty gives an enemy.
+type AdaptiveDifficulty struct {
+	Shift            models.DifficultyShift
+	Battles          int     // recent tower battles the estimate is based on
+	Needed           int     // battles still missing before anything is adjusted
+	Accuracy         float64 // average accuracy of those battles, percent
+	EstimatedWinRate float64 // win chance against the enemy at that accuracy, percent
+	TargetMin        float64
+	TargetMax        float64
+}
+
+// AdaptiveEnabled reports whether adaptive difficulty is switched on.
+func (e *Engine) AdaptiveEnabled() bool {
+	return e.Adaptive != nil
+}
+
+// GetAdaptiveDifficulty works out how the memory field against enemy is
+// nudged. It averages accuracy over the recent tower battles, estimates the
+// win chance against the enemy at that accuracy and, when the estimate leaves
+// the enemy's target band, adds or removes cells and show time one step per
+// WinRateStep points outside it. Returns nil when adaptive difficulty is off
+// or the enemy has no target band.
+func (e *Engine) GetAdaptiveDifficulty(enemy models.Enemy) (*AdaptiveDifficulty, error) {
+	if !e.AdaptiveEnabled() || enemy.TargetWinRateMax <= 0 {
+		return nil, nil
+	}
+	rules := *e.Adaptive
+
+	history, err := e.DB.GetBattleHistory(e.Character.ID, 2*rules.Window)
+	if err != nil {
+		return nil, err
+	}
+	adaptive := &AdaptiveDifficulty{TargetMin: enemy.TargetWinRateMin, TargetMax: enemy.TargetWinRateMax}
+	total := 0.0
+	for _, b := range history {
+		// The daily challenge is deliberately harder and would skew the average.
+		if b.ChallengeDay != "" || adaptive.Battles >= rules.Window {
+			continue
+		}
+		adaptive.Battles++
+		total += b.Accuracy
+	}
+	if adaptive.Battles < rules.MinBattles {
+		adaptive.Needed = rules.MinBattles - adaptive.Battles
+		return adaptive, nil
+	}
+	adaptive.Accuracy = total / float64(adaptive.Battles)
+
+	stats, err := e.combatStats()
+	if err != nil {
+		return nil, err
+	}
+	adaptive.EstimatedWinRate = estimateWinRate(enemy, stats, adaptive.Accuracy/100, rules)
+	adaptive.Shift = difficultyShift(adaptive.EstimatedWinRate, enemy.TargetWinRateMin, enemy.TargetWinRateMax, rules)
+	return adaptive, nil
+}
+
+// adaptEnemy applies adaptive difficulty to an enemy about to be fought.
+func (e *Engine) adaptEnemy(enemy *models.Enemy) error {
+	adaptive, err := e.GetAdaptiveDifficulty(*enemy)
+	if err != nil || adaptive == nil {
+		return err
+	}
+	enemy.Difficulty = adaptive.Shift
+	return nil
+}
+
+// difficultyShift turns the distance between the estimated win rate and the
+// target band into cell and show-time steps, capped at MaxCellShift.
+func difficultyShift(winRate, targetMin, targetMax float64, rules models.AdaptiveRules) models.DifficultyShift {
+	var gap float64
+	switch {
+	case winRate > targetMax:
+		gap = winRate - targetMax
+	case winRate < targetMin:
+		gap = winRate - targetMin
+	default:
+		return models.DifficultyShift{}
+	}
+	steps := int(math.Ceil(math.Abs(gap) / rules.WinRateStep))
+	if steps > rules.MaxCellShift {
+		steps = rules.MaxCellShift
+	}
+	if gap < 0 {
+		steps = -steps
+	}
+	// Winning too often means more cells and a shorter show, and vice versa.
+	return models.DifficultyShift{Cells: steps, ShowTimePercent: -steps * rules.ShowTimeStep}
+}
+
+// estimateWinRate plays the damage exchange of a regular fight at a fixed
+// accuracy and returns the share of fights won, in percent.
+func estimateWinRate(enemy models.Enemy, stats memory.Stats, accuracy float64, rules models.AdaptiveRules) float64 {
+	if rules.EstimateFights <= 0 {
+		return 0
+	}
+	critChance := memory.CritChanceAgainst(enemy, stats)
+	regen := memory.RegenPerRound(enemy)
+	wins := 0
+	for fight := 0; fight < rules.EstimateFights; fight++ {
+		rng := formulas.RoundSource(adaptiveEstimateSeed, fight)
+		playerHP, enemyHP := memory.MaxHP(stats), enemy.HP
+		for round := 0; round < rules.EstimateMaxRound; round++ {
+			exchange := formulas.ResolveRoundWithCrit(stats.STR, critChance, stats.STA, enemy.Attack, accuracy, rng)
+			enemyHP -= exchange.PlayerDamage
+			playerHP -= exchange.EnemyDamage
+			if enemyHP <= 0 {
+				wins++
+				break
+			}
+			if playerHP <= 0 {
+				break
+			}
+			enemyHP = min(enemyHP+regen, enemy.HP)
+		}
+	}
+	return float64(wins) / float64(rules.EstimateFights) * 100
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+func recordAccuracy(t *testing.T, e *Engine, accuracy float64, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		if err := e.DB.InsertBattle(&models.BattleRecord{
+			CharID:    e.Character.ID,
+			EnemyName: "Тень",
+			Result:    models.BattleLose,
+			Accuracy:  accuracy,
+		}); err != nil {
+			t.Fatalf("insert battle: %v", err)
+		}
+	}
+}
+
+func TestAdaptiveDifficultyIsOptIn(t *testing.T) {
+	e := newTestEngine(t)
+	current, _ := e.GetCurrentEnemy()
+	recordAccuracy(t, e, 100, 5)
+
+	if adaptive, err := e.GetAdaptiveDifficulty(*current); err != nil || adaptive != nil {
+		t.Fatalf("expected no adjustment while disabled, got %+v (%v)", adaptive, err)
+	}
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if !state.Enemy.Difficulty.IsZero() {
+		t.Fatalf("disabled adaptive difficulty changed the field: %+v", state.Enemy.Difficulty)
+	}
+}
+
+func TestAdaptiveDifficultyFollowsRecentAccuracy(t *testing.T) {
+	e := newTestEngine(t)
+	rules := models.DefaultAdaptiveRules()
+	e.Adaptive = &rules
+	current, _ := e.GetCurrentEnemy()
+	// Strong enough that a perfect memory wins the first floor comfortably.
+	levels, _ := e.GetStatLevels()
+	for i := range levels {
+		levels[i].Level = 12
+		if err := e.DB.UpdateStatLevel(&levels[i]); err != nil {
+			t.Fatalf("update stat level: %v", err)
+		}
+	}
+
+	recordAccuracy(t, e, 100, rules.MinBattles-1)
+	adaptive, err := e.GetAdaptiveDifficulty(*current)
+	if err != nil || adaptive == nil || adaptive.Needed != 1 || !adaptive.Shift.IsZero() {
+		t.Fatalf("expected to wait for one more battle, got %+v (%v)", adaptive, err)
+	}
+
+	recordAccuracy(t, e, 100, 1)
+	adaptive, _ = e.GetAdaptiveDifficulty(*current)
+	if adaptive.EstimatedWinRate <= current.TargetWinRateMax || adaptive.Shift.Cells <= 0 || adaptive.Shift.ShowTimePercent >= 0 {
+		t.Fatalf("perfect accuracy should make the field harder: %+v (target %.0f-%.0f)", adaptive, current.TargetWinRateMin, current.TargetWinRateMax)
+	}
+	if adaptive.Shift.Cells > rules.MaxCellShift {
+		t.Fatalf("shift %d exceeds the cap %d", adaptive.Shift.Cells, rules.MaxCellShift)
+	}
+
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if state.Enemy.Difficulty != adaptive.Shift {
+		t.Fatalf("fight must use the shown adjustment: %+v vs %+v", state.Enemy.Difficulty, adaptive.Shift)
+	}
+	plain := *current
+	if state.CellsToShow != memory.CellsToShow(state.Enemy, combatStatsFromLevels(levels)) ||
+		memory.CellsToShow(state.Enemy, combatStatsFromLevels(levels)) <= memory.CellsToShow(plain, combatStatsFromLevels(levels)) {
+		t.Fatalf("adjusted fight should show more cells, got %d", state.CellsToShow)
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		t.Fatalf("forfeit: %v", err)
+	}
+
+	recordAccuracy(t, e, 0, rules.Window)
+	adaptive, _ = e.GetAdaptiveDifficulty(*current)
+	if adaptive.Shift.Cells >= 0 || adaptive.Shift.ShowTimePercent <= 0 {
+		t.Fatalf("failing every pattern should make the field easier: %+v", adaptive)
+	}
+}
+
+func TestDifficultyShiftSteps(t *testing.T) {
+	rules := models.DefaultAdaptiveRules()
+	cases := []struct {
+		winRate float64
+		want    models.DifficultyShift
+	}{
+		{winRate: 20, want: models.DifficultyShift{}},
+		{winRate: 30, want: models.DifficultyShift{Cells: 1, ShowTimePercent: -10}},
+		{winRate: 95, want: models.DifficultyShift{Cells: 2, ShowTimePercent: -20}},
+		{winRate: 0, want: models.DifficultyShift{Cells: -1, ShowTimePercent: 10}},
+	}
+	for _, tc := range cases {
+		if got := difficultyShift(tc.winRate, 15, 25, rules); got != tc.want {
+			t.Fatalf("win rate %.0f: expected %+v, got %+v", tc.winRate, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package game
+
+import "time"
+
+// ============================================================
+// Battle attempts
+// ============================================================
+
+// AttemptStatus is what the fight button needs to know about attempts.
+type AttemptStatus struct {
+	Enabled  bool
+	Attempts int
+	Reserve  int
+	// DailyPending is today's free attempts not claimed yet; the next fight claims them.
+	DailyPending int
+}
+
+// CanFight reports whether a fight can be started right now.
+func (s AttemptStatus) CanFight() bool {
+	return !s.Enabled || s.Attempts > 0 || s.DailyPending > 0
+}
+
+// AttemptsEnabled reports whether fights cost battle attempts.
+func (e *Engine) AttemptsEnabled() bool {
+	return e.AttemptRules != nil
+}
+
+// GetAttemptStatus returns the current attempts without writing anything:
+// today's free attempts are reported as pending until a fight or the startup
+// claim takes them. With the economy off it only reports Enabled=false.
+func (e *Engine) GetAttemptStatus() (AttemptStatus, error) {
+	if !e.AttemptsEnabled() {
+		return AttemptStatus{}, nil
+	}
+	attempts, err := e.DB.GetAttempts(e.Character.ID)
+	if err != nil {
+		return AttemptStatus{}, err
+	}
+	reserve, err := e.DB.GetAttemptReserve(e.Character.ID)
+	if err != nil {
+		return AttemptStatus{}, err
+	}
+	status := AttemptStatus{Enabled: true, Attempts: attempts, Reserve: reserve}
+	if e.AttemptRules.DailyFree > 0 {
+		day, err := e.DB.GetFreeAttemptDay(e.Character.ID)
+		if err != nil {
+			return AttemptStatus{}, err
+		}
+		if day != time.Now().Format("2006-01-02") {
+			status.DailyPending = e.AttemptRules.DailyFree
+		}
+	}
+	e.Character.Attempts = attempts
+	return status, nil
+}
+
+// ClaimDailyAttempt grants the daily free attempts once per calendar day.
+// Returns how many attempts were granted.
+func (e *Engine) ClaimDailyAttempt() (int, error) {
+	if !e.AttemptsEnabled() || e.AttemptRules.DailyFree <= 0 {
+		return 0, nil
+	}
+	day := time.Now().Format("2006-01-02")
+	granted, err := e.DB.ClaimDailyAttempts(e.Character.ID, day, e.AttemptRules.DailyFree, e.AttemptRules.ReserveCap)
+	if err != nil || !granted {
+		return 0, err
+	}
+	e.GetAttempts()
+	return e.AttemptRules.DailyFree, nil
+}
+
+// awardAttempts adds earned attempts, banking the overflow when the rules allow it.
+func (e *Engine) awardAttempts(amount int) (int, error) {
+	if e.AttemptsEnabled() {
+		return e.DB.AddAttemptsWithReserve(e.Character.ID, amount, e.AttemptRules.ReserveCap)
+	}
+	return e.DB.AddAttempts(e.Character.ID, amount)
+}
+
+// attemptReserveCap is how many overflowing attempts may be banked; 0 with the economy off.
+func (e *Engine) attemptReserveCap() int {
+	if !e.AttemptsEnabled() {
+		return 0
+	}
+	return e.AttemptRules.ReserveCap
+}
+
+func (e *Engine) spendBattleAttempt() error {
+	if !e.AttemptsEnabled() {
+		// Battle attempts mechanic is disabled: fights are always available.
+		return nil
+	}
+	if _, err := e.ClaimDailyAttempt(); err != nil {
+		return err
+	}
+	if err := e.DB.SpendAttempt(e.Character.ID); err != nil {
+		return err
+	}
+	e.GetAttempts()
+	return nil
+}
+
+// refundUnplayed returns the attempt of a saved fight that was abandoned
+// before its first round resolved, e.g. because the app crashed.
+func (e *Engine) refundUnplayed(saved *SavedBattle) error {
+	if !e.AttemptsEnabled() || !e.AttemptRules.RefundUnplayed {
+		return nil
+	}
+	if saved.Round() > 1 || saved.Decided() {
+		return nil
+	}
+	// The daily challenge and practice never spent an attempt.
+	if saved.Battle != nil && (saved.Battle.ChallengeDay != "" || saved.Battle.Practice) {
+		return nil
+	}
+	total, err := e.awardAttempts(1)
+	if err != nil {
+		return err
+	}
+	e.Character.Attempts = total
+	return nil
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/models"
+)
+
+func withAttemptRules(e *Engine, rules models.AttemptRules) *Engine {
+	e.AttemptRules = &rules
+	return e
+}
+
+func TestAttemptsEnabledSpendAndDailyFree(t *testing.T) {
+	e := withAttemptRules(newTestEngine(t), models.AttemptRules{DailyFree: 1})
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+
+	// The first fight of the day runs on the free attempt.
+	if _, err := e.StartBattle(current.ID); err != nil {
+		t.Fatalf("start battle on daily attempt: %v", err)
+	}
+	if got := e.GetAttempts(); got != 0 {
+		t.Fatalf("expected daily attempt spent, got %d", got)
+	}
+	if _, err := e.StartBattle(current.ID); err == nil {
+		t.Fatal("expected error without attempts")
+	}
+	status, err := e.GetAttemptStatus()
+	if err != nil {
+		t.Fatalf("attempt status: %v", err)
+	}
+	if status.CanFight() {
+		t.Fatalf("daily attempt must be granted once, got %+v", status)
+	}
+}
+
+func TestAttemptStatusDoesNotClaimDailyAttempt(t *testing.T) {
+	e := withAttemptRules(newTestEngine(t), models.AttemptRules{DailyFree: 1})
+
+	for i := 0; i < 2; i++ {
+		status, err := e.GetAttemptStatus()
+		if err != nil {
+			t.Fatalf("attempt status: %v", err)
+		}
+		if status.Attempts != 0 || status.DailyPending != 1 || !status.CanFight() {
+			t.Fatalf("expected the daily attempt reported as pending, got %+v", status)
+		}
+	}
+	if got, err := e.DB.GetAttempts(e.Character.ID); err != nil || got != 0 {
+		t.Fatalf("reading the status must not grant attempts, got %d (%v)", got, err)
+	}
+
+	if granted, err := e.ClaimDailyAttempt(); err != nil || granted != 1 {
+		t.Fatalf("claim daily attempt: %d (%v)", granted, err)
+	}
+	status, err := e.GetAttemptStatus()
+	if err != nil {
+		t.Fatalf("attempt status: %v", err)
+	}
+	if status.Attempts != 1 || status.DailyPending != 0 {
+		t.Fatalf("expected the claimed attempt in the pool, got %+v", status)
+	}
+}
+
+func TestAttemptsOverflowGoesToReserve(t *testing.T) {
+	e := withAttemptRules(newTestEngine(t), models.AttemptRules{ReserveCap: 2})
+
+	if _, err := e.awardAttempts(models.MaxAttempts + 5); err != nil {
+		t.Fatalf("award attempts: %v", err)
+	}
+	status, err := e.GetAttemptStatus()
+	if err != nil {
+		t.Fatalf("attempt status: %v", err)
+	}
+	if status.Attempts != models.MaxAttempts || status.Reserve != 2 {
+		t.Fatalf("expected %d attempts and 2 in reserve, got %+v", models.MaxAttempts, status)
+	}
+
+	// Spending refills the pool from the reserve.
+	if err := e.spendBattleAttempt(); err != nil {
+		t.Fatalf("spend attempt: %v", err)
+	}
+	status, _ = e.GetAttemptStatus()
+	if status.Attempts != models.MaxAttempts || status.Reserve != 1 {
+		t.Fatalf("expected pool refilled from reserve, got %+v", status)
+	}
+}
+
+func TestExpeditionAttemptRewardOverflowGoesToReserve(t *testing.T) {
+	e := withAttemptRules(newTestEngine(t), models.AttemptRules{ReserveCap: 4})
+	if _, err := e.awardAttempts(models.MaxAttempts); err != nil {
+		t.Fatalf("fill attempts: %v", err)
+	}
+
+	expedition := models.Expedition{
+		Name:    "Запасливая экспедиция",
+		Status:  models.ExpeditionActive,
+		Rewards: []models.Reward{{Kind: models.RewardAttempts, Amount: 3}},
+		Tasks:   []models.ExpeditionTask{{Title: "Финал", ProgressTarget: 1, RewardEXP: 10, TargetStat: models.StatStrength}},
+	}
+	if err := e.CreateExpedition(&expedition); err != nil {
+		t.Fatalf("create expedition: %v", err)
+	}
+	if _, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 1); err != nil {
+		t.Fatalf("finish task: %v", err)
+	}
+
+	status, err := e.GetAttemptStatus()
+	if err != nil {
+		t.Fatalf("attempt status: %v", err)
+	}
+	if status.Attempts != models.MaxAttempts || status.Reserve != 3 {
+		t.Fatalf("expected the reward banked as 3 in reserve, got %+v", status)
+	}
+}
+
+func TestAttemptsRefundedForUnplayedFight(t *testing.T) {
+	e := withAttemptRules(newTestEngine(t), models.AttemptRules{RefundUnplayed: true})
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+	if _, err := e.awardAttempts(2); err != nil {
+		t.Fatalf("award attempts: %v", err)
+	}
+
+	// Abandoned before the first round: the attempt comes back.
+	if _, err := e.StartBattle(current.ID); err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		t.Fatalf("forfeit: %v", err)
+	}
+	if got := e.GetAttempts(); got != 2 {
+		t.Fatalf("expected unplayed fight refunded, got %d attempts", got)
+	}
+
+	// Abandoned after a round: the attempt is lost.
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if err := e.ProcessRound(state, nil); err != nil {
+		t.Fatalf("process round: %v", err)
+	}
+	if state.BattleOver {
+		t.Skip("fight ended in one round")
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		t.Fatalf("forfeit: %v", err)
+	}
+	if got := e.GetAttempts(); got != 1 {
+		t.Fatalf("expected played fight to keep its cost, got %d attempts", got)
+	}
+}
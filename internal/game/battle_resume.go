@@ -0,0 +1,141 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"solo-leveling/internal/game/combat/boss"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Saved battles
+// ============================================================
+
+// SavedBattle is an unfinished fight restored from the database. Exactly one
+// of Battle and Boss is set, according to Kind.
+type SavedBattle struct {
+	Kind      models.BattleKind
+	Battle    *models.BattleState
+	Boss      *boss.State
+	UpdatedAt time.Time
+}
+
+// EnemyName returns the name of the enemy the saved fight is against.
+func (s *SavedBattle) EnemyName() string {
+	if s.Boss != nil {
+		return s.Boss.Enemy.Name
+	}
+	if s.Battle != nil {
+		return s.Battle.Enemy.Name
+	}
+	return ""
+}
+
+// Decided reports whether the saved fight already has an outcome.
+func (s *SavedBattle) Decided() bool {
+	if s.Boss != nil {
+		return s.Boss.Phase == boss.PhaseWin || s.Boss.Phase == boss.PhaseLose
+	}
+	return s.Battle != nil && s.Battle.BattleOver
+}
+
+// Round returns the round the saved fight stopped at.
+func (s *SavedBattle) Round() int {
+	if s.Boss != nil {
+		return s.Boss.Round
+	}
+	if s.Battle != nil {
+		return s.Battle.Round
+	}
+	return 0
+}
+
+func (e *Engine) saveBattle(state *models.BattleState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return e.DB.SaveActiveBattle(&models.ActiveBattle{
+		CharID:  e.Character.ID,
+		Kind:    models.BattleKindRegular,
+		EnemyID: state.Enemy.ID,
+		State:   string(raw),
+	})
+}
+
+func (e *Engine) saveBossBattle(state *boss.State) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return e.DB.SaveActiveBattle(&models.ActiveBattle{
+		CharID:  e.Character.ID,
+		Kind:    models.BattleKindBoss,
+		EnemyID: state.Enemy.ID,
+		State:   string(raw),
+	})
+}
+
+// GetSavedBattle returns the character's unfinished fight, or nil if there is none.
+func (e *Engine) GetSavedBattle() (*SavedBattle, error) {
+	active, err := e.DB.GetActiveBattle(e.Character.ID)
+	if err != nil || active == nil {
+		return nil, err
+	}
+
+	saved := &SavedBattle{Kind: active.Kind, UpdatedAt: active.UpdatedAt}
+	switch active.Kind {
+	case models.BattleKindRegular:
+		saved.Battle = &models.BattleState{}
+		err = json.Unmarshal([]byte(active.State), saved.Battle)
+	case models.BattleKindBoss:
+		saved.Boss = &boss.State{}
+		err = json.Unmarshal([]byte(active.State), saved.Boss)
+	default:
+		err = fmt.Errorf("unknown battle kind: %s", active.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("восстановление боя: %w", err)
+	}
+	// The interrupted round is shown again from the start.
+	if saved.Boss != nil {
+		saved.Boss.RoundStartedAt = time.Now()
+	} else {
+		saved.Battle.RoundStartedAt = time.Now()
+	}
+	return saved, nil
+}
+
+// ForfeitSavedBattle records the unfinished fight as a loss and clears it.
+// A fight that had already been decided is recorded with its real outcome,
+// and one abandoned before its first round may get its attempt back.
+// Returns nil when there is nothing to forfeit.
+func (e *Engine) ForfeitSavedBattle() (*models.BattleRecord, error) {
+	saved, err := e.GetSavedBattle()
+	if err != nil || saved == nil {
+		return nil, err
+	}
+	if err := e.refundUnplayed(saved); err != nil {
+		return nil, err
+	}
+
+	if saved.Boss != nil {
+		state := saved.Boss
+		if state.Phase == boss.PhaseWin {
+			return e.FinishBoss(state)
+		}
+		state.Phase = boss.PhaseLose
+		state.PlayerHP = 0
+		return e.FailBoss(state)
+	}
+
+	state := saved.Battle
+	if !state.BattleOver {
+		state.BattleOver = true
+		state.Result = models.BattleLose
+		state.PlayerHP = 0
+	}
+	return e.FinishBattle(state)
+}
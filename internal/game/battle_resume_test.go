@@ -0,0 +1,255 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/game/combat/boss"
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+func TestSavedBattleResumeAndForfeit(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if err := e.ProcessRound(state, state.ShownCells); err != nil {
+		t.Fatalf("process round: %v", err)
+	}
+
+	// A fresh engine over the same database sees the fight as it was after the round.
+	restarted := &Engine{DB: e.DB, Character: e.Character}
+	saved, err := restarted.GetSavedBattle()
+	if err != nil {
+		t.Fatalf("get saved battle: %v", err)
+	}
+	if saved == nil || saved.Kind != models.BattleKindRegular || saved.Battle == nil {
+		t.Fatalf("expected saved regular battle, got %+v", saved)
+	}
+	if saved.Round() != state.Round || saved.Battle.EnemyHP != state.EnemyHP || saved.Battle.DamageDealt != state.DamageDealt {
+		t.Fatalf("saved state differs: %+v vs %+v", saved.Battle, state)
+	}
+
+	record, err := restarted.ForfeitSavedBattle()
+	if err != nil {
+		t.Fatalf("forfeit: %v", err)
+	}
+	if record == nil || record.Result != models.BattleLose || record.DamageDealt != state.DamageDealt {
+		t.Fatalf("expected forfeit recorded as loss, got %+v", record)
+	}
+	if saved, err := restarted.GetSavedBattle(); err != nil || saved != nil {
+		t.Fatalf("expected saved battle cleared, got %+v (%v)", saved, err)
+	}
+	history, err := e.GetBattleHistory(10)
+	if err != nil {
+		t.Fatalf("battle history: %v", err)
+	}
+	if len(history) != 1 || history[0].Result != models.BattleLose {
+		t.Fatalf("expected one loss in history, got %+v", history)
+	}
+	if record, err := restarted.ForfeitSavedBattle(); err != nil || record != nil {
+		t.Fatalf("expected nothing to forfeit, got %+v (%v)", record, err)
+	}
+}
+
+func TestStartBattleForfeitsAbandonedFight(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+
+	if _, err := e.StartBattle(current.ID); err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("restart battle: %v", err)
+	}
+	if _, err := e.FinishBattle(state); err != nil {
+		t.Fatalf("finish battle: %v", err)
+	}
+
+	history, err := e.GetBattleHistory(10)
+	if err != nil {
+		t.Fatalf("battle history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected abandoned fight to be recorded, got %d records", len(history))
+	}
+}
+
+func TestSavedBossBattleRoundTrip(t *testing.T) {
+	e := newTestEngine(t)
+	enemy := models.Enemy{ID: 42, Name: "Страж", Type: models.EnemyBoss, HP: 500, Attack: 10, Rank: models.RankC}
+	state, err := boss.NewState(enemy, memory.Stats{STR: 5, AGI: 5, INT: 5, STA: 5}, 120, 7)
+	if err != nil {
+		t.Fatalf("new boss state: %v", err)
+	}
+	state.Round = 3
+	state.DamageDealt = 77
+	state.TotalCrits = 2
+	if err := e.saveBossBattle(state); err != nil {
+		t.Fatalf("save boss battle: %v", err)
+	}
+
+	saved, err := e.GetSavedBattle()
+	if err != nil {
+		t.Fatalf("get saved battle: %v", err)
+	}
+	if saved.Boss == nil || saved.Boss.Phase != boss.PhaseMemory || saved.Round() != 3 || saved.EnemyName() != "Страж" {
+		t.Fatalf("unexpected saved boss: %+v", saved)
+	}
+	if len(saved.Boss.Memory.ShownCells) != len(state.Memory.ShownCells) {
+		t.Fatalf("shown cells lost: %v vs %v", saved.Boss.Memory.ShownCells, state.Memory.ShownCells)
+	}
+
+	record, err := e.ForfeitSavedBattle()
+	if err != nil {
+		t.Fatalf("forfeit boss: %v", err)
+	}
+	if record.Result != models.BattleLose || record.DamageDealt != 77 || record.CriticalHits != 2 {
+		t.Fatalf("expected boss forfeit as loss, got %+v", record)
+	}
+}
+
+func TestBossDecoysAreRecorded(t *testing.T) {
+	e := newTestEngine(t)
+	_, bossEnemy := zoneEnemies(t, e, 1)
+	bossEnemy.BossPhases = []models.BossPhase{{Name: "Ловушки", HPThreshold: 1, Mechanic: models.BossDecoys, Value: 2}}
+	state, err := boss.NewState(bossEnemy, memory.Stats{}, 10000, 3)
+	if err != nil {
+		t.Fatalf("new boss state: %v", err)
+	}
+	decoys := append([]int(nil), state.Memory.DecoyCells...)
+	if err := boss.ApplyMemoryInput(state, decoys, memory.Stats{}, 0); err != nil {
+		t.Fatalf("apply input: %v", err)
+	}
+	record, err := e.FailBoss(state)
+	if err != nil {
+		t.Fatalf("fail boss: %v", err)
+	}
+
+	rounds, err := e.DB.GetBattleRounds(record.ID)
+	if err != nil || len(rounds) != 1 {
+		t.Fatalf("expected one recorded round, got %v (%v)", rounds, err)
+	}
+	if len(rounds[0].DecoyCells) != 2 || rounds[0].DecoyCells[0] != decoys[0] || rounds[0].DecoyCells[1] != decoys[1] {
+		t.Fatalf("expected decoys %v to be stored, got %v", decoys, rounds[0].DecoyCells)
+	}
+}
+
+func TestBattleRoundsAreRecorded(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	firstShown := append([]int(nil), state.ShownCells...)
+	if err := e.ProcessRound(state, firstShown); err != nil {
+		t.Fatalf("process round 1: %v", err)
+	}
+	if !state.BattleOver {
+		if err := e.ProcessRound(state, nil); err != nil {
+			t.Fatalf("process round 2: %v", err)
+		}
+	}
+	if !state.BattleOver {
+		state.BattleOver = true
+		state.Result = models.BattleLose
+	}
+
+	record, err := e.FinishBattle(state)
+	if err != nil {
+		t.Fatalf("finish battle: %v", err)
+	}
+	rounds, err := e.DB.GetBattleRounds(record.ID)
+	if err != nil {
+		t.Fatalf("get rounds: %v", err)
+	}
+	if len(rounds) != len(state.Rounds) || len(rounds) == 0 {
+		t.Fatalf("expected %d recorded rounds, got %d", len(state.Rounds), len(rounds))
+	}
+
+	first := rounds[0]
+	if first.BattleID != record.ID || first.Round != 1 || first.GridSize != state.GridSize {
+		t.Fatalf("unexpected first round: %+v", first)
+	}
+	if len(first.ShownCells) != len(firstShown) || len(first.Choices) != len(firstShown) || first.Accuracy != 1 {
+		t.Fatalf("first round cells not recorded: %+v", first)
+	}
+	if first.DamageDealt != state.Rounds[0].DamageDealt || first.ShowTimeMs <= 0 {
+		t.Fatalf("first round stats not recorded: %+v", first)
+	}
+	if len(rounds) > 1 && (rounds[1].Accuracy != 0 || len(rounds[1].Choices) != 0) {
+		t.Fatalf("unexpected second round: %+v", rounds[1])
+	}
+}
+
+func TestSeededBattleIsReproducible(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	twin := &models.BattleState{
+		Enemy:       state.Enemy,
+		Seed:        state.Seed,
+		PlayerHP:    state.PlayerHP,
+		PlayerMaxHP: state.PlayerMaxHP,
+		EnemyHP:     state.EnemyHP,
+		EnemyMaxHP:  state.EnemyMaxHP,
+		Round:       state.Round,
+		GridSize:    state.GridSize,
+		CellsToShow: state.CellsToShow,
+		ShowTimeMs:  state.ShowTimeMs,
+		ShownCells:  append([]int(nil), state.ShownCells...),
+	}
+
+	for i := 0; i < 3 && !state.BattleOver; i++ {
+		choices := state.ShownCells[:len(state.ShownCells)/2]
+		if err := e.ProcessRound(state, choices); err != nil {
+			t.Fatalf("process round: %v", err)
+		}
+		if err := e.ProcessRound(twin, choices); err != nil {
+			t.Fatalf("process twin round: %v", err)
+		}
+		if state.EnemyHP != twin.EnemyHP || state.PlayerHP != twin.PlayerHP || state.TotalCrits != twin.TotalCrits {
+			t.Fatalf("round %d diverged: %+v vs %+v", i+1, state, twin)
+		}
+		for j := range state.ShownCells {
+			if state.ShownCells[j] != twin.ShownCells[j] {
+				t.Fatalf("next field diverged: %v vs %v", state.ShownCells, twin.ShownCells)
+			}
+		}
+	}
+
+	state.BattleOver, state.Result = true, models.BattleLose
+	record, err := e.FinishBattle(state)
+	if err != nil {
+		t.Fatalf("finish battle: %v", err)
+	}
+	history, err := e.GetBattleHistory(1)
+	if err != nil {
+		t.Fatalf("battle history: %v", err)
+	}
+	if len(history) != 1 || history[0].ID != record.ID || history[0].Seed != state.Seed {
+		t.Fatalf("expected seed %d stored on the record, got %+v", state.Seed, history)
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"solo-leveling/internal/models"
+)
+
+// zoneRewardBadges is the badge every regular enemy of a zone grants on its first defeat.
+var zoneRewardBadges = map[int]string{
+	1: "🐸",
+	2: "🏛",
+	3: "❄",
+	4: "🌋",
+	5: "🌑",
+}
+
+// enemyRewards is the first-win reward table for minibosses and bosses, keyed by enemy name.
+var enemyRewards = map[string]models.BattleReward{
+	"Пасть Топи":               {Badge: "🐊", Cosmetic: "Рамка «Болотный мох»"},
+	"Хозяйка Туманов Морра":    {Title: "Рассеявший Туман", Badge: "🌫", Cosmetic: "Аура «Туман Морры»"},
+	"Жрец Разломанных Печатей": {Badge: "📜", Cosmetic: "Рамка «Древние печати»"},
+	"Архонт Руин Кальдрос":     {Title: "Сокрушитель Архонта", Badge: "🗿", Cosmetic: "Аура «Пыль веков»"},
+	"Белый Йети":               {Badge: "🐾", Cosmetic: "Рамка «Иней»"},
+	"Король Вьюги Хельгрим":    {Title: "Низвергнувший Короля Вьюги", Badge: "👑", Cosmetic: "Аура «Вечная вьюга»"},
+	"Плавильщик Костей":        {Badge: "🦴", Cosmetic: "Рамка «Раскалённый шлак»"},
+	"Владыка Разломов Азгар":   {Title: "Укротитель Пламени", Badge: "🔥", Cosmetic: "Аура «Пепел Азгара»"},
+	"Оракул Тишины":            {Badge: "👁", Cosmetic: "Рамка «Безмолвие»"},
+	"Монарх Бездны Ноктэрн":    {Title: "Победитель Монарха", Badge: "🌌", Cosmetic: "Аура «Сердце Бездны»"},
+}
+
+// FirstWinReward returns what the first victory over enemy grants.
+func FirstWinReward(enemy models.Enemy) models.BattleReward {
+	if reward, ok := enemyRewards[enemy.Name]; ok {
+		return reward
+	}
+	return models.BattleReward{Badge: zoneRewardBadges[enemy.Zone]}
+}
+
+// GetFirstWinReward returns the reward still waiting on enemy, or nil once it was earned.
+func (e *Engine) GetFirstWinReward(enemy models.Enemy) (*models.BattleReward, error) {
+	earned, err := e.DB.GetBattleReward(e.Character.ID, enemy.ID)
+	if err != nil {
+		return nil, err
+	}
+	if earned != nil {
+		return nil, nil
+	}
+	reward := FirstWinReward(enemy)
+	if reward.IsEmpty() {
+		return nil, nil
+	}
+	return &reward, nil
+}
+
+// grantFirstWin awards the enemy's reward table and unlocks the next floor the
+// first time the enemy is beaten. Later victories leave record untouched.
+func (e *Engine) grantFirstWin(record *models.BattleRecord, enemy models.Enemy) error {
+	reward, unlockID, err := e.planFirstWin(record, enemy)
+	if err != nil || reward == nil {
+		return err
+	}
+	if err := e.DB.InsertBattleReward(reward); err != nil {
+		return err
+	}
+	if unlockID > 0 {
+		return e.DB.UnlockEnemy(e.Character.ID, unlockID)
+	}
+	return nil
+}
+
+// planFirstWin works out what grantFirstWin would write without writing it:
+// the reward (nil once earned) and the enemy it unlocks (0 for none). record
+// is filled in as if the reward were granted.
+func (e *Engine) planFirstWin(record *models.BattleRecord, enemy models.Enemy) (*models.BattleReward, int64, error) {
+	earned, err := e.DB.GetBattleReward(e.Character.ID, enemy.ID)
+	if err != nil || earned != nil {
+		return nil, 0, err
+	}
+
+	reward := FirstWinReward(enemy)
+	reward.CharID = e.Character.ID
+	reward.EnemyID = enemy.ID
+	record.RewardTitle = reward.Title
+	record.RewardBadge = reward.Badge
+	record.RewardCosmetic = reward.Cosmetic
+
+	next, err := e.DB.GetEnemiesByFloor(enemy.Floor + 1)
+	if err != nil {
+		return nil, 0, err
+	}
+	var unlockID int64
+	if len(next) > 0 {
+		unlockID = next[0].ID
+		record.UnlockedEnemyName = next[0].Name
+	}
+	return &reward, unlockID, nil
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/models"
+)
+
+// decidedBattle builds a finished fight without going through tower validation.
+func decidedBattle(enemy models.Enemy, result models.BattleResult) *models.BattleState {
+	return &models.BattleState{Enemy: enemy, BattleOver: true, Result: result}
+}
+
+func TestFirstWinGrantsRewardOnce(t *testing.T) {
+	e := newTestEngine(t)
+
+	_, bossEnemy := zoneEnemies(t, e, 1)
+	if reward, err := e.GetFirstWinReward(bossEnemy); err != nil || reward == nil || reward.Title == "" {
+		t.Fatalf("expected a titled reward on offer for the boss, got %+v (err %v)", reward, err)
+	}
+
+	record, err := e.FinishBattle(decidedBattle(bossEnemy, models.BattleWin))
+	if err != nil {
+		t.Fatalf("finish battle: %v", err)
+	}
+	want := FirstWinReward(bossEnemy)
+	if record.RewardTitle != want.Title || record.RewardBadge != want.Badge || record.RewardCosmetic != want.Cosmetic {
+		t.Fatalf("record reward mismatch: %+v", record)
+	}
+
+	titles, err := e.GetAllTitles()
+	if err != nil {
+		t.Fatalf("get titles: %v", err)
+	}
+	found := false
+	for _, title := range titles {
+		if title == want.Title {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %q among titles %v", want.Title, titles)
+	}
+
+	if reward, err := e.GetFirstWinReward(bossEnemy); err != nil || reward != nil {
+		t.Fatalf("reward should no longer be on offer, got %+v (err %v)", reward, err)
+	}
+
+	again, err := e.FinishBattle(decidedBattle(bossEnemy, models.BattleWin))
+	if err != nil {
+		t.Fatalf("finish second battle: %v", err)
+	}
+	if again.RewardTitle != "" || again.RewardBadge != "" || again.UnlockedEnemyName != "" {
+		t.Fatalf("repeat win must not grant rewards: %+v", again)
+	}
+
+	defeated, err := e.DB.GetDefeatedEnemies(e.Character.ID)
+	if err != nil {
+		t.Fatalf("get defeated: %v", err)
+	}
+	for _, d := range defeated {
+		if d.EnemyID == bossEnemy.ID && d.RewardTitle != want.Title {
+			t.Fatalf("gallery entry missing reward: %+v", d)
+		}
+	}
+}
+
+func TestFirstWinUnlocksNextFloor(t *testing.T) {
+	e := newTestEngine(t)
+
+	regular, _ := zoneEnemies(t, e, 1)
+	first := regular[0]
+	record, err := e.FinishBattle(battleWinState(t, e, first.ID))
+	if err != nil {
+		t.Fatalf("finish battle: %v", err)
+	}
+	if record.RewardBadge == "" {
+		t.Fatal("regular enemies should grant their zone badge")
+	}
+
+	next, err := e.DB.GetEnemiesByFloor(first.Floor + 1)
+	if err != nil || len(next) == 0 {
+		t.Fatalf("next floor enemy: %v", err)
+	}
+	unlocked, err := e.DB.GetUnlockedEnemyIDs(e.Character.ID)
+	if err != nil {
+		t.Fatalf("get unlocks: %v", err)
+	}
+	if !unlocked[next[0].ID] || record.UnlockedEnemyName != next[0].Name {
+		t.Fatalf("expected %q to be unlocked, record says %q", next[0].Name, record.UnlockedEnemyName)
+	}
+
+	loss, err := e.FinishBattle(decidedBattle(next[0], models.BattleLose))
+	if err != nil {
+		t.Fatalf("finish lost battle: %v", err)
+	}
+	if loss.RewardBadge != "" {
+		t.Fatal("a loss must not grant rewards")
+	}
+}
@@ -2,9 +2,8 @@ package game
 
 import (
 	"fmt"
-	"math/rand"
-	"time"
 
+	"solo-leveling/internal/database"
 	"solo-leveling/internal/game/combat/boss"
 	"solo-leveling/internal/game/combat/memory"
 	"solo-leveling/internal/models"
@@ -18,51 +17,43 @@ func (e *Engine) StartBossBattle(enemyID int64) (*boss.State, error) {
 	if enemy.Type != models.EnemyBoss {
 		return nil, fmt.Errorf("босс не выбран: текущий враг не является боссом")
 	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		return nil, err
+	}
 	if err := e.spendBattleAttempt(); err != nil {
 		return nil, err
 	}
+	if err := e.adaptEnemy(enemy); err != nil {
+		return nil, err
+	}
 
-	stats, err := e.GetStatLevels()
+	memStats, err := e.combatStats()
 	if err != nil {
 		return nil, err
 	}
 
-	statMap := make(map[models.StatType]int)
-	for _, s := range stats {
-		statMap[s.StatType] = s.Level
-	}
+	playerHP := memory.MaxHP(memStats)
 
-	memStats := memory.Stats{
-		STR: statMap[models.StatStrength],
-		AGI: statMap[models.StatAgility],
-		INT: statMap[models.StatIntellect],
-		STA: statMap[models.StatEndurance],
+	state, err := boss.NewState(*enemy, memStats, playerHP, memory.NewSeed())
+	if err != nil {
+		return nil, err
 	}
-
-	playerHP := memory.PlayerHP(memStats.STA)
-
-	return boss.NewState(*enemy, memStats, playerHP)
+	if err := e.saveBossBattle(state); err != nil {
+		return nil, err
+	}
+	return state, nil
 }
 
 func (e *Engine) ProcessBossMemory(state *boss.State, guesses []int) error {
-	stats, err := e.GetStatLevels()
+	memStats, err := e.combatStats()
 	if err != nil {
 		return err
 	}
 
-	statMap := make(map[models.StatType]int)
-	for _, s := range stats {
-		statMap[s.StatType] = s.Level
-	}
-
-	memStats := memory.Stats{
-		STR: statMap[models.StatStrength],
-		AGI: statMap[models.StatAgility],
-		INT: statMap[models.StatIntellect],
-		STA: statMap[models.StatEndurance],
+	if err := boss.ApplyMemoryInput(state, guesses, memStats, 0); err != nil {
+		return err
 	}
-
-	return boss.ApplyMemoryInput(state, guesses, memStats, 0)
+	return e.saveBossBattle(state)
 }
 
 func (e *Engine) FailBoss(state *boss.State) (*models.BattleRecord, error) {
@@ -74,12 +65,17 @@ func (e *Engine) FailBoss(state *boss.State) (*models.BattleRecord, error) {
 		DamageDealt:  state.DamageDealt,
 		DamageTaken:  state.DamageTaken,
 		Accuracy:     boss.CalcAccuracy(state.TotalHits, state.TotalMisses),
-		CriticalHits: 0,
+		CriticalHits: state.TotalCrits,
 		Dodges:       0,
+		Seed:         state.Seed,
+		Rounds:       state.Rounds,
 	}
 	if err := e.DB.InsertBattle(record); err != nil {
 		return nil, err
 	}
+	if err := e.DB.DeleteActiveBattle(e.Character.ID); err != nil {
+		return nil, err
+	}
 	return record, nil
 }
 
@@ -92,20 +88,35 @@ func (e *Engine) FinishBoss(state *boss.State) (*models.BattleRecord, error) {
 		DamageDealt:  state.DamageDealt,
 		DamageTaken:  state.DamageTaken,
 		Accuracy:     boss.CalcAccuracy(state.TotalHits, state.TotalMisses),
-		CriticalHits: 0,
+		CriticalHits: state.TotalCrits,
 		Dodges:       0,
+		Seed:         state.Seed,
+		Rounds:       state.Rounds,
 	}
 
 	if err := e.UnlockAchievement(AchievementFirstBattle); err != nil {
 		return nil, err
 	}
 
-	if err := e.DB.InsertBattle(record); err != nil {
+	reward, unlockID, err := e.planFirstWin(record, state.Enemy)
+	if err != nil {
 		return nil, err
 	}
-
-	// Add a small delay to mark battle timing variance in history
-	rand.Seed(time.Now().UnixNano())
+	victory := database.BattleVictory{Battle: record, Reward: reward, UnlockEnemyID: unlockID}
+	if state.Enemy.Endless {
+		victory.EndlessFloor = state.Enemy.Floor
+	} else if reward != nil {
+		// Only the first clear of a catalog boss drops gear.
+		if victory.Drop, err = e.pickDrop(state.Enemy.Name, state.Seed); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.DB.RecordVictory(victory); err != nil {
+		return nil, err
+	}
+	if victory.Drop != nil {
+		record.RewardItem = victory.Drop.Item.Name
+	}
 
 	return record, nil
 }
@@ -0,0 +1,330 @@
+// Package catalog is the tower's enemy list. The game seeds its database from
+// it and the balance simulator tunes it, so both always talk about the same
+// enemies. The default list is embedded from enemies.json; a file with the same
+// name in the data directory replaces it.
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"solo-leveling/internal/models"
+)
+
+// OverrideFile is the name of the user catalog looked up in the data directory.
+const OverrideFile = "enemies.json"
+
+// BackupFile keeps the catalog that was live before the last Save.
+const BackupFile = "enemies.json.bak"
+
+//go:embed enemies.json
+var defaultData []byte
+
+// Enemy is one catalog entry before it is stored or simulated.
+type Enemy struct {
+	Index        int // position in the catalog, 0-based
+	Floor        int
+	Zone         int
+	Slot         int // 1-based position inside the zone
+	Name         string
+	Description  string
+	Rank         models.QuestRank
+	Biome        string
+	Role         string
+	Level        int
+	HP           int
+	Attack       int
+	Image        string // file name under assets/enemies, optional
+	IsBoss       bool
+	IsTransition bool
+	// ExpectedMinLevel/ExpectedMaxLevel define the intended player-level window.
+	ExpectedMinLevel int
+	ExpectedMaxLevel int
+	TargetWinRateMin float64
+	TargetWinRateMax float64
+	BossPhases       []models.BossPhase
+}
+
+// File is the on-disk catalog format.
+type File struct {
+	// Tuning records the simulator run that produced the numbers, if any.
+	Tuning   *Tuning           `json:"tuning,omitempty"`
+	RoleLore map[string]string `json:"role_lore"`
+	Zones    []FileZone        `json:"zones"`
+}
+
+// Tuning identifies the auto-tune run written into a catalog file.
+type Tuning struct {
+	Seed        int64   `json:"seed"`
+	RunsPerEval int     `json:"runs_per_eval"`
+	Iterations  int     `json:"iterations"`
+	MinPower    float64 `json:"min_power"`
+	MaxPower    float64 `json:"max_power"`
+	AppliedAt   string  `json:"applied_at"`
+}
+
+// FileZone is one zone of the catalog file; its enemies are listed floor by floor.
+type FileZone struct {
+	Zone       int         `json:"zone"`
+	Biome      string      `json:"biome"`
+	Lore       string      `json:"lore"`
+	BossPhases []FilePhase `json:"boss_phases,omitempty"`
+	Enemies    []FileEnemy `json:"enemies"`
+}
+
+// FileEnemy is one enemy of the catalog file.
+type FileEnemy struct {
+	Name          string     `json:"name"`
+	Rank          string     `json:"rank"`
+	Role          string     `json:"role"`
+	Level         int        `json:"level"`
+	HP            int        `json:"hp"`
+	Attack        int        `json:"attack"`
+	TargetWinRate [2]float64 `json:"target_winrate"`
+	Boss          bool       `json:"boss,omitempty"`
+	Transition    bool       `json:"transition,omitempty"`
+	Image         string     `json:"image,omitempty"`
+	// Description replaces the "<zone lore>: <role lore>" text.
+	Description string `json:"description,omitempty"`
+}
+
+// FilePhase is a scripted boss phase in the catalog file.
+type FilePhase struct {
+	Name        string  `json:"name"`
+	HPThreshold float64 `json:"hp_threshold"`
+	Mechanic    string  `json:"mechanic"`
+	Value       int     `json:"value"`
+}
+
+// Enemies returns the embedded default catalog in floor order.
+func Enemies() []Enemy {
+	enemies, err := Parse(defaultData)
+	if err != nil {
+		panic(fmt.Sprintf("embedded enemy catalog: %v", err))
+	}
+	return enemies
+}
+
+// Load returns the catalog from dir/OverrideFile when it exists and the
+// embedded default otherwise. An invalid override is an error, not a fallback.
+func Load(dir string) ([]Enemy, error) {
+	file, err := ReadFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	return file.Enemies(), nil
+}
+
+// ReadFile returns the validated catalog file Load would use.
+func ReadFile(dir string) (File, error) {
+	raw, err := readSource(dir)
+	if err != nil {
+		return File{}, err
+	}
+	return decode(raw)
+}
+
+// Parse decodes and validates a catalog file.
+func Parse(raw []byte) ([]Enemy, error) {
+	file, err := decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	return file.Enemies(), nil
+}
+
+// Save validates f and writes it as dir/OverrideFile. The catalog that was
+// live before (override or embedded default) is copied to dir/BackupFile first;
+// the returned path points at that backup.
+func (f File) Save(dir string) (string, error) {
+	if err := f.Validate(); err != nil {
+		return "", fmt.Errorf("каталог врагов: %w", err)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	previous, err := readSource(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create data dir: %w", err)
+	}
+	backup := filepath.Join(dir, BackupFile)
+	if err := os.WriteFile(backup, previous, 0644); err != nil {
+		return "", fmt.Errorf("backup catalog: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, OverrideFile), append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("write catalog: %w", err)
+	}
+	return backup, nil
+}
+
+// SetStats replaces HP and attack of the named enemy and reports whether it exists.
+func (f *File) SetStats(name string, hp, attack int) bool {
+	for zi := range f.Zones {
+		for ei := range f.Zones[zi].Enemies {
+			e := &f.Zones[zi].Enemies[ei]
+			if e.Name == name {
+				e.HP = hp
+				e.Attack = attack
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func readSource(dir string) ([]byte, error) {
+	if dir == "" {
+		return defaultData, nil
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, OverrideFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return defaultData, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("каталог врагов: %w", err)
+	}
+	return raw, nil
+}
+
+func decode(raw []byte) (File, error) {
+	var file File
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return File{}, fmt.Errorf("каталог врагов: %w", err)
+	}
+	if err := file.Validate(); err != nil {
+		return File{}, fmt.Errorf("каталог врагов: %w", err)
+	}
+	return file, nil
+}
+
+// Validate checks the rules the tower relies on: every zone has exactly one
+// boss, names are unique and the numbers are usable.
+func (f File) Validate() error {
+	if len(f.Zones) == 0 {
+		return errors.New("нет ни одной зоны")
+	}
+	zones := map[int]bool{}
+	names := map[string]bool{}
+	for _, z := range f.Zones {
+		if z.Zone <= 0 {
+			return fmt.Errorf("неверный номер зоны %d", z.Zone)
+		}
+		if zones[z.Zone] {
+			return fmt.Errorf("зона %d указана дважды", z.Zone)
+		}
+		zones[z.Zone] = true
+
+		bosses := 0
+		for _, e := range z.Enemies {
+			if e.Name == "" {
+				return fmt.Errorf("зона %d: враг без имени", z.Zone)
+			}
+			if names[e.Name] {
+				return fmt.Errorf("имя %q повторяется", e.Name)
+			}
+			names[e.Name] = true
+			if !validRank(e.Rank) {
+				return fmt.Errorf("%s: неизвестный ранг %q", e.Name, e.Rank)
+			}
+			if e.Level <= 0 || e.HP <= 0 || e.Attack <= 0 {
+				return fmt.Errorf("%s: уровень, HP и атака должны быть положительными", e.Name)
+			}
+			if e.TargetWinRate[0] > e.TargetWinRate[1] {
+				return fmt.Errorf("%s: целевой винрейт задан наоборот", e.Name)
+			}
+			if e.Boss {
+				bosses++
+			}
+		}
+		if bosses != 1 {
+			return fmt.Errorf("зона %d: нужен ровно один босс, найдено %d", z.Zone, bosses)
+		}
+		for _, p := range z.BossPhases {
+			if !validMechanic(p.Mechanic) {
+				return fmt.Errorf("зона %d: неизвестная механика фазы %q", z.Zone, p.Mechanic)
+			}
+			if p.HPThreshold <= 0 || p.HPThreshold > 1 {
+				return fmt.Errorf("зона %d: порог фазы %q вне (0, 1]", z.Zone, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Enemies flattens a validated file into catalog entries, numbering floors in file order.
+func (f File) Enemies() []Enemy {
+	var enemies []Enemy
+	floor := 1
+	for _, z := range f.Zones {
+		phases := make([]models.BossPhase, 0, len(z.BossPhases))
+		for _, p := range z.BossPhases {
+			phases = append(phases, models.BossPhase{
+				Name:        p.Name,
+				HPThreshold: p.HPThreshold,
+				Mechanic:    models.BossMechanic(p.Mechanic),
+				Value:       p.Value,
+			})
+		}
+
+		for i, e := range z.Enemies {
+			description := e.Description
+			if description == "" {
+				description = z.Lore
+				if lore := f.RoleLore[e.Role]; lore != "" {
+					description = fmt.Sprintf("%s: %s", z.Lore, lore)
+				}
+			}
+			enemy := Enemy{
+				Index:            len(enemies),
+				Floor:            floor,
+				Zone:             z.Zone,
+				Slot:             i + 1,
+				Name:             e.Name,
+				Description:      description,
+				Rank:             models.QuestRank(e.Rank),
+				Biome:            z.Biome,
+				Role:             e.Role,
+				Level:            e.Level,
+				HP:               e.HP,
+				Attack:           e.Attack,
+				Image:            e.Image,
+				IsBoss:           e.Boss,
+				IsTransition:     e.Transition,
+				ExpectedMinLevel: max(1, e.Level-1),
+				ExpectedMaxLevel: e.Level + 1,
+				TargetWinRateMin: e.TargetWinRate[0],
+				TargetWinRateMax: e.TargetWinRate[1],
+			}
+			if e.Boss && len(phases) > 0 {
+				enemy.BossPhases = phases
+			}
+			enemies = append(enemies, enemy)
+			floor++
+		}
+	}
+	return enemies
+}
+
+func validRank(rank string) bool {
+	switch models.QuestRank(rank) {
+	case models.RankE, models.RankD, models.RankC, models.RankB, models.RankA, models.RankS:
+		return true
+	}
+	return false
+}
+
+func validMechanic(mechanic string) bool {
+	switch models.BossMechanic(mechanic) {
+	case models.BossEnrage, models.BossShield, models.BossDecoys, models.BossLastStand:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validFile = `{
+  "role_lore": {"NORMAL": "обычный враг"},
+  "zones": [{
+    "zone": 1, "biome": "swamp", "lore": "Болота",
+    "boss_phases": [{"name": "Ярость", "hp_threshold": 0.5, "mechanic": "enrage", "value": 20}],
+    "enemies": [
+      {"name": "Волк", "rank": "E", "role": "NORMAL", "level": 1, "hp": 100, "attack": 10, "target_winrate": [0.8, 0.9]},
+      {"name": "Король", "rank": "D", "role": "BOSS", "level": 3, "hp": 300, "attack": 20, "target_winrate": [0.5, 0.6], "boss": true, "image": "king.png"}
+    ]
+  }]
+}`
+
+func TestEmbeddedCatalogIsValid(t *testing.T) {
+	enemies := Enemies()
+	if len(enemies) == 0 {
+		t.Fatal("embedded catalog is empty")
+	}
+	for i, e := range enemies {
+		if e.Floor != i+1 {
+			t.Fatalf("%s: expected floor %d, got %d", e.Name, i+1, e.Floor)
+		}
+	}
+}
+
+func TestParseBuildsEntries(t *testing.T) {
+	enemies, err := Parse([]byte(validFile))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(enemies) != 2 {
+		t.Fatalf("expected 2 enemies, got %d", len(enemies))
+	}
+	wolf, king := enemies[0], enemies[1]
+	if wolf.Description != "Болота: обычный враг" || wolf.Slot != 1 || wolf.Biome != "swamp" {
+		t.Fatalf("unexpected regular entry: %+v", wolf)
+	}
+	if !king.IsBoss || king.Floor != 2 || king.Image != "king.png" || len(king.BossPhases) != 1 {
+		t.Fatalf("unexpected boss entry: %+v", king)
+	}
+	if wolf.BossPhases != nil {
+		t.Fatal("regular enemies must not carry boss phases")
+	}
+}
+
+func TestParseRejectsInvalidCatalogs(t *testing.T) {
+	cases := map[string]struct{ from, to, want string }{
+		"duplicate name": {`"name": "Король"`, `"name": "Волк"`, "повторяется"},
+		"two bosses":     {`"role": "NORMAL", "level": 1`, `"boss": true, "role": "NORMAL", "level": 1`, "ровно один босс"},
+		"no boss":        {`"boss": true`, `"boss": false`, "ровно один босс"},
+		"bad rank":       {`"rank": "E"`, `"rank": "Z"`, "ранг"},
+		"zero hp":        {`"hp": 100`, `"hp": 0`, "положительными"},
+		"bad mechanic":   {`"enrage"`, `"sleep"`, "механика"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			raw := strings.Replace(validFile, tc.from, tc.to, 1)
+			_, err := Parse([]byte(raw))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadPrefersOverride(t *testing.T) {
+	dir := t.TempDir()
+	enemies, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load default: %v", err)
+	}
+	if len(enemies) != len(Enemies()) {
+		t.Fatal("expected embedded catalog without an override")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, OverrideFile), []byte(validFile), 0644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	enemies, err = Load(dir)
+	if err != nil {
+		t.Fatalf("load override: %v", err)
+	}
+	if len(enemies) != 2 || enemies[0].Name != "Волк" {
+		t.Fatalf("override not used: %+v", enemies)
+	}
+}
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"solo-leveling/internal/config"
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/sim"
+)
+
+func TestGameAndSimulatorShareCatalog(t *testing.T) {
+	game := GetPresetEnemies()
+	simulated := sim.GetPresetEnemies()
+	if len(game) != len(simulated) {
+		t.Fatalf("catalog sizes differ: game %d, sim %d", len(game), len(simulated))
+	}
+	for i := range game {
+		g, s := game[i], simulated[i]
+		if g.Name != s.Name || g.HP != s.HP || g.Attack != s.Attack || g.Level != s.Level ||
+			string(g.Rank) != s.Rank || g.Role != s.Role || g.Floor != s.Floor || g.IsBoss != s.IsBoss {
+			t.Fatalf("entry %d differs: game %+v, sim %+v", i, g, s)
+		}
+	}
+}
+
+func TestInitEnemiesSyncsTunedStatsAndKeepsProgress(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+	markDefeated(t, e, current.ID)
+
+	// Stored numbers from an older balance pass.
+	stale := GetPresetEnemies()
+	stale[0].HP += 50
+	stale[0].Attack += 5
+	if err := e.DB.SyncEnemyCatalogStats(stale); err != nil {
+		t.Fatalf("sync stale stats: %v", err)
+	}
+
+	if err := e.InitEnemies(); err != nil {
+		t.Fatalf("init enemies: %v", err)
+	}
+	synced, err := e.DB.GetEnemyByID(current.ID)
+	if err != nil {
+		t.Fatalf("get enemy: %v", err)
+	}
+	if preset := GetPresetEnemies()[0]; synced.HP != preset.HP || synced.Attack != preset.Attack {
+		t.Fatalf("expected catalog stats %d/%d, got %d/%d", preset.HP, preset.Attack, synced.HP, synced.Attack)
+	}
+	defeated, err := e.DB.GetDefeatedEnemyIDs(e.Character.ID)
+	if err != nil {
+		t.Fatalf("get defeated: %v", err)
+	}
+	if !defeated[current.ID] {
+		t.Fatal("syncing stats must not reset battle progress")
+	}
+}
+
+func TestStoredBossesKeepTheirPhases(t *testing.T) {
+	e := newTestEngine(t)
+
+	// Rows stored before phases were kept on the enemy.
+	stale := GetPresetEnemies()
+	for i := range stale {
+		stale[i].BossPhases = nil
+	}
+	if err := e.DB.SyncEnemyCatalogStats(stale); err != nil {
+		t.Fatalf("sync stale catalog: %v", err)
+	}
+	if err := e.InitEnemies(); err != nil {
+		t.Fatalf("init enemies: %v", err)
+	}
+
+	stored, err := e.DB.GetAllEnemies()
+	if err != nil {
+		t.Fatalf("get enemies: %v", err)
+	}
+	preset := GetPresetEnemies()
+	for i, enemy := range stored {
+		if len(enemy.BossPhases) != len(preset[i].BossPhases) {
+			t.Fatalf("%s: expected %d phases, got %d", enemy.Name, len(preset[i].BossPhases), len(enemy.BossPhases))
+		}
+		if enemy.IsBoss && len(enemy.BossPhases) == 0 {
+			t.Fatalf("boss %s must carry its phases", enemy.Name)
+		}
+	}
+}
+
+func TestInitEnemiesRejectsInvalidOverride(t *testing.T) {
+	e := newTestEngine(t)
+	before, err := e.DB.GetAllEnemies()
+	if err != nil {
+		t.Fatalf("get enemies: %v", err)
+	}
+
+	dir, err := config.DataDir()
+	if err != nil {
+		t.Fatalf("data dir: %v", err)
+	}
+	override := `{"zones": [{"zone": 1, "enemies": [{"name": "Волк", "rank": "E", "level": 1, "hp": 100, "attack": 10}]}]}`
+	if err := os.WriteFile(filepath.Join(dir, catalog.OverrideFile), []byte(override), 0644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+
+	if err := e.InitEnemies(); err == nil {
+		t.Fatal("expected an override without a boss to be rejected")
+	}
+	after, err := e.DB.GetAllEnemies()
+	if err != nil {
+		t.Fatalf("get enemies: %v", err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("invalid override must not touch the catalog: %d -> %d enemies", len(before), len(after))
+	}
+}
+
+func TestReseedClearsRecordedRounds(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if err := e.ProcessRound(state, nil); err != nil {
+		t.Fatalf("process round: %v", err)
+	}
+	state.BattleOver = true
+	record, err := e.FinishBattle(state)
+	if err != nil || len(record.Rounds) == 0 {
+		t.Fatalf("expected a battle with recorded rounds, got %+v (%v)", record, err)
+	}
+
+	if err := e.DB.ReplaceEnemyCatalog(GetPresetEnemies()); err != nil {
+		t.Fatalf("reseed after a recorded battle: %v", err)
+	}
+	if rounds, err := e.DB.GetBattleRounds(record.ID); err != nil || len(rounds) != 0 {
+		t.Fatalf("reseed must drop the rounds of cleared battles, got %d (%v)", len(rounds), err)
+	}
+}
+
+func TestReseedDropsSavedFight(t *testing.T) {
+	e := newTestEngine(t)
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("get current enemy: %v", err)
+	}
+	if _, err := e.StartBattle(current.ID); err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+
+	if err := e.DB.ReplaceEnemyCatalog(GetPresetEnemies()); err != nil {
+		t.Fatalf("reseed: %v", err)
+	}
+	if saved, err := e.GetSavedBattle(); err != nil || saved != nil {
+		t.Fatalf("reseed must drop the saved fight, got %+v (%v)", saved, err)
+	}
+}
@@ -0,0 +1,214 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/game/combat/formulas"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Daily challenge
+// ============================================================
+
+const (
+	// challengeRole keeps the challenge on the classic grid whatever the biome.
+	challengeRole = "CHALLENGE"
+	// challengeHPFactor makes the challenge sturdier than the catalog enemy it copies.
+	challengeHPFactor = 1.2
+)
+
+// errChallengePlayed refuses a second challenge fight on the same day.
+var errChallengePlayed = errors.New("испытание дня уже пройдено — приходи завтра")
+
+// challengeModifiers are the rules a day's challenge draws from.
+var challengeModifiers = []models.EnemyModifier{
+	models.ModifierHaste,
+	models.ModifierWideGrid,
+	models.ModifierNoCrit,
+}
+
+// challengeStreakRewards are the cosmetics for consecutive daily clears.
+var challengeStreakRewards = []struct {
+	Days     int
+	Key      string
+	Cosmetic string
+}{
+	{Days: 3, Key: AchievementChallenge3, Cosmetic: "Рамка «Испытатель»"},
+	{Days: 7, Key: AchievementChallenge7, Cosmetic: "Аура «Неделя испытаний»"},
+}
+
+// DailyChallenge is today's challenge and how the character fared in it.
+type DailyChallenge struct {
+	Day    string
+	Enemy  models.Enemy
+	Result *models.BattleRecord // nil until fought today
+	Streak int                  // consecutive days cleared, today included once won
+}
+
+// Played reports whether today's challenge has been fought.
+func (c DailyChallenge) Played() bool {
+	return c.Result != nil
+}
+
+// ChallengeSeed turns a date into the seed shared by every player on that day.
+func ChallengeSeed(day time.Time) int64 {
+	y, m, d := day.Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// GenerateDailyChallenge builds the challenge enemy for day. It copies a
+// regular catalog enemy picked by the date and adds one to three challenge
+// modifiers, so everyone meets the same fight on the same date.
+func GenerateDailyChallenge(entries []catalog.Enemy, day time.Time) models.Enemy {
+	var regulars []catalog.Enemy
+	for _, c := range entries {
+		if !c.IsBoss {
+			regulars = append(regulars, c)
+		}
+	}
+	if len(regulars) == 0 {
+		return models.Enemy{}
+	}
+
+	rng := formulas.RoundSource(ChallengeSeed(day), 0)
+	template := regulars[rng.Intn(len(regulars))]
+	count := 1 + rng.Intn(len(challengeModifiers))
+	mods := make([]models.EnemyModifier, 0, count)
+	for _, idx := range rng.Perm(len(challengeModifiers))[:count] {
+		mods = append(mods, challengeModifiers[idx])
+	}
+
+	return models.Enemy{
+		Name:        "Испытание: " + template.Name,
+		Description: fmt.Sprintf("Испытание дня %s. %s", day.Format("02.01.2006"), template.Description),
+		Rank:        template.Rank,
+		Type:        models.EnemyRegular,
+		Level:       template.Level,
+		HP:          int(math.Round(float64(template.HP) * challengeHPFactor)),
+		Attack:      template.Attack,
+		Biome:       template.Biome,
+		Role:        challengeRole,
+		Modifiers:   mods,
+	}
+}
+
+// GetDailyChallenge returns today's challenge with the character's result and streak.
+func (e *Engine) GetDailyChallenge() (*DailyChallenge, error) {
+	now := time.Now()
+	day := now.Format("2006-01-02")
+	result, err := e.DB.GetChallengeResult(e.Character.ID, day)
+	if err != nil {
+		return nil, err
+	}
+	// The challenge reads the same catalog as InitEnemies, override included.
+	entries, err := loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	streak, err := e.GetChallengeStreak()
+	if err != nil {
+		return nil, err
+	}
+	return &DailyChallenge{
+		Day:    day,
+		Enemy:  GenerateDailyChallenge(entries, now),
+		Result: result,
+		Streak: streak,
+	}, nil
+}
+
+// StartDailyChallenge opens today's challenge fight. It costs no battle
+// attempt but can be fought once per day; an abandoned challenge counts.
+func (e *Engine) StartDailyChallenge() (*models.BattleState, error) {
+	challenge, err := e.GetDailyChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Played() {
+		return nil, errChallengePlayed
+	}
+
+	stats, err := e.combatStats()
+	if err != nil {
+		return nil, err
+	}
+	day, err := time.ParseInLocation("2006-01-02", challenge.Day, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	// Forfeit only once the challenge can start. Forfeiting today's saved
+	// challenge itself uses up the day.
+	forfeited, err := e.ForfeitSavedBattle()
+	if err != nil {
+		return nil, err
+	}
+	if forfeited != nil && forfeited.ChallengeDay == challenge.Day {
+		return nil, errChallengePlayed
+	}
+	state, err := newBattleState(challenge.Enemy, stats, ChallengeSeed(day))
+	if err != nil {
+		return nil, err
+	}
+	state.ChallengeDay = challenge.Day
+	if err := e.saveBattle(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// GetChallengeStreak counts consecutive days with a cleared challenge. A streak
+// that reached yesterday is still alive until today's challenge is lost or skipped.
+func (e *Engine) GetChallengeStreak() (int, error) {
+	days, err := e.DB.GetChallengeWinDays(e.Character.ID)
+	if err != nil {
+		return 0, err
+	}
+	return challengeStreak(days, time.Now()), nil
+}
+
+// grantChallengeStreak unlocks the cosmetics the current streak has earned.
+func (e *Engine) grantChallengeStreak(record *models.BattleRecord) error {
+	streak, err := e.GetChallengeStreak()
+	if err != nil {
+		return err
+	}
+	for _, reward := range challengeStreakRewards {
+		if streak < reward.Days {
+			continue
+		}
+		unlocked, err := e.DB.UnlockAchievement(reward.Key)
+		if err != nil {
+			return err
+		}
+		if unlocked {
+			record.RewardCosmetic = reward.Cosmetic
+		}
+	}
+	return nil
+}
+
+// challengeStreak walks days (newest first) back from now.
+func challengeStreak(days []string, now time.Time) int {
+	const layout = "2006-01-02"
+	cursor := now
+	streak := 0
+	for i, day := range days {
+		if day == cursor.Format(layout) {
+			streak++
+			cursor = cursor.AddDate(0, 0, -1)
+			continue
+		}
+		if i == 0 && day == cursor.AddDate(0, 0, -1).Format(layout) {
+			streak++
+			cursor = cursor.AddDate(0, 0, -2)
+			continue
+		}
+		break
+	}
+	return streak
+}
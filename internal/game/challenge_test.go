@@ -0,0 +1,188 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"solo-leveling/internal/config"
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/models"
+)
+
+func TestGenerateDailyChallengeDependsOnlyOnDate(t *testing.T) {
+	entries := catalog.Enemies()
+	day := time.Date(2026, 3, 14, 8, 0, 0, 0, time.Local)
+
+	a := GenerateDailyChallenge(entries, day)
+	b := GenerateDailyChallenge(entries, day.Add(10*time.Hour))
+	if a.Name != b.Name || a.HP != b.HP || len(a.Modifiers) != len(b.Modifiers) {
+		t.Fatalf("same date must give the same challenge: %+v vs %+v", a, b)
+	}
+	if a.Role != challengeRole || len(a.Modifiers) == 0 || a.IsBoss {
+		t.Fatalf("unexpected challenge enemy: %+v", a)
+	}
+
+	differs := false
+	for i := 1; i <= 7 && !differs; i++ {
+		other := GenerateDailyChallenge(entries, day.AddDate(0, 0, i))
+		differs = other.Name != a.Name || len(other.Modifiers) != len(a.Modifiers)
+	}
+	if !differs {
+		t.Fatal("challenge should change from day to day")
+	}
+}
+
+func TestDailyChallengeOncePerDayWithoutAttempts(t *testing.T) {
+	e := newTestEngine(t)
+	rules := models.DefaultAttemptRules()
+	e.AttemptRules = &rules
+	if _, err := e.DB.ClaimDailyAttempts(e.Character.ID, time.Now().Format("2006-01-02"), 0, 0); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	before := e.GetAttempts()
+
+	state, err := e.StartDailyChallenge()
+	if err != nil {
+		t.Fatalf("start challenge: %v", err)
+	}
+	if state.ChallengeDay == "" || len(state.Enemy.Modifiers) == 0 {
+		t.Fatalf("challenge state not marked: %+v", state.Enemy)
+	}
+	state.BattleOver = true
+	state.Result = models.BattleWin
+	record, err := e.FinishBattle(state)
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if record.ChallengeDay != state.ChallengeDay {
+		t.Fatal("battle record must carry the challenge marker")
+	}
+	if e.GetAttempts() != before {
+		t.Fatalf("the challenge must not spend attempts: %d -> %d", before, e.GetAttempts())
+	}
+
+	if _, err := e.StartDailyChallenge(); err == nil {
+		t.Fatal("the challenge can be fought once per day")
+	}
+	challenge, err := e.GetDailyChallenge()
+	if err != nil || !challenge.Played() || challenge.Streak != 1 {
+		t.Fatalf("expected a cleared challenge with streak 1, got %+v (%v)", challenge, err)
+	}
+	defeated, _ := e.DB.GetDefeatedEnemyIDs(e.Character.ID)
+	if current, _ := e.GetCurrentEnemy(); current == nil || defeated[current.ID] {
+		t.Fatal("the challenge must not move tower progress")
+	}
+}
+
+func TestDailyChallengeStreakGrantsCosmetic(t *testing.T) {
+	e := newTestEngine(t)
+	now := time.Now()
+	for i := 1; i <= 2; i++ {
+		if err := e.DB.InsertBattle(&models.BattleRecord{
+			CharID:       e.Character.ID,
+			EnemyName:    "Испытание",
+			Result:       models.BattleWin,
+			ChallengeDay: now.AddDate(0, 0, -i).Format("2006-01-02"),
+		}); err != nil {
+			t.Fatalf("insert past challenge: %v", err)
+		}
+	}
+
+	state, err := e.StartDailyChallenge()
+	if err != nil {
+		t.Fatalf("start challenge: %v", err)
+	}
+	state.BattleOver = true
+	state.Result = models.BattleWin
+	record, err := e.FinishBattle(state)
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if record.RewardCosmetic != "Рамка «Испытатель»" {
+		t.Fatalf("expected the 3-day cosmetic, got %q", record.RewardCosmetic)
+	}
+}
+
+func TestChallengeStreakCountsConsecutiveDays(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.Local)
+	cases := []struct {
+		days []string
+		want int
+	}{
+		{[]string{"2026-05-10", "2026-05-09", "2026-05-07"}, 2},
+		{[]string{"2026-05-09", "2026-05-08"}, 2},
+		{[]string{"2026-05-08"}, 0},
+		{nil, 0},
+	}
+	for _, tc := range cases {
+		if got := challengeStreak(tc.days, now); got != tc.want {
+			t.Fatalf("%v: expected streak %d, got %d", tc.days, tc.want, got)
+		}
+	}
+}
+
+func TestPlayedChallengeKeepsSavedTowerFight(t *testing.T) {
+	e := newTestEngine(t)
+	if err := e.DB.InsertBattle(&models.BattleRecord{
+		CharID:       e.Character.ID,
+		EnemyName:    "Испытание",
+		Result:       models.BattleWin,
+		ChallengeDay: time.Now().Format("2006-01-02"),
+	}); err != nil {
+		t.Fatalf("insert today's challenge: %v", err)
+	}
+	current, _ := e.GetCurrentEnemy()
+	if _, err := e.StartBattle(current.ID); err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+
+	if _, err := e.StartDailyChallenge(); err == nil {
+		t.Fatal("a played challenge must not start again")
+	}
+	saved, err := e.GetSavedBattle()
+	if err != nil || saved == nil || saved.Battle.Enemy.ID != current.ID {
+		t.Fatalf("the saved tower fight must survive a refused challenge, got %+v (%v)", saved, err)
+	}
+}
+
+func TestForfeitingSavedChallengeUsesUpTheDay(t *testing.T) {
+	e := newTestEngine(t)
+	if _, err := e.StartDailyChallenge(); err != nil {
+		t.Fatalf("start challenge: %v", err)
+	}
+	if _, err := e.StartDailyChallenge(); err == nil {
+		t.Fatal("restarting the challenge must count the abandoned one")
+	}
+	if challenge, _ := e.GetDailyChallenge(); !challenge.Played() {
+		t.Fatal("the abandoned challenge must be recorded")
+	}
+}
+
+func TestDailyChallengeUsesCatalogOverride(t *testing.T) {
+	e := newTestEngine(t)
+	dir, err := config.DataDir()
+	if err != nil {
+		t.Fatalf("data dir: %v", err)
+	}
+	file, err := catalog.ReadFile(dir)
+	if err != nil {
+		t.Fatalf("read catalog: %v", err)
+	}
+	for z := range file.Zones {
+		for i := range file.Zones[z].Enemies {
+			file.Zones[z].Enemies[i].Name += " (настроен)"
+		}
+	}
+	if _, err := file.Save(dir); err != nil {
+		t.Fatalf("save override: %v", err)
+	}
+
+	challenge, err := e.GetDailyChallenge()
+	if err != nil {
+		t.Fatalf("daily challenge: %v", err)
+	}
+	if !strings.HasSuffix(challenge.Enemy.Name, " (настроен)") {
+		t.Fatalf("the challenge must be built from the override, got %q", challenge.Enemy.Name)
+	}
+}
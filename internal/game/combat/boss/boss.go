@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"math/rand"
+	"sort"
 	"time"
 
 	"solo-leveling/internal/game/combat/memory"
@@ -24,10 +24,20 @@ type MemoryRound struct {
 	CellsToShow int
 	ShowTimeMs  int
 	ShownCells  []int
+	// DecoyCells are highlighted as traps while the decoy phase is active.
+	DecoyCells []int
 }
 
+const (
+	// minEnragedShowMs keeps an enraged highlight phase readable.
+	minEnragedShowMs = 600
+	// decoyDamagePercent is the share of boss attack dealt per clicked decoy.
+	decoyDamagePercent = 50
+)
+
 type State struct {
 	Enemy       models.Enemy
+	Seed        int64
 	Phase       Phase
 	Round       int
 	PlayerHP    int
@@ -36,6 +46,12 @@ type State struct {
 	EnemyMaxHP  int
 	Memory      MemoryRound
 
+	// Phases is the boss script; the first PhasesTriggered of them are active.
+	Phases          []models.BossPhase
+	PhasesTriggered int
+	Shield          int
+	LastStandUsed   bool
+
 	DamageDealt int
 	DamageTaken int
 	TotalHits   int
@@ -49,35 +65,34 @@ type State struct {
 	LastRoundTotal    int
 	LastRoundAccuracy float64
 	LastRoundCrit     bool
+	LastRoundDecoys   int
 	RoundLog          []string
-}
-
-func NewState(enemy models.Enemy, stats memory.Stats, playerHP int) (*State, error) {
-	gridSize := memory.GridSize(enemy)
-	cellsToShow := memory.CellsToShow(enemy, stats)
-	showTimeMs := memory.TimeToShow(stats)
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	shown, err := memory.GenerateShownCells(gridSize, cellsToShow, rng)
-	if err != nil {
-		return nil, err
-	}
+	// Full per-round recording for replays; RoundStartedAt times the current round.
+	Rounds         []models.BattleRound
+	RoundStartedAt time.Time
+}
 
+// NewState opens a boss fight whose randomness is fully determined by seed.
+func NewState(enemy models.Enemy, stats memory.Stats, playerHP int, seed int64) (*State, error) {
 	st := &State{
 		Enemy:       enemy,
+		Seed:        seed,
 		Phase:       PhaseMemory,
 		Round:       1,
 		PlayerHP:    playerHP,
 		PlayerMaxHP: playerHP,
 		EnemyHP:     enemy.HP,
 		EnemyMaxHP:  enemy.HP,
+		Phases:      sortedPhases(enemy.BossPhases),
 		Memory: MemoryRound{
-			GridSize:    gridSize,
-			CellsToShow: cellsToShow,
-			ShowTimeMs:  showTimeMs,
-			ShownCells:  shown,
+			GridSize: memory.GridSize(enemy),
 		},
 	}
+	st.triggerPhases()
+	if err := st.dealMemoryRound(stats, memory.RoundRNG(seed, 0)); err != nil {
+		return nil, err
+	}
 	return st, nil
 }
 
@@ -86,9 +101,10 @@ func ApplyMemoryInput(state *State, choices []int, stats memory.Stats, bonusAtta
 		return errors.New("not in memory phase")
 	}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng := state.RNG()
 	accuracy := memory.ComputeAccuracy(state.Memory.ShownCells, choices)
 	hits := memory.CorrectClicks(state.Memory.ShownCells, choices)
+	decoys := memory.CorrectClicks(state.Memory.DecoyCells, choices)
 	total := state.Memory.CellsToShow
 	misses := total - hits
 	if misses < 0 {
@@ -98,8 +114,32 @@ func ApplyMemoryInput(state *State, choices []int, stats memory.Stats, bonusAtta
 	damage, isCrit := memory.ComputePlayerDamage(stats, accuracy, rng)
 	damage += bonusAttack
 	enemyDamage := memory.ComputeEnemyDamage(state.Enemy, stats, accuracy, rng)
+	if phase, ok := state.ActivePhase(models.BossLastStand); ok {
+		enemyDamage = enemyDamage * (100 + phase.Value) / 100
+	}
+	enemyDamage += decoys * state.Enemy.Attack * decoyDamagePercent / 100
+
+	var phaseLog []string
+	if state.Shield > 0 {
+		if accuracy >= 1 {
+			state.Shield--
+			phaseLog = append(phaseLog, fmt.Sprintf("🛡 Щит пробит! Осталось слоёв: %d", state.Shield))
+		} else {
+			phaseLog = append(phaseLog, "🛡 Щит поглотил удар — нужен идеальный раунд")
+		}
+		damage = 0
+		isCrit = false
+	}
 
 	state.EnemyHP -= damage
+	if state.EnemyHP <= 0 && !state.LastStandUsed {
+		if _, ok := state.phase(models.BossLastStand); ok {
+			// The boss refuses to fall and drops straight into its last stand.
+			state.EnemyHP = 1
+			state.LastStandUsed = true
+			phaseLog = append(phaseLog, "💢 Босс выстоял на последнем издыхании!")
+		}
+	}
 	state.PlayerHP -= enemyDamage
 	state.DamageDealt += damage
 	state.DamageTaken += enemyDamage
@@ -115,42 +155,155 @@ func ApplyMemoryInput(state *State, choices []int, stats memory.Stats, bonusAtta
 	state.LastRoundTotal = total
 	state.LastRoundAccuracy = accuracy
 	state.LastRoundCrit = isCrit
+	state.LastRoundDecoys = decoys
+
+	state.Rounds = append(state.Rounds, models.BattleRound{
+		Round:       state.Round,
+		Minigame:    models.MinigameGridMemory,
+		GridSize:    state.Memory.GridSize,
+		ShownCells:  append([]int(nil), state.Memory.ShownCells...),
+		DecoyCells:  append([]int(nil), state.Memory.DecoyCells...),
+		Choices:     append([]int(nil), choices...),
+		Accuracy:    accuracy,
+		DamageDealt: damage,
+		DamageTaken: enemyDamage,
+		IsCrit:      isCrit,
+		ShowTimeMs:  state.Memory.ShowTimeMs,
+		DurationMs:  models.RoundDurationMs(state.RoundStartedAt, time.Now()),
+	})
 
 	logLine := fmt.Sprintf("Раунд %d: %.0f%% (%d/%d) → %d урона", state.Round, accuracy*100, hits, total, damage)
 	state.RoundLog = append(state.RoundLog, logLine)
 	if isCrit {
 		state.RoundLog = append(state.RoundLog, "⚡ Крит! x1.5")
 	}
+	if decoys > 0 {
+		state.RoundLog = append(state.RoundLog, fmt.Sprintf("☠ Ловушки задеты: %d", decoys))
+	}
 	if enemyDamage > 0 {
 		state.RoundLog = append(state.RoundLog, fmt.Sprintf("Враг атакует: -%d HP", enemyDamage))
 	}
-	if len(state.RoundLog) > 6 {
-		state.RoundLog = state.RoundLog[len(state.RoundLog)-6:]
-	}
+	state.RoundLog = append(state.RoundLog, phaseLog...)
 
 	if state.EnemyHP <= 0 {
 		state.EnemyHP = 0
 		state.Phase = PhaseWin
+		state.trimLog()
 		return nil
 	}
 	if state.PlayerHP <= 0 {
 		state.PlayerHP = 0
 		state.Phase = PhaseLose
+		state.trimLog()
 		return nil
 	}
 
+	for _, phase := range state.triggerPhases() {
+		state.RoundLog = append(state.RoundLog, fmt.Sprintf("🔥 Новая фаза: %s", phase.Name))
+	}
+	state.trimLog()
+
 	state.Round++
-	state.Memory.CellsToShow = memory.CellsToShow(state.Enemy, stats)
-	state.Memory.ShowTimeMs = memory.TimeToShow(stats)
-	shown, err := memory.GenerateShownCells(state.Memory.GridSize, state.Memory.CellsToShow, rng)
+	return state.dealMemoryRound(stats, rng)
+}
+
+// ActivePhase reports whether a triggered phase with the given mechanic is in effect.
+func (s *State) ActivePhase(mechanic models.BossMechanic) (models.BossPhase, bool) {
+	for _, phase := range s.Phases[:s.PhasesTriggered] {
+		if phase.Mechanic == mechanic {
+			return phase, true
+		}
+	}
+	return models.BossPhase{}, false
+}
+
+// CurrentPhaseName names the latest triggered phase, or "" before the first one.
+func (s *State) CurrentPhaseName() string {
+	if s.PhasesTriggered == 0 {
+		return ""
+	}
+	return s.Phases[s.PhasesTriggered-1].Name
+}
+
+// phase finds a scripted phase by mechanic, triggered or not.
+func (s *State) phase(mechanic models.BossMechanic) (models.BossPhase, bool) {
+	for _, phase := range s.Phases {
+		if phase.Mechanic == mechanic {
+			return phase, true
+		}
+	}
+	return models.BossPhase{}, false
+}
+
+// triggerPhases activates every phase whose HP threshold has been reached and
+// returns the newly triggered ones.
+func (s *State) triggerPhases() []models.BossPhase {
+	var triggered []models.BossPhase
+	for s.PhasesTriggered < len(s.Phases) {
+		next := s.Phases[s.PhasesTriggered]
+		if s.EnemyMaxHP > 0 && float64(s.EnemyHP)/float64(s.EnemyMaxHP) > next.HPThreshold {
+			break
+		}
+		s.PhasesTriggered++
+		if next.Mechanic == models.BossShield {
+			s.Shield = next.Value
+		}
+		triggered = append(triggered, next)
+	}
+	return triggered
+}
+
+// dealMemoryRound generates the next field with the active phases applied.
+func (s *State) dealMemoryRound(stats memory.Stats, rng memory.RNG) error {
+	cells := memory.CellsToShow(s.Enemy, stats)
+	showTimeMs := memory.TimeToShow(s.Enemy, stats)
+	if phase, ok := s.ActivePhase(models.BossEnrage); ok {
+		showTimeMs = showTimeMs * phase.Value / 100
+		if showTimeMs < minEnragedShowMs {
+			showTimeMs = minEnragedShowMs
+		}
+	}
+	decoys := 0
+	if phase, ok := s.ActivePhase(models.BossDecoys); ok {
+		decoys = phase.Value
+	}
+	if free := s.Memory.GridSize*s.Memory.GridSize - cells; decoys > free {
+		decoys = free
+	}
+
+	field, err := memory.GenerateShownCells(s.Memory.GridSize, cells+decoys, rng)
 	if err != nil {
 		return err
 	}
-	state.Memory.ShownCells = shown
-
+	s.Memory.CellsToShow = cells
+	s.Memory.ShowTimeMs = showTimeMs
+	s.Memory.ShownCells = field[:cells]
+	s.Memory.DecoyCells = nil
+	if decoys > 0 {
+		s.Memory.DecoyCells = field[cells:]
+	}
+	s.RoundStartedAt = time.Now()
 	return nil
 }
 
+func (s *State) trimLog() {
+	if len(s.RoundLog) > 6 {
+		s.RoundLog = s.RoundLog[len(s.RoundLog)-6:]
+	}
+}
+
+// sortedPhases orders a boss script from the highest HP threshold down.
+func sortedPhases(phases []models.BossPhase) []models.BossPhase {
+	out := append([]models.BossPhase(nil), phases...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].HPThreshold > out[j].HPThreshold })
+	return out
+}
+
+// RNG returns the random source of the current round.
+func (s *State) RNG() memory.RNG {
+	return memory.RoundRNG(s.Seed, s.Round)
+}
+
 func CalcAccuracy(totalHits, totalMisses int) float64 {
 	total := totalHits + totalMisses
 	if total == 0 {
@@ -0,0 +1,149 @@
+package boss
+
+import (
+	"testing"
+
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+func testBoss(phases ...models.BossPhase) models.Enemy {
+	return models.Enemy{
+		Name:       "Тестовый Босс",
+		Rank:       models.RankE,
+		Type:       models.EnemyBoss,
+		IsBoss:     true,
+		HP:         1000,
+		Attack:     10,
+		BossPhases: phases,
+	}
+}
+
+func TestPhasesTriggerAtThresholdsInOrder(t *testing.T) {
+	st, err := NewState(testBoss(
+		models.BossPhase{Name: "Вторая", HPThreshold: 0.3, Mechanic: models.BossEnrage, Value: 50},
+		models.BossPhase{Name: "Первая", HPThreshold: 0.6, Mechanic: models.BossDecoys, Value: 2},
+	), memory.Stats{}, 100, 1)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	if st.PhasesTriggered != 0 || st.CurrentPhaseName() != "" {
+		t.Fatalf("no phase expected at full HP, got %d", st.PhasesTriggered)
+	}
+
+	st.EnemyHP = 500
+	if got := st.triggerPhases(); len(got) != 1 || got[0].Name != "Первая" {
+		t.Fatalf("expected the 0.6 phase first, got %+v", got)
+	}
+	st.EnemyHP = 100
+	if got := st.triggerPhases(); len(got) != 1 || got[0].Name != "Вторая" {
+		t.Fatalf("expected the 0.3 phase next, got %+v", got)
+	}
+	if _, ok := st.ActivePhase(models.BossEnrage); !ok {
+		t.Fatal("enrage should be active")
+	}
+}
+
+func TestEnrageShortensShowTime(t *testing.T) {
+	stats := memory.Stats{INT: 10}
+	st, err := NewState(testBoss(models.BossPhase{Name: "Ярость", HPThreshold: 1, Mechanic: models.BossEnrage, Value: 50}), stats, 100, 1)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	want := memory.TimeToShow(st.Enemy, stats) / 2
+	if st.Memory.ShowTimeMs != want {
+		t.Fatalf("expected enraged show time %d, got %d", want, st.Memory.ShowTimeMs)
+	}
+}
+
+func TestShieldNeedsPerfectRound(t *testing.T) {
+	stats := memory.Stats{STR: 10}
+	st, err := NewState(testBoss(models.BossPhase{Name: "Щит", HPThreshold: 1, Mechanic: models.BossShield, Value: 1}), stats, 10000, 1)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	if st.Shield != 1 {
+		t.Fatalf("expected one shield layer, got %d", st.Shield)
+	}
+
+	// An imperfect round is fully absorbed.
+	if err := ApplyMemoryInput(st, st.Memory.ShownCells[:1], stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	if st.EnemyHP != st.EnemyMaxHP || st.Shield != 1 {
+		t.Fatalf("imperfect round must not hurt the boss: hp=%d shield=%d", st.EnemyHP, st.Shield)
+	}
+
+	// A perfect round breaks the layer; the next one deals damage.
+	if err := ApplyMemoryInput(st, st.Memory.ShownCells, stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	if st.Shield != 0 || st.EnemyHP != st.EnemyMaxHP {
+		t.Fatalf("perfect round should only break the shield: hp=%d shield=%d", st.EnemyHP, st.Shield)
+	}
+	if err := ApplyMemoryInput(st, st.Memory.ShownCells, stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	if st.EnemyHP >= st.EnemyMaxHP {
+		t.Fatal("boss should take damage once the shield is down")
+	}
+}
+
+func TestDecoysAreSeparateAndPunishClicks(t *testing.T) {
+	stats := memory.Stats{}
+	enemy := testBoss(models.BossPhase{Name: "Ловушки", HPThreshold: 1, Mechanic: models.BossDecoys, Value: 3})
+	st, err := NewState(enemy, stats, 10000, 5)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	if len(st.Memory.DecoyCells) != 3 {
+		t.Fatalf("expected 3 decoys, got %d", len(st.Memory.DecoyCells))
+	}
+	if memory.CorrectClicks(st.Memory.ShownCells, st.Memory.DecoyCells) != 0 {
+		t.Fatal("decoys must not overlap real cells")
+	}
+
+	clean, _ := NewState(enemy, stats, 10000, 5)
+	if err := ApplyMemoryInput(clean, clean.Memory.ShownCells, stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	trapped := append(append([]int(nil), st.Memory.ShownCells...), st.Memory.DecoyCells...)
+	if err := ApplyMemoryInput(st, trapped, stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	if st.LastRoundDecoys != 3 {
+		t.Fatalf("expected 3 decoy clicks, got %d", st.LastRoundDecoys)
+	}
+	if len(st.Rounds) != 1 || len(st.Rounds[0].DecoyCells) != 3 {
+		t.Fatalf("the recorded round must keep its decoys: %+v", st.Rounds)
+	}
+	if st.LastRoundEnemyDmg <= clean.LastRoundEnemyDmg {
+		t.Fatalf("decoy clicks should add damage: %d vs %d", st.LastRoundEnemyDmg, clean.LastRoundEnemyDmg)
+	}
+}
+
+func TestLastStandSurvivesLethalBlow(t *testing.T) {
+	stats := memory.Stats{STR: 10}
+	st, err := NewState(testBoss(models.BossPhase{Name: "Последний Рубеж", HPThreshold: 0.1, Mechanic: models.BossLastStand, Value: 50}), stats, 10000, 1)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	st.EnemyHP = 5
+
+	if err := ApplyMemoryInput(st, st.Memory.ShownCells, stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	if st.Phase != PhaseMemory || st.EnemyHP != 1 {
+		t.Fatalf("boss should survive at 1 HP, got phase=%s hp=%d", st.Phase, st.EnemyHP)
+	}
+	if _, ok := st.ActivePhase(models.BossLastStand); !ok {
+		t.Fatal("last stand should be active after surviving")
+	}
+
+	if err := ApplyMemoryInput(st, st.Memory.ShownCells, stats, 0); err != nil {
+		t.Fatalf("ApplyMemoryInput: %v", err)
+	}
+	if st.Phase != PhaseWin {
+		t.Fatalf("last stand saves the boss only once, got phase=%s", st.Phase)
+	}
+}
@@ -0,0 +1,252 @@
+// Package formulas holds the combat numbers shared by the game engine and the
+// balance simulator. It depends on nothing but the standard library so both
+// sides can import it; anything that differs between them is a bug.
+package formulas
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	RegularGridSize = 6
+	BossGridSize    = 8
+
+	MinCellsToShow = 4
+	// bossExtraCells is added to the base before INT reduction, bossBonusCells after it.
+	bossExtraCells = 2
+	bossBonusCells = 3
+
+	baseShowSeconds   = 2.5
+	showSecondsPerINT = 0.05
+	minShowSeconds    = 2.0
+	maxShowSeconds    = 4.0
+
+	CritMultiplier = 1.5
+	critPerAGI     = 0.015
+
+	enemyDamageMinFactor = 0.8
+	enemyDamageMaxFactor = 1.2
+	staDamageReduction   = 0.25
+
+	lowAccuracyPenaltyThreshold  = 0.5
+	lowAccuracyPenaltyMultiplier = 1.2
+
+	// Keep this as a constant so tuning does not leak into UI code.
+	enableLowAccuracyPenalty = true
+
+	// regenPercent is the share of max HP a regenerating enemy restores each round.
+	regenPercent = 4
+)
+
+// RNG is the random source the damage formulas draw from.
+type RNG interface {
+	Float64() float64
+}
+
+// roundSeedStride spreads per-round seeds apart so neighbouring rounds do not share streams.
+const roundSeedStride = 1_000_003
+
+// RoundSource returns the random source for one round of a seeded battle.
+// Round 0 generates the opening field; round N resolves round N and deals round N+1.
+func RoundSource(seed int64, round int) *rand.Rand {
+	return rand.New(&splitMix{state: uint64(seed + int64(round)*roundSeedStride)})
+}
+
+// splitMix is a splitmix64 generator. Unlike math/rand's default source it
+// costs nothing to seed, which matters when the simulator deals millions of rounds.
+type splitMix struct {
+	state uint64
+}
+
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// GridSize selects the memory field size.
+func GridSize(isBoss bool) int {
+	if isBoss {
+		return BossGridSize
+	}
+	return RegularGridSize
+}
+
+// BaseCellsByRank maps enemy rank to base highlighted cells.
+func BaseCellsByRank(rank string) int {
+	switch rank {
+	case "E":
+		return 6
+	case "D":
+		return 8
+	case "C":
+		return 10
+	case "B":
+		return 12
+	case "A":
+		return 14
+	case "S":
+		return 16
+	default:
+		return 8
+	}
+}
+
+// CellsToShow calculates the number of highlighted cells. relief lowers the
+// target before the minimum is applied (swamp fog uses it).
+func CellsToShow(rank string, isBoss bool, intStat int, relief int) int {
+	baseCells := BaseCellsByRank(rank)
+	if isBoss {
+		baseCells += bossExtraCells
+	}
+
+	cells := baseCells - intStat/3 - relief
+	if cells < MinCellsToShow {
+		cells = MinCellsToShow
+	}
+	if isBoss {
+		cells += bossBonusCells
+	}
+
+	maxCells := GridSize(isBoss) * GridSize(isBoss)
+	if cells > maxCells {
+		cells = maxCells
+	}
+	return cells
+}
+
+// ShowSeconds returns the highlight phase duration: 2.5s + INT*0.05s, clamped to [2, 4].
+func ShowSeconds(intStat int) float64 {
+	seconds := baseShowSeconds + float64(intStat)*showSecondsPerINT
+	if seconds < minShowSeconds {
+		seconds = minShowSeconds
+	}
+	if seconds > maxShowSeconds {
+		seconds = maxShowSeconds
+	}
+	return seconds
+}
+
+// PlayerHP returns base HP: 100 + STA*12.
+func PlayerHP(sta int) int {
+	if sta < 0 {
+		sta = 0
+	}
+	return 100 + sta*12
+}
+
+// CritChance returns crit probability from AGI (1.5% per level).
+func CritChance(agi int) float64 {
+	if agi < 0 {
+		return 0
+	}
+	chance := float64(agi) * critPerAGI
+	if chance > 1 {
+		return 1
+	}
+	return chance
+}
+
+// BasePlayerDamage returns damage before accuracy multiplier.
+func BasePlayerDamage(str int) int {
+	return 10 + str*2
+}
+
+// PlayerDamage applies accuracy and crit to outgoing damage.
+func PlayerDamage(str, agi int, accuracy float64, rng RNG) (int, bool) {
+	return PlayerDamageWithCrit(str, CritChance(agi), accuracy, rng)
+}
+
+// PlayerDamageWithCrit is PlayerDamage with the crit chance given directly.
+func PlayerDamageWithCrit(str int, critChance, accuracy float64, rng RNG) (int, bool) {
+	if accuracy < 0 {
+		accuracy = 0
+	}
+	if accuracy > 1 {
+		accuracy = 1
+	}
+
+	raw := float64(BasePlayerDamage(str)) * accuracy
+	isCrit := false
+	if raw > 0 && rng != nil && rng.Float64() < critChance {
+		raw *= CritMultiplier
+		isCrit = true
+	}
+
+	damage := int(math.Round(raw))
+	if damage < 0 {
+		damage = 0
+	}
+	return damage, isCrit
+}
+
+// EnemyDamage calculates incoming damage with variance, STA reduction and the
+// low-accuracy penalty. Never less than 1.
+func EnemyDamage(attack, sta int, accuracy float64, rng RNG) int {
+	factor := 1.0
+	if rng != nil {
+		factor = enemyDamageMinFactor + rng.Float64()*(enemyDamageMaxFactor-enemyDamageMinFactor)
+	}
+
+	raw := float64(attack) * factor
+	raw -= float64(sta) * staDamageReduction
+
+	if enableLowAccuracyPenalty && accuracy < lowAccuracyPenaltyThreshold {
+		raw *= lowAccuracyPenaltyMultiplier
+	}
+
+	damage := int(math.Round(raw))
+	if damage < 1 {
+		damage = 1
+	}
+	return damage
+}
+
+// Exchange is what one resolved round costs both sides.
+type Exchange struct {
+	PlayerDamage int
+	Crit         bool
+	EnemyDamage  int
+}
+
+// ResolveRound draws the player's and the enemy's damage for one round, in
+// the order the engine does. Both hits land even if one of them is lethal.
+func ResolveRound(str, agi, sta, attack int, accuracy float64, rng RNG) Exchange {
+	return ResolveRoundWithCrit(str, CritChance(agi), sta, attack, accuracy, rng)
+}
+
+// ResolveRoundWithCrit is ResolveRound with the crit chance given directly.
+func ResolveRoundWithCrit(str int, critChance float64, sta, attack int, accuracy float64, rng RNG) Exchange {
+	damage, crit := PlayerDamageWithCrit(str, critChance, accuracy, rng)
+	return Exchange{
+		PlayerDamage: damage,
+		Crit:         crit,
+		EnemyDamage:  EnemyDamage(attack, sta, accuracy, rng),
+	}
+}
+
+// HasRegen reports whether an enemy of this role regenerates between rounds.
+// Bosses rely on their scripted phases instead.
+func HasRegen(role string, isBoss bool) bool {
+	return role == "ELITE" && !isBoss
+}
+
+// Regen returns the HP a regenerating enemy restores after each round it survives.
+func Regen(maxHP int) int {
+	regen := int(math.Round(float64(maxHP) * regenPercent / 100))
+	if regen < 1 {
+		regen = 1
+	}
+	return regen
+}
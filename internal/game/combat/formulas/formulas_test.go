@@ -0,0 +1,130 @@
+package formulas
+
+import (
+	"math"
+	"testing"
+)
+
+func nearlyEqual(got, want, eps float64) bool {
+	return math.Abs(got-want) <= eps
+}
+
+// seq replays values in order, wrapping around.
+type seq []float64
+
+func (s *seq) Float64() float64 {
+	v := (*s)[0]
+	*s = append((*s)[1:], v)
+	return v
+}
+
+func rolls(values ...float64) *seq {
+	s := seq(values)
+	return &s
+}
+
+func TestPlayerDamage_ScalesWithAccuracy(t *testing.T) {
+	low, _ := PlayerDamage(20, 10, 0.61, rolls(0.99))
+	high, _ := PlayerDamage(20, 10, 0.90, rolls(0.99))
+	if high <= low {
+		t.Fatalf("higher accuracy must increase damage, got low=%d high=%d", low, high)
+	}
+	if zero, crit := PlayerDamage(20, 10, 0, rolls(0)); zero != 0 || crit {
+		t.Fatalf("a missed round must deal nothing and never crit, got %d crit=%v", zero, crit)
+	}
+}
+
+func TestPlayerDamage_CritMultipliesDamage(t *testing.T) {
+	plain, crit := PlayerDamage(20, 10, 1, rolls(0.99))
+	if crit {
+		t.Fatal("a high roll must not crit")
+	}
+	boosted, crit := PlayerDamage(20, 10, 1, rolls(0))
+	if !crit {
+		t.Fatal("a zero roll must crit")
+	}
+	if boosted != int(math.Round(float64(plain)*CritMultiplier)) {
+		t.Fatalf("crit should multiply damage by %.1f, got %d from %d", CritMultiplier, boosted, plain)
+	}
+}
+
+func TestCritChance_GrowsWithAGIAndCaps(t *testing.T) {
+	if CritChance(-1) != 0 {
+		t.Fatalf("negative AGI must not crit, got %f", CritChance(-1))
+	}
+	if !nearlyEqual(CritChance(10), 0.15, 1e-9) {
+		t.Fatalf("crit chance should be 1.5%% per AGI, got %f at AGI=10", CritChance(10))
+	}
+	if CritChance(1000) != 1 {
+		t.Fatalf("crit chance should cap at 1, got %f", CritChance(1000))
+	}
+}
+
+func TestEnemyDamage_STAAndLowAccuracy(t *testing.T) {
+	base := EnemyDamage(40, 0, 1, nil)
+	tough := EnemyDamage(40, 40, 1, nil)
+	if tough >= base {
+		t.Fatalf("STA should reduce incoming damage, got %d vs %d", tough, base)
+	}
+	if sloppy := EnemyDamage(40, 0, 0.3, nil); sloppy <= base {
+		t.Fatalf("low accuracy should raise incoming damage, got %d vs %d", sloppy, base)
+	}
+	if floor := EnemyDamage(1, 999, 1, nil); floor != 1 {
+		t.Fatalf("enemy damage should never drop below 1, got %d", floor)
+	}
+	low := EnemyDamage(40, 0, 1, rolls(0))
+	high := EnemyDamage(40, 0, 1, rolls(0.999))
+	if low != 32 || high != 48 {
+		t.Fatalf("variance should span 0.8–1.2 of attack, got %d..%d", low, high)
+	}
+}
+
+func TestCellsToShow_INTReliefAndBossBonus(t *testing.T) {
+	if got := CellsToShow("A", false, 6, 0); got != 12 {
+		t.Fatalf("expected one cell fewer per 3 INT (12 cells), got %d", got)
+	}
+	if got := CellsToShow("E", false, 60, 0); got != MinCellsToShow {
+		t.Fatalf("expected the minimum of %d cells, got %d", MinCellsToShow, got)
+	}
+	if got := CellsToShow("A", false, 0, 2); got != 12 {
+		t.Fatalf("relief should lower the target, got %d", got)
+	}
+	if got := CellsToShow("E", true, 60, 0); got != MinCellsToShow+bossBonusCells {
+		t.Fatalf("bosses add their bonus after the minimum, got %d", got)
+	}
+}
+
+func TestShowSecondsClamps(t *testing.T) {
+	if got := ShowSeconds(0); !nearlyEqual(got, 2.5, 1e-9) {
+		t.Fatalf("expected 2.5s at INT=0, got %f", got)
+	}
+	if got := ShowSeconds(-40); got != minShowSeconds {
+		t.Fatalf("expected the %fs floor, got %f", minShowSeconds, got)
+	}
+	if got := ShowSeconds(1000); got != maxShowSeconds {
+		t.Fatalf("expected the %fs cap, got %f", maxShowSeconds, got)
+	}
+}
+
+func TestRoundSourceIsDeterministic(t *testing.T) {
+	a := RoundSource(42, 3).Float64()
+	b := RoundSource(42, 3).Float64()
+	if a != b {
+		t.Fatalf("same seed and round must give the same roll: %f vs %f", a, b)
+	}
+	if c := RoundSource(42, 4).Float64(); c == a {
+		t.Fatalf("neighbouring rounds should not share a stream: %f", c)
+	}
+}
+
+func TestRegen(t *testing.T) {
+	if !HasRegen("ELITE", false) || HasRegen("ELITE", true) || HasRegen("NORMAL", false) {
+		t.Fatal("only non-boss elites regenerate")
+	}
+	if got := Regen(200); got != 8 {
+		t.Fatalf("expected 4%% of 200 HP, got %d", got)
+	}
+	if got := Regen(5); got != 1 {
+		t.Fatalf("regen should be at least 1, got %d", got)
+	}
+}
@@ -0,0 +1,230 @@
+package combat
+
+import (
+	"errors"
+
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Grid memory: remember the highlighted cells
+// ============================================================
+
+type gridMemory struct{}
+
+func (gridMemory) Kind() models.MinigameKind { return models.MinigameGridMemory }
+
+func (gridMemory) NewRound(enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	gridSize := memory.GridSize(enemy)
+	shown, err := memory.GenerateShownCells(gridSize, memory.CellsToShow(enemy, stats), rng)
+	if err != nil {
+		return models.MinigameRound{}, err
+	}
+	return GridRound(gridSize, shown, memory.TimeToShow(enemy, stats)), nil
+}
+
+func (gridMemory) Evaluate(round models.MinigameRound, input []int) Result {
+	return ratio(memory.CorrectClicks(round.Answer, input), len(round.Answer))
+}
+
+// ============================================================
+// Sequence memory (Simon): repeat the cells in the order they lit up
+// ============================================================
+
+const sequenceGridSize = 4
+
+type sequenceMemory struct{}
+
+func (sequenceMemory) Kind() models.MinigameKind { return models.MinigameSequence }
+
+func (sequenceMemory) NewRound(enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	length := clamp(3+rankLevel(enemy.Rank)-stats.INT/6, 3, 9)
+	seq, err := memory.GenerateShownCells(sequenceGridSize, length, rng)
+	if err != nil {
+		return models.MinigameRound{}, err
+	}
+	return models.MinigameRound{
+		Kind:       models.MinigameSequence,
+		GridSize:   sequenceGridSize,
+		Cells:      seq,
+		ShowTimeMs: clamp(memory.TimeToShow(enemy, stats)/4, 450, 1000),
+		Inputs:     length,
+		Answer:     seq,
+	}, nil
+}
+
+// Evaluate counts cells entered in the right position.
+func (sequenceMemory) Evaluate(round models.MinigameRound, input []int) Result {
+	hits := 0
+	for i, cell := range round.Answer {
+		if i < len(input) && input[i] == cell {
+			hits++
+		}
+	}
+	return ratio(hits, len(round.Answer))
+}
+
+// ============================================================
+// N-back: flag every step that repeats the position N steps earlier
+// ============================================================
+
+const (
+	nBackGridSize    = 3
+	nBackMatchChance = 0.35
+)
+
+type nBack struct{}
+
+func (nBack) Kind() models.MinigameKind { return models.MinigameNBack }
+
+func (nBack) NewRound(enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	if rng == nil {
+		return models.MinigameRound{}, errors.New("rng is nil")
+	}
+	level := rankLevel(enemy.Rank)
+	n := 1 + level/2
+	steps := n + 6 + level - stats.INT/8
+	if steps < n+4 {
+		steps = n + 4
+	}
+
+	cells := nBackGridSize * nBackGridSize
+	stream := make([]int, steps)
+	var answer []int
+	for i := range stream {
+		if i >= n && rng.Float64() < nBackMatchChance {
+			stream[i] = stream[i-n]
+		} else {
+			stream[i] = rng.Perm(cells)[0]
+		}
+		if i >= n && stream[i] == stream[i-n] {
+			answer = append(answer, i)
+		}
+	}
+	return models.MinigameRound{
+		Kind:       models.MinigameNBack,
+		GridSize:   nBackGridSize,
+		Cells:      stream,
+		N:          n,
+		ShowTimeMs: clamp(memory.TimeToShow(enemy, stats)/2, 1000, 2000),
+		Inputs:     steps - n,
+		Answer:     answer,
+	}, nil
+}
+
+// Evaluate scores every decidable step: flagged matches and unflagged non-matches both count.
+func (nBack) Evaluate(round models.MinigameRound, input []int) Result {
+	flagged := make(map[int]bool, len(input))
+	for _, step := range input {
+		flagged[step] = true
+	}
+	match := make(map[int]bool, len(round.Answer))
+	for _, step := range round.Answer {
+		match[step] = true
+	}
+	hits := 0
+	for step := round.N; step < len(round.Cells); step++ {
+		if flagged[step] == match[step] {
+			hits++
+		}
+	}
+	return ratio(hits, len(round.Cells)-round.N)
+}
+
+// ============================================================
+// Stroop: name the ink colour, not the word
+// ============================================================
+
+const stroopIncongruentChance = 0.75
+
+type stroop struct{}
+
+func (stroop) Kind() models.MinigameKind { return models.MinigameStroop }
+
+func (stroop) NewRound(enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	if rng == nil {
+		return models.MinigameRound{}, errors.New("rng is nil")
+	}
+	count := 4 + rankLevel(enemy.Rank)
+	colors := len(models.StroopColors)
+	prompts := make([]models.StroopPrompt, count)
+	answer := make([]int, count)
+	for i := range prompts {
+		perm := rng.Perm(colors)
+		word, ink := perm[0], perm[0]
+		if rng.Float64() < stroopIncongruentChance {
+			ink = perm[1]
+		}
+		prompts[i] = models.StroopPrompt{Word: word, Ink: ink}
+		answer[i] = ink
+	}
+	return models.MinigameRound{
+		Kind:       models.MinigameStroop,
+		Prompts:    prompts,
+		ShowTimeMs: clamp(memory.TimeToShow(enemy, stats)/2, 1000, 2000),
+		Inputs:     count,
+		Answer:     answer,
+	}, nil
+}
+
+// Evaluate counts prompts answered with the ink colour; a missing answer (-1) is wrong.
+func (stroop) Evaluate(round models.MinigameRound, input []int) Result {
+	hits := 0
+	for i, ink := range round.Answer {
+		if i < len(input) && input[i] == ink {
+			hits++
+		}
+	}
+	return ratio(hits, len(round.Answer))
+}
+
+// ============================================================
+// Pattern rotation: pick the shown pattern turned clockwise
+// ============================================================
+
+type patternRotation struct{}
+
+func (patternRotation) Kind() models.MinigameKind { return models.MinigameRotation }
+
+func (patternRotation) NewRound(enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	if rng == nil {
+		return models.MinigameRound{}, errors.New("rng is nil")
+	}
+	gridSize := 4
+	if rankLevel(enemy.Rank) >= 3 {
+		gridSize = 5
+	}
+	count := clamp(memory.CellsToShow(enemy, stats)/2, 3, gridSize*gridSize/2)
+	pattern, err := memory.GenerateShownCells(gridSize, count, rng)
+	if err != nil {
+		return models.MinigameRound{}, err
+	}
+	turns := 1 + int(rng.Float64()*3)
+	return models.MinigameRound{
+		Kind:       models.MinigameRotation,
+		GridSize:   gridSize,
+		Cells:      pattern,
+		Turns:      turns,
+		ShowTimeMs: memory.TimeToShow(enemy, stats),
+		Inputs:     count,
+		Answer:     RotateCells(pattern, gridSize, turns),
+	}, nil
+}
+
+func (patternRotation) Evaluate(round models.MinigameRound, input []int) Result {
+	return ratio(memory.CorrectClicks(round.Answer, input), len(round.Answer))
+}
+
+// RotateCells turns cell indices on a size×size grid clockwise by quarter turns.
+func RotateCells(cells []int, size int, turns int) []int {
+	out := make([]int, len(cells))
+	for i, cell := range cells {
+		row, col := cell/size, cell%size
+		for t := 0; t < ((turns%4)+4)%4; t++ {
+			row, col = col, size-1-row
+		}
+		out[i] = row*size + col
+	}
+	return out
+}
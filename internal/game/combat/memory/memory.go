@@ -3,30 +3,22 @@ package memory
 import (
 	"errors"
 	"math"
+	"time"
 
+	"solo-leveling/internal/game/combat/formulas"
 	"solo-leveling/internal/models"
 )
 
 const (
-	RegularGridSize = 6
-	BossGridSize    = 8
-
-	minCellsToShow = 4
-
-	baseShowSeconds = 2.5
-	minShowSeconds  = 2.0
-	maxShowSeconds  = 4.0
-
-	critMultiplier = 1.5
-
-	enemyDamageMinFactor = 0.8
-	enemyDamageMaxFactor = 1.2
-
-	lowAccuracyPenaltyThreshold  = 0.5
-	lowAccuracyPenaltyMultiplier = 1.2
-
-	// Keep this as a constant so tuning does not leak into UI code.
-	enableLowAccuracyPenalty = true
+	RegularGridSize = formulas.RegularGridSize
+	BossGridSize    = formulas.BossGridSize
+
+	// fogCellRelief offsets the cells hidden by swamp fog.
+	fogCellRelief = 1
+	// hasteTimeFactor shortens the highlight under the haste modifier.
+	hasteTimeFactor = 0.7
+	// wideGridBonus widens the field under the wide-grid modifier.
+	wideGridBonus = 2
 )
 
 // Stats represents combat-relevant player stats.
@@ -35,6 +27,10 @@ type Stats struct {
 	AGI int
 	INT int
 	STA int
+	// Equipment bonuses on top of the stats.
+	CritBonus       float64 // added crit chance, 0.05 is +5%
+	BonusHP         int
+	ShowTimePercent int // longer highlight, in percent
 }
 
 // RNG is a narrow random source interface for deterministic tests.
@@ -43,66 +39,97 @@ type RNG interface {
 	Perm(n int) []int
 }
 
+// NewSeed returns a fresh seed for a battle.
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// RoundRNG returns the random source for one round of a seeded battle. Round 0
+// generates the opening field; round N resolves round N and deals round N+1.
+// Deriving it from (seed, round) keeps a fight reproducible across save/resume.
+func RoundRNG(seed int64, round int) RNG {
+	return formulas.RoundSource(seed, round)
+}
+
 // GridSize selects memory field size by enemy type.
 func GridSize(enemy models.Enemy) int {
-	if enemy.Type == models.EnemyBoss {
-		return BossGridSize
+	size := formulas.GridSize(enemy.Type == models.EnemyBoss)
+	if HasModifier(enemy, models.ModifierWideGrid) {
+		size += wideGridBonus
 	}
-	return RegularGridSize
+	return size
 }
 
 // BaseCellsByRank maps enemy rank to base highlighted cells.
 func BaseCellsByRank(rank models.QuestRank) int {
-	switch rank {
-	case models.RankE:
-		return 6
-	case models.RankD:
-		return 8
-	case models.RankC:
-		return 10
-	case models.RankB:
-		return 12
-	case models.RankA:
-		return 14
-	case models.RankS:
-		return 16
-	default:
-		return 8
+	return formulas.BaseCellsByRank(string(rank))
+}
+
+// Modifiers lists the biome and role traits of a regular enemy followed by the
+// ones set on it directly. Bosses rely on their scripted phases instead.
+func Modifiers(enemy models.Enemy) []models.EnemyModifier {
+	if enemy.Type == models.EnemyBoss || enemy.IsBoss {
+		return nil
+	}
+	var mods []models.EnemyModifier
+	switch enemy.Biome {
+	case "swamp":
+		mods = append(mods, models.ModifierFog)
+	case "frost":
+		mods = append(mods, models.ModifierFlicker)
+	case "void":
+		mods = append(mods, models.ModifierShuffle)
+	}
+	if formulas.HasRegen(enemy.Role, false) {
+		mods = append(mods, models.ModifierRegen)
+	}
+	return append(mods, enemy.Modifiers...)
+}
+
+// HasModifier reports whether the enemy carries the given modifier.
+func HasModifier(enemy models.Enemy, modifier models.EnemyModifier) bool {
+	for _, m := range Modifiers(enemy) {
+		if m == modifier {
+			return true
+		}
 	}
+	return false
 }
 
 // CellsToShow calculates the number of cells shown for the current round.
 func CellsToShow(enemy models.Enemy, stats Stats) int {
-	baseCells := BaseCellsByRank(enemy.Rank)
-	if enemy.Type == models.EnemyBoss {
-		baseCells += 2
+	relief := 0
+	if HasModifier(enemy, models.ModifierFog) {
+		relief = fogCellRelief
 	}
-
-	reduction := stats.INT / 3
-	cells := baseCells - reduction
-	if cells < minCellsToShow {
-		cells = minCellsToShow
+	cells := formulas.CellsToShow(string(enemy.Rank), enemy.Type == models.EnemyBoss, stats.INT, relief)
+	if enemy.Difficulty.Cells == 0 {
+		return cells
 	}
-	if enemy.Type == models.EnemyBoss {
-		cells += 3
+	// The adaptive shift stays inside [MinCellsToShow, half the grid].
+	grid := GridSize(enemy)
+	limit := grid * grid / 2
+	if cells > limit {
+		limit = cells
 	}
-
-	maxCells := GridSize(enemy) * GridSize(enemy)
-	if cells > maxCells {
-		cells = maxCells
+	cells += enemy.Difficulty.Cells
+	if cells < formulas.MinCellsToShow {
+		cells = formulas.MinCellsToShow
+	}
+	if cells > limit {
+		cells = limit
 	}
 	return cells
 }
 
 // TimeToShow returns highlight phase duration in milliseconds.
-func TimeToShow(stats Stats) int {
-	seconds := baseShowSeconds + float64(stats.INT)*0.05
-	if seconds < minShowSeconds {
-		seconds = minShowSeconds
-	}
-	if seconds > maxShowSeconds {
-		seconds = maxShowSeconds
+func TimeToShow(enemy models.Enemy, stats Stats) int {
+	seconds := formulas.ShowSeconds(stats.INT)
+	if HasModifier(enemy, models.ModifierHaste) {
+		seconds *= hasteTimeFactor
 	}
+	seconds *= 1 + float64(enemy.Difficulty.ShowTimePercent)/100
+	seconds *= 1 + float64(stats.ShowTimePercent)/100
 	return int(math.Round(seconds * 1000))
 }
 
@@ -171,69 +198,52 @@ func ComputeAccuracy(shown, selected []int) float64 {
 
 // PlayerHP returns base HP: 100 + STA*12.
 func PlayerHP(sta int) int {
-	if sta < 0 {
-		sta = 0
-	}
-	return 100 + sta*12
+	return formulas.PlayerHP(sta)
 }
 
 // CritChance returns crit probability from AGI (1.5% per level).
 func CritChance(agi int) float64 {
-	if agi < 0 {
-		return 0
-	}
-	chance := float64(agi) * 0.015
-	if chance > 1 {
-		return 1
-	}
-	return chance
+	return formulas.CritChance(agi)
 }
 
 // BasePlayerDamage returns damage before accuracy multiplier.
 func BasePlayerDamage(str int) int {
-	return 10 + str*2
+	return formulas.BasePlayerDamage(str)
+}
+
+// MaxHP returns the player's HP including equipment.
+func MaxHP(stats Stats) int {
+	return PlayerHP(stats.STA) + stats.BonusHP
 }
 
 // ComputePlayerDamage applies accuracy and crit to outgoing damage.
 func ComputePlayerDamage(stats Stats, accuracy float64, rng RNG) (int, bool) {
-	if accuracy < 0 {
-		accuracy = 0
-	}
-	if accuracy > 1 {
-		accuracy = 1
-	}
+	return formulas.PlayerDamageWithCrit(stats.STR, PlayerCritChance(stats), accuracy, rng)
+}
+
+// PlayerCritChance returns the crit chance from AGI and equipment, capped at 1.
+func PlayerCritChance(stats Stats) float64 {
+	return math.Min(CritChance(stats.AGI)+stats.CritBonus, 1)
+}
 
-	raw := float64(BasePlayerDamage(stats.STR)) * accuracy
-	isCrit := false
-	if raw > 0 && rng != nil && rng.Float64() < CritChance(stats.AGI) {
-		raw *= critMultiplier
-		isCrit = true
+// CritChanceAgainst returns the player's crit chance against enemy: none
+// under the no-crit modifier.
+func CritChanceAgainst(enemy models.Enemy, stats Stats) float64 {
+	if HasModifier(enemy, models.ModifierNoCrit) {
+		return 0
 	}
+	return PlayerCritChance(stats)
+}
 
-	damage := int(math.Round(raw))
-	if damage < 0 {
-		damage = 0
+// RegenPerRound returns the HP an enemy restores after each round it survives.
+func RegenPerRound(enemy models.Enemy) int {
+	if !HasModifier(enemy, models.ModifierRegen) {
+		return 0
 	}
-	return damage, isCrit
+	return formulas.Regen(enemy.HP)
 }
 
 // ComputeEnemyDamage calculates incoming damage with variance and STA mitigation.
 func ComputeEnemyDamage(enemy models.Enemy, stats Stats, accuracy float64, rng RNG) int {
-	factor := 1.0
-	if rng != nil {
-		factor = enemyDamageMinFactor + rng.Float64()*(enemyDamageMaxFactor-enemyDamageMinFactor)
-	}
-
-	raw := float64(enemy.Attack) * factor
-	raw -= float64(stats.STA) * 0.25
-
-	if enableLowAccuracyPenalty && accuracy < lowAccuracyPenaltyThreshold {
-		raw *= lowAccuracyPenaltyMultiplier
-	}
-
-	damage := int(math.Round(raw))
-	if damage < 1 {
-		damage = 1
-	}
-	return damage
+	return formulas.EnemyDamage(enemy.Attack, stats.STA, accuracy, rng)
 }
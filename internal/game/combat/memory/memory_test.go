@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"solo-leveling/internal/game/combat/formulas"
 	"solo-leveling/internal/models"
 )
 
@@ -131,3 +132,140 @@ func TestComputeEnemyDamage_DeterministicAndMinOne(t *testing.T) {
 		t.Fatalf("damage should be clamped to min 1, got %d", damage)
 	}
 }
+
+func TestRoundRNG_DeterministicPerSeedAndRound(t *testing.T) {
+	a, err := GenerateShownCells(6, 8, RoundRNG(1234, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := GenerateShownCells(6, 8, RoundRNG(1234, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed and round must match: %v vs %v", a, b)
+		}
+	}
+
+	c, err := GenerateShownCells(6, 8, RoundRNG(1234, 4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	same := true
+	for i := range a {
+		if a[i] != c[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Fatalf("different rounds should not repeat the field: %v", a)
+	}
+}
+
+func TestModifiers_ChangeFormulas(t *testing.T) {
+	stats := Stats{INT: 6}
+	plain := models.Enemy{Rank: models.RankC, Type: models.EnemyRegular, Biome: "ruins", HP: 300}
+
+	swamp := plain
+	swamp.Biome = "swamp"
+	if got, want := CellsToShow(swamp, stats), CellsToShow(plain, stats)-fogCellRelief; got != want {
+		t.Fatalf("fog: expected %d cells, got %d", want, got)
+	}
+
+	frost := plain
+	frost.Biome = "frost"
+	if got, want := TimeToShow(frost, stats), TimeToShow(plain, stats); got != want {
+		t.Fatalf("flicker must not change the show time: expected %dms, got %d", want, got)
+	}
+
+	if RegenPerRound(plain) != 0 {
+		t.Fatal("only ELITE enemies regenerate")
+	}
+	elite := plain
+	elite.Role = "ELITE"
+	if got := RegenPerRound(elite); got != 12 {
+		t.Fatalf("expected 4%% of 300 HP, got %d", got)
+	}
+
+	boss := swamp
+	boss.Type = models.EnemyBoss
+	boss.IsBoss = true
+	if len(Modifiers(boss)) != 0 {
+		t.Fatal("bosses rely on scripted phases, not modifiers")
+	}
+}
+
+func TestChallengeModifiers_ChangeFormulas(t *testing.T) {
+	stats := Stats{AGI: 10, INT: 6}
+	plain := models.Enemy{Rank: models.RankC, Type: models.EnemyRegular, Biome: "ruins", HP: 300}
+
+	wide := plain
+	wide.Modifiers = []models.EnemyModifier{models.ModifierWideGrid}
+	if got, want := GridSize(wide), GridSize(plain)+wideGridBonus; got != want {
+		t.Fatalf("wide grid: expected %d, got %d", want, got)
+	}
+
+	haste := plain
+	haste.Modifiers = []models.EnemyModifier{models.ModifierHaste}
+	if TimeToShow(haste, stats) >= TimeToShow(plain, stats) {
+		t.Fatal("haste should shorten the highlight")
+	}
+
+	noCrit := plain
+	noCrit.Modifiers = []models.EnemyModifier{models.ModifierNoCrit}
+	if CritChanceAgainst(noCrit, stats) != 0 || CritChanceAgainst(plain, stats) != CritChance(stats.AGI) {
+		t.Fatal("no-crit should drop AGI from the crit roll only for its enemy")
+	}
+}
+
+func TestDifficultyShift_StaysInBounds(t *testing.T) {
+	stats := Stats{INT: 3}
+	plain := models.Enemy{Rank: models.RankC, Type: models.EnemyRegular, HP: 300}
+	base := CellsToShow(plain, stats)
+
+	harder := plain
+	harder.Difficulty = models.DifficultyShift{Cells: 2, ShowTimePercent: -20}
+	if got := CellsToShow(harder, stats); got != base+2 {
+		t.Fatalf("expected %d cells, got %d", base+2, got)
+	}
+	if got, want := TimeToShow(harder, stats), int(float64(TimeToShow(plain, stats))*0.8+0.5); got != want {
+		t.Fatalf("expected %dms, got %d", want, got)
+	}
+
+	ringed := stats
+	ringed.ShowTimePercent = 10
+	if got, want := TimeToShow(plain, ringed), int(float64(TimeToShow(plain, stats))*1.1+0.5); got != want {
+		t.Fatalf("equipment should lengthen the show to %dms, got %d", want, got)
+	}
+
+	easier := plain
+	easier.Difficulty = models.DifficultyShift{Cells: -100}
+	if got := CellsToShow(easier, stats); got != formulas.MinCellsToShow {
+		t.Fatalf("cells must not drop below %d, got %d", formulas.MinCellsToShow, got)
+	}
+	flooded := plain
+	flooded.Difficulty = models.DifficultyShift{Cells: 100}
+	if got, limit := CellsToShow(flooded, stats), GridSize(plain)*GridSize(plain)/2; got != limit {
+		t.Fatalf("cells must stop at half the grid (%d), got %d", limit, got)
+	}
+}
+
+func TestEquipmentBonuses_RaiseCritAndHP(t *testing.T) {
+	stats := Stats{AGI: 10, STA: 5}
+	geared := stats
+	geared.CritBonus = 0.05
+	geared.BonusHP = 30
+
+	if got, want := PlayerCritChance(geared), CritChance(stats.AGI)+0.05; got != want {
+		t.Fatalf("expected crit chance %.3f, got %.3f", want, got)
+	}
+	if MaxHP(geared) != MaxHP(stats)+30 {
+		t.Fatalf("expected +30 HP, got %d vs %d", MaxHP(geared), MaxHP(stats))
+	}
+	geared.CritBonus = 5
+	if PlayerCritChance(geared) != 1 {
+		t.Fatal("crit chance must be capped at 100%")
+	}
+}
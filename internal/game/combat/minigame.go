@@ -0,0 +1,131 @@
+package combat
+
+import (
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+// Result is the evaluation of a player's input for one round.
+type Result struct {
+	Accuracy float64 // 0..1
+	Hits     int
+	Total    int
+}
+
+// Minigame generates rounds and scores the player's answers. Damage is always
+// derived from Result.Accuracy, so every minigame plugs into the same combat math.
+type Minigame interface {
+	Kind() models.MinigameKind
+	// NewRound builds a round whose difficulty scales with the enemy and the player's stats.
+	NewRound(enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error)
+	// Evaluate scores input against the round's answer.
+	Evaluate(round models.MinigameRound, input []int) Result
+}
+
+var registry = map[models.MinigameKind]Minigame{
+	models.MinigameGridMemory: gridMemory{},
+	models.MinigameSequence:   sequenceMemory{},
+	models.MinigameNBack:      nBack{},
+	models.MinigameStroop:     stroop{},
+	models.MinigameRotation:   patternRotation{},
+}
+
+// biomeMinigames maps each tower biome to the minigame its enemies use.
+var biomeMinigames = map[string]models.MinigameKind{
+	"swamp":    models.MinigameGridMemory,
+	"ruins":    models.MinigameSequence,
+	"frost":    models.MinigameRotation,
+	"volcanic": models.MinigameStroop,
+	"void":     models.MinigameGridMemory,
+}
+
+// roleMinigames overrides the biome choice for some enemy roles.
+var roleMinigames = map[string]models.MinigameKind{
+	"MINIBOSS":  models.MinigameNBack,
+	"CHALLENGE": models.MinigameGridMemory,
+	"PRACTICE":  models.MinigameGridMemory,
+}
+
+// ForKind returns the minigame of the given kind, falling back to grid memory.
+func ForKind(kind models.MinigameKind) Minigame {
+	if game, ok := registry[kind]; ok {
+		return game
+	}
+	return registry[models.MinigameGridMemory]
+}
+
+// KindForEnemy picks the minigame for an enemy. Bosses and transition enemies
+// keep the classic grid; minibosses use their role's minigame and everyone
+// else follows their biome.
+func KindForEnemy(enemy models.Enemy) models.MinigameKind {
+	if enemy.Type == models.EnemyBoss || enemy.IsBoss || enemy.Role == "BOSS" || enemy.Role == "TRANSITION" {
+		return models.MinigameGridMemory
+	}
+	if kind, ok := roleMinigames[enemy.Role]; ok {
+		return kind
+	}
+	if kind, ok := biomeMinigames[enemy.Biome]; ok {
+		return kind
+	}
+	return models.MinigameGridMemory
+}
+
+// NewRound deals a round of the given minigame and applies the enemy's modifiers to it.
+func NewRound(kind models.MinigameKind, enemy models.Enemy, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	round, err := ForKind(kind).NewRound(enemy, stats, rng)
+	if err != nil {
+		return models.MinigameRound{}, err
+	}
+	applyModifiers(&round, enemy, rng)
+	return round, nil
+}
+
+// GridRound wraps a classic grid-memory field as a minigame round.
+func GridRound(gridSize int, shown []int, showTimeMs int) models.MinigameRound {
+	return models.MinigameRound{
+		Kind:       models.MinigameGridMemory,
+		GridSize:   gridSize,
+		Cells:      shown,
+		ShowTimeMs: showTimeMs,
+		Inputs:     len(shown),
+		Answer:     shown,
+	}
+}
+
+// rankLevel maps E..S to 0..5 for difficulty scaling.
+func rankLevel(rank models.QuestRank) int {
+	switch rank {
+	case models.RankD:
+		return 1
+	case models.RankC:
+		return 2
+	case models.RankB:
+		return 3
+	case models.RankA:
+		return 4
+	case models.RankS:
+		return 5
+	default:
+		return 0
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func ratio(hits, total int) Result {
+	if total <= 0 {
+		return Result{}
+	}
+	if hits > total {
+		hits = total
+	}
+	return Result{Accuracy: float64(hits) / float64(total), Hits: hits, Total: total}
+}
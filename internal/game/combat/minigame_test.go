@@ -0,0 +1,150 @@
+package combat
+
+import (
+	"math/rand"
+	"testing"
+
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+func TestRotateCells_QuarterTurns(t *testing.T) {
+	// 3x3 grid: top-left corner goes to top-right after one clockwise turn.
+	if got := RotateCells([]int{0}, 3, 1); got[0] != 2 {
+		t.Fatalf("expected 2, got %d", got[0])
+	}
+	if got := RotateCells([]int{0}, 3, 2); got[0] != 8 {
+		t.Fatalf("expected 8, got %d", got[0])
+	}
+	if got := RotateCells([]int{1, 5}, 3, 4); got[0] != 1 || got[1] != 5 {
+		t.Fatalf("full turn must be identity, got %v", got)
+	}
+}
+
+func TestSequence_ScoresByPosition(t *testing.T) {
+	round := models.MinigameRound{Kind: models.MinigameSequence, Answer: []int{3, 7, 1}}
+	game := ForKind(models.MinigameSequence)
+
+	if res := game.Evaluate(round, []int{3, 7, 1}); res.Accuracy != 1 {
+		t.Fatalf("expected full accuracy, got %v", res.Accuracy)
+	}
+	// Same cells in the wrong order only keep the first position.
+	if res := game.Evaluate(round, []int{3, 1, 7}); res.Hits != 1 {
+		t.Fatalf("expected 1 hit, got %d", res.Hits)
+	}
+}
+
+func TestNBack_CountsMatchesAndRejections(t *testing.T) {
+	round := models.MinigameRound{
+		Kind:   models.MinigameNBack,
+		N:      1,
+		Cells:  []int{4, 4, 2, 2, 5},
+		Answer: []int{1, 3},
+	}
+	game := ForKind(models.MinigameNBack)
+
+	if res := game.Evaluate(round, []int{1, 3}); res.Accuracy != 1 {
+		t.Fatalf("expected full accuracy, got %v", res.Accuracy)
+	}
+	// Missing step 3 and flagging step 4 wrongly costs two of four steps.
+	if res := game.Evaluate(round, []int{1, 4}); res.Hits != 2 || res.Total != 4 {
+		t.Fatalf("expected 2/4, got %d/%d", res.Hits, res.Total)
+	}
+}
+
+func TestNBack_AnswerMatchesStream(t *testing.T) {
+	enemy := models.Enemy{Rank: models.RankB}
+	round, err := ForKind(models.MinigameNBack).NewRound(enemy, memory.Stats{}, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatalf("NewRound: %v", err)
+	}
+	match := map[int]bool{}
+	for _, step := range round.Answer {
+		match[step] = true
+	}
+	for step := round.N; step < len(round.Cells); step++ {
+		if (round.Cells[step] == round.Cells[step-round.N]) != match[step] {
+			t.Fatalf("step %d: answer disagrees with stream", step)
+		}
+	}
+}
+
+func TestStroop_MissedPromptIsWrong(t *testing.T) {
+	round, err := ForKind(models.MinigameStroop).NewRound(models.Enemy{Rank: models.RankE}, memory.Stats{}, rand.New(rand.NewSource(9)))
+	if err != nil {
+		t.Fatalf("NewRound: %v", err)
+	}
+	input := append([]int(nil), round.Answer...)
+	input[0] = -1
+	res := ForKind(models.MinigameStroop).Evaluate(round, input)
+	if res.Hits != len(round.Answer)-1 {
+		t.Fatalf("expected %d hits, got %d", len(round.Answer)-1, res.Hits)
+	}
+}
+
+func TestKindForEnemy(t *testing.T) {
+	if kind := KindForEnemy(models.Enemy{Biome: "ruins"}); kind != models.MinigameSequence {
+		t.Fatalf("ruins: expected sequence, got %s", kind)
+	}
+	if kind := KindForEnemy(models.Enemy{Biome: "ruins", Role: "MINIBOSS"}); kind != models.MinigameNBack {
+		t.Fatalf("miniboss: expected n-back, got %s", kind)
+	}
+	if kind := KindForEnemy(models.Enemy{Biome: "void", IsBoss: true}); kind != models.MinigameGridMemory {
+		t.Fatalf("boss: expected grid, got %s", kind)
+	}
+	if kind := KindForEnemy(models.Enemy{Biome: "unknown"}); kind != models.MinigameGridMemory {
+		t.Fatalf("unknown biome: expected grid, got %s", kind)
+	}
+}
+
+func TestModifiers_OnlyWhereTheyApply(t *testing.T) {
+	swamp := models.Enemy{Biome: "swamp", Role: "ELITE", Rank: models.RankE}
+	mods := Modifiers(swamp)
+	if len(mods) != 2 || mods[0] != models.ModifierFog || mods[1] != models.ModifierRegen {
+		t.Fatalf("swamp elite: unexpected modifiers %v", mods)
+	}
+	// A swamp miniboss plays n-back, where fog has no field to cover.
+	if mods := Modifiers(models.Enemy{Biome: "swamp", Role: "MINIBOSS"}); len(mods) != 0 {
+		t.Fatalf("swamp miniboss: expected no modifiers, got %v", mods)
+	}
+	if mods := Modifiers(models.Enemy{Biome: "void", Type: models.EnemyBoss, IsBoss: true}); len(mods) != 0 {
+		t.Fatalf("bosses use scripted phases, got %v", mods)
+	}
+}
+
+func TestNewRound_AppliesFieldModifiers(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	fog, err := NewRound(models.MinigameGridMemory, models.Enemy{Biome: "swamp", Rank: models.RankE}, memory.Stats{}, rng)
+	if err != nil {
+		t.Fatalf("NewRound: %v", err)
+	}
+	if want := fog.GridSize * fog.GridSize / 4; len(fog.Fog) != want {
+		t.Fatalf("expected %d fog cells, got %d", want, len(fog.Fog))
+	}
+
+	void, err := NewRound(models.MinigameGridMemory, models.Enemy{Biome: "void", Rank: models.RankE}, memory.Stats{}, rng)
+	if err != nil {
+		t.Fatalf("NewRound: %v", err)
+	}
+	if !void.Shuffled || memory.CorrectClicks(void.Cells, void.Answer) == len(void.Cells) {
+		t.Fatalf("void round should move some cells: cells=%v answer=%v", void.Cells, void.Answer)
+	}
+}
+
+func TestShuffleCells_MovesToFreeCells(t *testing.T) {
+	cells := []int{0, 1, 2, 3, 4, 5}
+	moved := ShuffleCells(cells, 6, rand.New(rand.NewSource(2)))
+	if len(moved) != len(cells) {
+		t.Fatalf("expected %d cells, got %d", len(cells), len(moved))
+	}
+	seen := map[int]bool{}
+	for _, c := range moved {
+		if seen[c] {
+			t.Fatalf("duplicate cell %d in %v", c, moved)
+		}
+		seen[c] = true
+	}
+	if stayed := memory.CorrectClicks(cells, moved); stayed != len(cells)-len(cells)/shuffleDivisor {
+		t.Fatalf("expected %d cells to stay, got %d", len(cells)-len(cells)/shuffleDivisor, stayed)
+	}
+}
@@ -0,0 +1,96 @@
+package combat
+
+import (
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+const (
+	// fogShare is the fraction of the grid covered by swamp fog.
+	fogShare = 0.25
+	// shuffleDivisor makes one in every shuffleDivisor highlighted cells jump during a void shuffle.
+	shuffleDivisor = 3
+)
+
+// modifierKinds lists the minigames a field modifier can be shown on. Regen,
+// haste and no-crit work on timing or damage and apply to every minigame.
+var modifierKinds = map[models.EnemyModifier][]models.MinigameKind{
+	models.ModifierFog:      {models.MinigameGridMemory, models.MinigameRotation},
+	models.ModifierFlicker:  {models.MinigameGridMemory, models.MinigameRotation},
+	models.ModifierShuffle:  {models.MinigameGridMemory},
+	models.ModifierWideGrid: {models.MinigameGridMemory},
+}
+
+// Modifiers returns the enemy modifiers that take effect in the minigame it uses.
+func Modifiers(enemy models.Enemy) []models.EnemyModifier {
+	kind := KindForEnemy(enemy)
+	var out []models.EnemyModifier
+	for _, mod := range memory.Modifiers(enemy) {
+		if modifierApplies(mod, kind) {
+			out = append(out, mod)
+		}
+	}
+	return out
+}
+
+func modifierApplies(mod models.EnemyModifier, kind models.MinigameKind) bool {
+	kinds, ok := modifierKinds[mod]
+	if !ok {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// applyModifiers dresses a freshly dealt round with the enemy's field modifiers.
+func applyModifiers(round *models.MinigameRound, enemy models.Enemy, rng memory.RNG) {
+	for _, mod := range Modifiers(enemy) {
+		switch mod {
+		case models.ModifierFog:
+			total := round.GridSize * round.GridSize
+			count := int(float64(total) * fogShare)
+			if count > 0 {
+				round.Fog = append([]int(nil), rng.Perm(total)[:count]...)
+			}
+		case models.ModifierFlicker:
+			round.Flicker = true
+		case models.ModifierShuffle:
+			round.Answer = ShuffleCells(round.Cells, round.GridSize, rng)
+			round.Shuffled = true
+		}
+	}
+}
+
+// ShuffleCells moves one in shuffleDivisor highlighted cells (at least one) to
+// free cells of the grid and returns the resulting positions.
+func ShuffleCells(cells []int, gridSize int, rng memory.RNG) []int {
+	out := append([]int(nil), cells...)
+	if len(out) == 0 {
+		return out
+	}
+	taken := make(map[int]bool, len(out))
+	for _, c := range out {
+		taken[c] = true
+	}
+	var free []int
+	for _, c := range rng.Perm(gridSize * gridSize) {
+		if !taken[c] {
+			free = append(free, c)
+		}
+	}
+	moves := len(out) / shuffleDivisor
+	if moves < 1 {
+		moves = 1
+	}
+	if moves > len(free) {
+		moves = len(free)
+	}
+	for i, idx := range rng.Perm(len(out))[:moves] {
+		out[idx] = free[i]
+	}
+	return out
+}
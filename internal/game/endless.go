@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/game/combat/formulas"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Endless tower
+// ============================================================
+
+const (
+	// endlessZoneFloors is the size of a generated zone; its last floor is the boss.
+	endlessZoneFloors = 10
+	// endlessHPGrowth and endlessAttackGrowth scale the last catalog zone per generated zone.
+	endlessHPGrowth     = 1.12
+	endlessAttackGrowth = 1.06
+	// endlessLevelStep matches the level gap between catalog zones.
+	endlessLevelStep = 6
+)
+
+// endlessEpithets names generated enemies after the biome they borrow.
+var endlessEpithets = map[string]string{
+	"swamp":    "Топкий",
+	"ruins":    "Древний",
+	"frost":    "Ледяной",
+	"volcanic": "Пепельный",
+	"void":     "Пустотный",
+}
+
+var endlessNouns = []string{"Страж", "Жнец", "Призрак", "Охотник", "Колосс", "Ткач", "Вестник", "Палач"}
+
+var endlessBossNouns = []string{"Владыка", "Тиран", "Монарх", "Хранитель"}
+
+// GenerateEndlessFloor builds the enemy of a tower floor past the catalog. The
+// same seed and floor always give the same enemy: stats grow from the last
+// catalog zone, biomes (and with them the fight modifiers) rotate through the
+// catalog zones, and every tenth floor is a boss.
+func GenerateEndlessFloor(entries []catalog.Enemy, seed int64, floor int) (models.Enemy, error) {
+	zones := catalogZones(entries)
+	if len(zones) == 0 {
+		return models.Enemy{}, fmt.Errorf("каталог врагов пуст")
+	}
+	last := zones[len(zones)-1]
+	lastFloor := last[len(last)-1].Floor
+	if floor <= lastFloor {
+		return models.Enemy{}, fmt.Errorf("этаж %d входит в каталог", floor)
+	}
+
+	step := (floor-lastFloor-1)/endlessZoneFloors + 1
+	slot := (floor-lastFloor-1)%endlessZoneFloors + 1
+	isBoss := slot == endlessZoneFloors
+	template := endlessTemplate(last, slot, isBoss)
+	flavor := endlessTemplate(zones[(step-1)%len(zones)], slot, isBoss)
+
+	rng := formulas.RoundSource(seed, floor)
+	jitter := func() float64 { return 0.95 + rng.Float64()*0.10 }
+	nouns := endlessNouns
+	enemyType := models.EnemyRegular
+	if isBoss {
+		nouns = endlessBossNouns
+		enemyType = models.EnemyBoss
+	}
+	name := fmt.Sprintf("%s %s · Этаж %d", endlessEpithets[flavor.Biome], nouns[rng.Intn(len(nouns))], floor)
+
+	return models.Enemy{
+		Name:             name,
+		Description:      fmt.Sprintf("%s. %s", FloorName(floor), flavor.Description),
+		Rank:             models.RankS,
+		Type:             enemyType,
+		Level:            template.Level + endlessLevelStep*step,
+		HP:               int(math.Round(float64(template.HP) * math.Pow(endlessHPGrowth, float64(step)) * jitter())),
+		Attack:           int(math.Round(float64(template.Attack) * math.Pow(endlessAttackGrowth, float64(step)) * jitter())),
+		Floor:            floor,
+		Zone:             last[0].Zone + step,
+		IsBoss:           isBoss,
+		Biome:            flavor.Biome,
+		Role:             template.Role,
+		TargetWinRateMin: template.TargetWinRateMin,
+		TargetWinRateMax: template.TargetWinRateMax,
+		BossPhases:       flavor.BossPhases,
+		Endless:          true,
+	}, nil
+}
+
+// GetEndlessRecord returns the highest endless floor the character has won, 0 if none.
+func (e *Engine) GetEndlessRecord() (int, error) {
+	return e.DB.GetEndlessRecord(e.Character.ID)
+}
+
+// nextEndlessFloor returns the floor above the character's endless record.
+// A floor already fought is read back from the character's own rows; a new
+// one is generated with ID 0 and stored once the fight starts.
+func (e *Engine) nextEndlessFloor(userID int64, catalogEnemies []models.Enemy) (*models.Enemy, error) {
+	top, err := e.DB.GetEndlessRecord(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, enemy := range catalogEnemies {
+		top = max(top, enemy.Floor)
+	}
+
+	stored, err := e.DB.GetEndlessFloor(userID, top+1)
+	if err != nil || stored != nil {
+		return stored, err
+	}
+	entries, err := loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	seed, err := e.DB.GetEndlessSeed(userID)
+	if err != nil {
+		return nil, err
+	}
+	enemy, err := GenerateEndlessFloor(entries, seed, top+1)
+	if err != nil {
+		return nil, err
+	}
+	return &enemy, nil
+}
+
+// clearEndlessFloor raises the endless record after a won endless floor.
+func (e *Engine) clearEndlessFloor(enemy models.Enemy) error {
+	if !enemy.Endless {
+		return nil
+	}
+	return e.DB.RaiseEndlessRecord(e.Character.ID, enemy.Floor)
+}
+
+// catalogZones groups catalog entries by zone in floor order.
+func catalogZones(entries []catalog.Enemy) [][]catalog.Enemy {
+	var zones [][]catalog.Enemy
+	for _, c := range entries {
+		if n := len(zones); n > 0 && zones[n-1][0].Zone == c.Zone {
+			zones[n-1] = append(zones[n-1], c)
+			continue
+		}
+		zones = append(zones, []catalog.Enemy{c})
+	}
+	return zones
+}
+
+// endlessTemplate picks the zone entry a generated floor copies: the boss for
+// boss floors, otherwise the regular enemy in the same slot.
+func endlessTemplate(zone []catalog.Enemy, slot int, isBoss bool) catalog.Enemy {
+	var regulars []catalog.Enemy
+	for _, c := range zone {
+		if c.IsBoss {
+			if isBoss {
+				return c
+			}
+			continue
+		}
+		regulars = append(regulars, c)
+	}
+	if len(regulars) == 0 {
+		return zone[len(zone)-1]
+	}
+	return regulars[(slot-1)%len(regulars)]
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/models"
+)
+
+func clearCatalog(t *testing.T, e *Engine) {
+	t.Helper()
+	all, err := e.DB.GetAllEnemies()
+	if err != nil {
+		t.Fatalf("get enemies: %v", err)
+	}
+	for _, enemy := range all {
+		markDefeated(t, e, enemy.ID)
+	}
+}
+
+func TestGenerateEndlessFloorIsDeterministicAndScales(t *testing.T) {
+	entries := catalog.Enemies()
+	last := entries[len(entries)-1]
+
+	first, err := GenerateEndlessFloor(entries, 7, last.Floor+1)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	again, _ := GenerateEndlessFloor(entries, 7, last.Floor+1)
+	if first.Name != again.Name || first.HP != again.HP || first.Attack != again.Attack {
+		t.Fatalf("same seed and floor must give the same enemy: %+v vs %+v", first, again)
+	}
+	if !first.Endless || first.IsBoss || first.Zone != last.Zone+1 {
+		t.Fatalf("unexpected first endless floor: %+v", first)
+	}
+
+	boss, _ := GenerateEndlessFloor(entries, 7, last.Floor+endlessZoneFloors)
+	if !boss.IsBoss || len(boss.BossPhases) == 0 {
+		t.Fatalf("every tenth floor must be a boss with phases: %+v", boss)
+	}
+	if boss.HP <= last.HP {
+		t.Fatalf("endless boss must outgrow the last catalog boss: %d <= %d", boss.HP, last.HP)
+	}
+
+	next, _ := GenerateEndlessFloor(entries, 7, last.Floor+endlessZoneFloors+1)
+	if next.Biome == first.Biome {
+		t.Fatalf("biome should rotate between endless zones, got %s twice", first.Biome)
+	}
+
+	if _, err := GenerateEndlessFloor(entries, 7, last.Floor); err == nil {
+		t.Fatal("catalog floors must not be generated")
+	}
+}
+
+func TestEndlessFloorsAreStoredPerCharacterWhenFought(t *testing.T) {
+	e := newTestEngine(t)
+	clearCatalog(t, e)
+	before, _ := e.DB.GetEnemyCount()
+
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("expected a generated floor after the catalog, got %v (%v)", current, err)
+	}
+	lastFloor := catalog.Enemies()[len(catalog.Enemies())-1].Floor
+	if !current.Endless || current.Floor != lastFloor+1 || current.ID != 0 {
+		t.Fatalf("unexpected endless enemy: %+v", current)
+	}
+	if after, _ := e.DB.GetEnemyCount(); after != before {
+		t.Fatalf("showing a floor must not store it, got %d new enemies", after-before)
+	}
+	if record, _ := e.GetEndlessRecord(); record != 0 {
+		t.Fatalf("showing a floor must not raise the record, got %d", record)
+	}
+
+	state, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start endless battle: %v", err)
+	}
+	if state.Enemy.ID == 0 {
+		t.Fatal("the fought floor must be stored")
+	}
+	stored, err := e.DB.GetEndlessFloor(e.Character.ID, current.Floor)
+	if err != nil || stored == nil || stored.ID != state.Enemy.ID {
+		t.Fatalf("expected the floor stored for the character, got %v (%v)", stored, err)
+	}
+	if other, _ := e.DB.GetEndlessFloor(e.Character.ID+1, current.Floor); other != nil {
+		t.Fatalf("another character must not see the floor: %+v", other)
+	}
+	again, err := e.GetCurrentEnemy()
+	if err != nil || again.ID != state.Enemy.ID {
+		t.Fatalf("a fought floor must be picked again by its stored row: %v (%v)", again, err)
+	}
+
+	state.BattleOver = true
+	state.Result = models.BattleWin
+	if _, err := e.FinishBattle(state); err != nil {
+		t.Fatalf("finish endless battle: %v", err)
+	}
+	if record, _ := e.GetEndlessRecord(); record != current.Floor {
+		t.Fatalf("expected record %d after the win, got %d", current.Floor, record)
+	}
+	next, err := e.GetCurrentEnemy()
+	if err != nil || next == nil || next.Floor != current.Floor+1 {
+		t.Fatalf("expected floor %d next, got %v (%v)", current.Floor+1, next, err)
+	}
+
+	if err := e.InitEnemies(); err != nil {
+		t.Fatalf("init enemies: %v", err)
+	}
+	if record, _ := e.GetEndlessRecord(); record != current.Floor {
+		t.Fatal("startup catalog check must keep endless progress")
+	}
+}
@@ -2,11 +2,13 @@ package game
 
 import (
 	"fmt"
-	"math"
-	"math/rand"
 	"time"
 
+	"solo-leveling/internal/config"
 	"solo-leveling/internal/database"
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/game/combat"
+	"solo-leveling/internal/game/combat/formulas"
 	"solo-leveling/internal/game/combat/memory"
 	"solo-leveling/internal/models"
 )
@@ -16,6 +18,11 @@ type Engine struct {
 	Character             *models.Character
 	RecommendationSource  string
 	RecommendationDetails string
+
+	// AttemptRules makes fights cost battle attempts; nil keeps them free.
+	AttemptRules *models.AttemptRules
+	// Adaptive nudges the memory field towards each enemy's target win rate; nil turns it off.
+	Adaptive *models.AdaptiveRules
 }
 
 func NewEngine(db *database.DB) (*Engine, error) {
@@ -32,6 +39,9 @@ func NewEngine(db *database.DB) (*Engine, error) {
 	if err := e.InitAchievements(); err != nil {
 		return nil, fmt.Errorf("init achievements: %w", err)
 	}
+	if _, err := db.EnsureEndlessSeed(char.ID, memory.NewSeed()); err != nil {
+		return nil, fmt.Errorf("init endless tower: %w", err)
+	}
 	return e, nil
 }
 
@@ -57,319 +67,76 @@ func FloorName(floor int) string {
 	}
 }
 
+// GetPresetEnemies converts the embedded default enemy catalog into seedable enemies.
 func GetPresetEnemies() []models.Enemy {
-	type zoneTemplate struct {
-		Zone      int
-		Biome     string
-		MinLevel  int
-		MaxLevel  int
-		Names     []string
-		LoreLabel string
-	}
-
-	zones := []zoneTemplate{
-		{
-			Zone:      1,
-			Biome:     "swamp",
-			MinLevel:  2,
-			MaxLevel:  6,
-			LoreLabel: "Туманные Болота",
-			Names: []string{
-				"Квакающий Разведчик",
-				"Болотный Пиявочник",
-				"Гнилотный Шаман",
-				"Трясинный Волк",
-				"Моховой Голем",
-				"Токсичный Удильщик",
-				"Ведьмин Грибник",
-				"Слизень-Разъедатель",
-				"Пасть Топи",
-				"Хозяйка Туманов Морра",
-			},
-		},
-		{
-			Zone:      2,
-			Biome:     "ruins",
-			MinLevel:  7,
-			MaxLevel:  12,
-			LoreLabel: "Забытые Руины",
-			Names: []string{
-				"Ржавый Страж Портала",
-				"Пыльный Скелет-Рыцарь",
-				"Летучая Моль Проклятий",
-				"Крипт-Охотник",
-				"Костяной Арбалетчик",
-				"Каменный Идол",
-				"Тень Архива",
-				"Пожиратель Реликвий",
-				"Жрец Разломанных Печатей",
-				"Архонт Руин Кальдрос",
-			},
-		},
-		{
-			Zone:      3,
-			Biome:     "frost",
-			MinLevel:  13,
-			MaxLevel:  18,
-			LoreLabel: "Ледяные Пики",
-			Names: []string{
-				"Снежный Падальщик",
-				"Морозный Пехотинец",
-				"Ледяная Гарпия",
-				"Вьюжный Волк",
-				"Осколочный Голем",
-				"Северный Берсерк",
-				"Хрустальный Охотник",
-				"Ледяной Колдун",
-				"Белый Йети",
-				"Король Вьюги Хельгрим",
-			},
-		},
-		{
-			Zone:      4,
-			Biome:     "volcanic",
-			MinLevel:  19,
-			MaxLevel:  24,
-			LoreLabel: "Пепельные Разломы",
-			Names: []string{
-				"Пепельный Разбойник",
-				"Обугленный Скелет",
-				"Лавовый Плевун",
-				"Огненный Гончий",
-				"Шлаковый Голем",
-				"Жрец Пепла",
-				"Крылатый Угольник",
-				"Демон Искр",
-				"Плавильщик Костей",
-				"Владыка Разломов Азгар",
-			},
-		},
-		{
-			Zone:      5,
-			Biome:     "void",
-			MinLevel:  25,
-			MaxLevel:  30,
-			LoreLabel: "Цитадель Бездны",
-			Names: []string{
-				"Безликий Смотритель",
-				"Паразит Пустоты",
-				"Теневой Дуэлянт",
-				"Пожиратель Света",
-				"Хор Бездны",
-				"Клеймённый Инквизитор",
-				"Рыцарь Нулевой Тени",
-				"Коготь Монарха",
-				"Оракул Тишины",
-				"Монарх Бездны Ноктэрн",
-			},
-		},
-	}
-
-	enemies := make([]models.Enemy, 0, 50)
-	floor := 1
-
-	for _, z := range zones {
-		for i, name := range z.Names {
-			slot := i + 1
-			level := levelForSlot(z.MinLevel, z.MaxLevel, slot)
-			role := roleForSlot(slot)
-			targetMin, targetMax := targetWinrateForSlot(slot)
-			isBoss := slot == 10
-
-			typeValue := models.EnemyRegular
-			if isBoss {
-				typeValue = models.EnemyBoss
-			}
-
-			baseHP := 120 + level*26
-			baseATK := 8 + int(math.Round(float64(level)*0.9))
-			power := rolePowerMultiplier(slot)
-			hp := int(math.Round(float64(baseHP) * power))
-			atk := int(math.Round(float64(baseATK) * (0.8 + power*0.35)))
-
-			enemies = append(enemies, models.Enemy{
-				Name:             name,
-				Description:      fmt.Sprintf("%s: %s", z.LoreLabel, roleLore(role)),
-				Rank:             rankForZoneAndSlot(z.Zone, slot),
-				Type:             typeValue,
-				Level:            level,
-				HP:               hp,
-				Attack:           atk,
-				Floor:            floor,
-				Zone:             z.Zone,
-				IsBoss:           isBoss,
-				Biome:            z.Biome,
-				Role:             role,
-				IsTransition:     slot <= 2,
-				TargetWinRateMin: targetMin,
-				TargetWinRateMax: targetMax,
-			})
-			floor++
-		}
-	}
-
-	return enemies
+	return presetFromCatalog(catalog.Enemies())
 }
 
-func (e *Engine) InitEnemies() error {
-	preset := GetPresetEnemies()
-	needsReseed, err := e.DB.EnemyCatalogNeedsReseed(preset)
+// LoadPresetEnemies returns the enemy catalog the game should seed: the user
+// override from the data directory when present, the embedded default otherwise.
+func LoadPresetEnemies() ([]models.Enemy, error) {
+	entries, err := loadCatalog()
 	if err != nil {
-		return err
-	}
-	if needsReseed {
-		if err := e.DB.ReplaceEnemyCatalog(preset); err != nil {
-			return err
-		}
+		return nil, err
 	}
-	return e.DB.NormalizeEnemyZones()
+	return presetFromCatalog(entries), nil
 }
 
-func levelForSlot(minLevel, maxLevel, slot int) int {
-	pattern := []int{0, 1, 1, 2, 2, 3, 4, 4, 5, 5}
-	if slot < 1 {
-		slot = 1
-	}
-	if slot > len(pattern) {
-		slot = len(pattern)
-	}
-	level := minLevel + pattern[slot-1]
-	if level > maxLevel {
-		level = maxLevel
+func loadCatalog() ([]catalog.Enemy, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
 	}
-	return level
+	return catalog.Load(dir)
 }
 
-func roleForSlot(slot int) string {
-	switch slot {
-	case 1:
-		return "TRANSITION"
-	case 2:
-		return "TRANSITION_ELITE"
-	case 3:
-		return "NORMAL"
-	case 4:
-		return "HARD"
-	case 5:
-		return "EASY"
-	case 6:
-		return "HARD"
-	case 7:
-		return "ELITE"
-	case 8:
-		return "NORMAL"
-	case 9:
-		return "MINIBOSS"
-	case 10:
-		return "BOSS"
-	default:
-		return "NORMAL"
+func presetFromCatalog(entries []catalog.Enemy) []models.Enemy {
+	enemies := make([]models.Enemy, 0, len(entries))
+	for _, c := range entries {
+		typeValue := models.EnemyRegular
+		if c.IsBoss {
+			typeValue = models.EnemyBoss
+		}
+		enemies = append(enemies, models.Enemy{
+			Name:             c.Name,
+			Description:      c.Description,
+			Rank:             c.Rank,
+			Type:             typeValue,
+			Level:            c.Level,
+			HP:               c.HP,
+			Attack:           c.Attack,
+			Floor:            c.Floor,
+			Zone:             c.Zone,
+			IsBoss:           c.IsBoss,
+			Biome:            c.Biome,
+			Role:             c.Role,
+			Image:            c.Image,
+			IsTransition:     c.IsTransition,
+			TargetWinRateMin: c.TargetWinRateMin,
+			TargetWinRateMax: c.TargetWinRateMax,
+			BossPhases:       c.BossPhases,
+		})
 	}
+	return enemies
 }
 
-func rolePowerMultiplier(slot int) float64 {
-	switch slot {
-	case 1:
-		return 1.15
-	case 2:
-		return 1.22
-	case 3:
-		return 1.00
-	case 4:
-		return 1.08
-	case 5:
-		return 0.90
-	case 6:
-		return 1.10
-	case 7:
-		return 1.20
-	case 8:
-		return 1.02
-	case 9:
-		return 1.30
-	case 10:
-		return 1.38
-	default:
-		return 1.00
+func (e *Engine) InitEnemies() error {
+	preset, err := LoadPresetEnemies()
+	if err != nil {
+		return err
 	}
-}
-
-func targetWinrateForSlot(slot int) (float64, float64) {
-	switch slot {
-	case 1:
-		return 15, 25
-	case 2:
-		return 12, 20
-	case 3:
-		return 30, 45
-	case 4:
-		return 20, 30
-	case 5:
-		return 45, 60
-	case 6:
-		return 20, 30
-	case 7:
-		return 12, 20
-	case 8:
-		return 30, 45
-	case 9:
-		return 8, 15
-	case 10:
-		return 5, 12
-	default:
-		return 30, 45
+	needsReseed, err := e.DB.EnemyCatalogNeedsReseed(preset)
+	if err != nil {
+		return err
 	}
-}
-
-func rankForZoneAndSlot(zone, slot int) models.QuestRank {
-	switch zone {
-	case 1:
-		if slot <= 5 {
-			return models.RankE
-		}
-		return models.RankD
-	case 2:
-		if slot <= 5 {
-			return models.RankC
-		}
-		return models.RankB
-	case 3:
-		if slot <= 4 {
-			return models.RankB
-		}
-		if slot <= 8 {
-			return models.RankA
-		}
-		return models.RankS
-	case 4:
-		if slot <= 5 {
-			return models.RankA
+	if needsReseed {
+		if err := e.DB.ReplaceEnemyCatalog(preset); err != nil {
+			return err
 		}
-		return models.RankS
-	default:
-		return models.RankS
-	}
-}
-
-func roleLore(role string) string {
-	switch role {
-	case "TRANSITION":
-		return "входной страж зоны: опасен с первых секунд."
-	case "TRANSITION_ELITE":
-		return "пограничный элитный противник, проверяет базу билда."
-	case "HARD":
-		return "усиливает давление и наказывает ошибки."
-	case "EASY":
-		return "тактическая передышка, но не бесплатная."
-	case "ELITE":
-		return "элитный враг с усиленной выживаемостью."
-	case "MINIBOSS":
-		return "мини-босс, близок к порогу зоны."
-	case "BOSS":
-		return "властитель зоны и ключ к следующему этапу."
-	default:
-		return "боевой противник башни."
+	} else if err := e.DB.SyncEnemyCatalogStats(preset); err != nil {
+		return err
 	}
+	return e.DB.NormalizeEnemyZones()
 }
 
 func (e *Engine) GetEnemies() ([]models.Enemy, error) {
@@ -403,86 +170,93 @@ func (e *Engine) StartBattle(enemyID int64) (*models.BattleState, error) {
 	if enemy.Type == models.EnemyBoss {
 		return nil, fmt.Errorf("этот противник требует бой с боссом")
 	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		return nil, err
+	}
 	if err := e.spendBattleAttempt(); err != nil {
 		return nil, err
 	}
+	if err := e.adaptEnemy(enemy); err != nil {
+		return nil, err
+	}
 
-	stats, err := e.GetStatLevels()
+	memStats, err := e.combatStats()
 	if err != nil {
 		return nil, err
 	}
 
+	state, err := newBattleState(*enemy, memStats, memory.NewSeed())
+	if err != nil {
+		return nil, err
+	}
+	if err := e.saveBattle(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// combatStatsFromLevels picks the combat stats out of the character's stat levels.
+func combatStatsFromLevels(levels []models.StatLevel) memory.Stats {
 	statMap := make(map[models.StatType]int)
-	for _, s := range stats {
+	for _, s := range levels {
 		statMap[s.StatType] = s.Level
 	}
-
-	memStats := memory.Stats{
+	return memory.Stats{
 		STR: statMap[models.StatStrength],
 		AGI: statMap[models.StatAgility],
 		INT: statMap[models.StatIntellect],
 		STA: statMap[models.StatEndurance],
 	}
-	playerHP := memory.PlayerHP(memStats.STA)
-	gridSize := memory.GridSize(*enemy)
-	cellsToShow := memory.CellsToShow(*enemy, memStats)
-	showTimeMs := memory.TimeToShow(memStats)
+}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	shown, err := memory.GenerateShownCells(gridSize, cellsToShow, rng)
+// newBattleState deals the opening round of a regular fight from seed.
+func newBattleState(enemy models.Enemy, stats memory.Stats, seed int64) (*models.BattleState, error) {
+	playerHP := memory.MaxHP(stats)
+	kind := combat.KindForEnemy(enemy)
+	challenge, err := combat.NewRound(kind, enemy, stats, memory.RoundRNG(seed, 0))
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.BattleState{
-		Enemy:       *enemy,
+	state := &models.BattleState{
+		Enemy:       enemy,
+		Seed:        seed,
+		Minigame:    kind,
 		PlayerHP:    playerHP,
 		PlayerMaxHP: playerHP,
 		EnemyHP:     enemy.HP,
 		EnemyMaxHP:  enemy.HP,
 		Round:       1,
-		GridSize:    gridSize,
-		CellsToShow: cellsToShow,
-		ShowTimeMs:  showTimeMs,
-		ShownCells:  shown,
-	}, nil
+	}
+	setBattleChallenge(state, challenge)
+	return state, nil
 }
 
-// ProcessRound evaluates one visual-memory round.
+// ProcessRound evaluates one minigame round.
 func (e *Engine) ProcessRound(state *models.BattleState, choices []int) error {
 	if state.BattleOver {
 		return fmt.Errorf("battle is already over")
 	}
 
-	stats, err := e.GetStatLevels()
+	statsForRound, err := e.combatStats()
 	if err != nil {
 		return err
 	}
 
-	statMap := make(map[models.StatType]int)
-	for _, s := range stats {
-		statMap[s.StatType] = s.Level
-	}
-
-	str := statMap[models.StatStrength]
-	statsForRound := memory.Stats{
-		STR: str,
-		AGI: statMap[models.StatAgility],
-		INT: statMap[models.StatIntellect],
-		STA: statMap[models.StatEndurance],
-	}
-
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	accuracy := memory.ComputeAccuracy(state.ShownCells, choices)
-	hits := memory.CorrectClicks(state.ShownCells, choices)
-	total := state.CellsToShow
+	rng := memory.RoundRNG(state.Seed, state.Round)
+	challenge := BattleChallenge(state)
+	result := combat.ForKind(challenge.Kind).Evaluate(challenge, choices)
+	accuracy := result.Accuracy
+	hits := result.Hits
+	total := result.Total
 	misses := total - hits
 	if misses < 0 {
 		misses = 0
 	}
 
-	damage, isCrit := memory.ComputePlayerDamage(statsForRound, accuracy, rng)
-	enemyDamage := memory.ComputeEnemyDamage(state.Enemy, statsForRound, accuracy, rng)
+	critChance := memory.CritChanceAgainst(state.Enemy, statsForRound)
+	exchange := formulas.ResolveRoundWithCrit(statsForRound.STR, critChance, statsForRound.STA, state.Enemy.Attack, accuracy, rng)
+	damage, isCrit, enemyDamage := exchange.PlayerDamage, exchange.Crit, exchange.EnemyDamage
 	if isCrit {
 		state.TotalCrits++
 	}
@@ -504,6 +278,20 @@ func (e *Engine) ProcessRound(state *models.BattleState, choices []int) error {
 	state.LastRoundAccuracy = accuracy
 	state.LastRoundCrit = isCrit
 
+	state.Rounds = append(state.Rounds, models.BattleRound{
+		Round:       state.Round,
+		Minigame:    challenge.Kind,
+		GridSize:    challenge.GridSize,
+		ShownCells:  append([]int(nil), challenge.Answer...),
+		Choices:     append([]int(nil), choices...),
+		Accuracy:    accuracy,
+		DamageDealt: damage,
+		DamageTaken: enemyDamage,
+		IsCrit:      isCrit,
+		ShowTimeMs:  state.ShowTimeMs,
+		DurationMs:  models.RoundDurationMs(state.RoundStartedAt, time.Now()),
+	})
+
 	logLine := fmt.Sprintf("Раунд %d: %.0f%% (%d/%d) → %d урона", state.Round, accuracy*100, hits, total, damage)
 	state.RoundLog = append(state.RoundLog, logLine)
 	if isCrit {
@@ -512,6 +300,13 @@ func (e *Engine) ProcessRound(state *models.BattleState, choices []int) error {
 	if enemyDamage > 0 {
 		state.RoundLog = append(state.RoundLog, fmt.Sprintf("Враг атакует: -%d HP", enemyDamage))
 	}
+	if regen := memory.RegenPerRound(state.Enemy); regen > 0 && state.EnemyHP > 0 && state.PlayerHP > 0 {
+		state.EnemyHP += regen
+		if state.EnemyHP > state.EnemyMaxHP {
+			state.EnemyHP = state.EnemyMaxHP
+		}
+		state.RoundLog = append(state.RoundLog, fmt.Sprintf("Враг регенерирует: +%d HP", regen))
+	}
 	if len(state.RoundLog) > 6 {
 		state.RoundLog = state.RoundLog[len(state.RoundLog)-6:]
 	}
@@ -527,20 +322,38 @@ func (e *Engine) ProcessRound(state *models.BattleState, choices []int) error {
 		state.Result = models.BattleLose
 	} else {
 		state.Round++
-		state.CellsToShow = memory.CellsToShow(state.Enemy, statsForRound)
-		state.ShowTimeMs = memory.TimeToShow(statsForRound)
-		shown, err := memory.GenerateShownCells(state.GridSize, state.CellsToShow, rng)
+		next, err := dealRound(state, challenge.Kind, statsForRound, rng)
 		if err != nil {
 			return err
 		}
-		state.ShownCells = shown
+		setBattleChallenge(state, next)
 	}
 
 	state.PlayerChoices = choices
-	return nil
+	return e.saveBattle(state)
 }
 
-// FinishBattle records the battle result.
+// setBattleChallenge makes round the current challenge and mirrors it into the grid fields.
+func setBattleChallenge(state *models.BattleState, round models.MinigameRound) {
+	state.Challenge = round
+	state.GridSize = round.GridSize
+	state.CellsToShow = round.Inputs
+	state.ShowTimeMs = round.ShowTimeMs
+	state.ShownCells = round.Cells
+	state.RoundStartedAt = time.Now()
+}
+
+// BattleChallenge returns the current round, rebuilding a grid round for
+// states saved before minigames existed.
+func BattleChallenge(state *models.BattleState) models.MinigameRound {
+	if state.Challenge.Kind != "" {
+		return state.Challenge
+	}
+	return combat.GridRound(state.GridSize, state.ShownCells, state.ShowTimeMs)
+}
+
+// FinishBattle records the battle result. Practice fights go to the practice
+// history instead and leave attempts, rewards and progression alone.
 func (e *Engine) FinishBattle(state *models.BattleState) (*models.BattleRecord, error) {
 	accuracy := 0.0
 	totalPatternCells := state.TotalHits + state.TotalMisses
@@ -558,6 +371,12 @@ func (e *Engine) FinishBattle(state *models.BattleState) (*models.BattleRecord,
 		Accuracy:     accuracy,
 		CriticalHits: state.TotalCrits,
 		Dodges:       state.TotalDodges,
+		Seed:         state.Seed,
+		ChallengeDay: state.ChallengeDay,
+		Rounds:       state.Rounds,
+	}
+	if state.Practice {
+		return e.finishPractice(state, record)
 	}
 
 	if state.Result == models.BattleWin {
@@ -569,15 +388,25 @@ func (e *Engine) FinishBattle(state *models.BattleState) (*models.BattleRecord,
 	if err := e.DB.InsertBattle(record); err != nil {
 		return nil, err
 	}
+	if state.Result == models.BattleWin && state.ChallengeDay != "" {
+		if err := e.grantChallengeStreak(record); err != nil {
+			return nil, err
+		}
+	} else if state.Result == models.BattleWin {
+		if err := e.grantFirstWin(record, state.Enemy); err != nil {
+			return nil, err
+		}
+		if err := e.clearEndlessFloor(state.Enemy); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.DB.DeleteActiveBattle(e.Character.ID); err != nil {
+		return nil, err
+	}
 
 	return record, nil
 }
 
-// GetShowTimeMs returns adjusted show time (ms) based on accessory bonuses
-func (e *Engine) GetShowTimeMs(baseTimeMs int) (int, error) {
-	return baseTimeMs, nil
-}
-
 func (e *Engine) GetBattleStats() (*models.BattleStatistics, error) {
 	return e.DB.GetBattleStats(e.Character.ID)
 }
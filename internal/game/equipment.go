@@ -0,0 +1,152 @@
+package game
+
+import (
+	"solo-leveling/internal/game/combat/formulas"
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Equipment
+// ============================================================
+
+// itemCatalog lists every item that can drop. Stored items refer to it by key,
+// so rebalancing an item here changes copies already owned.
+var itemCatalog = []models.Item{
+	{Key: "rusty_dagger", Name: "Ржавый кинжал", Slot: models.SlotWeapon, Effects: models.ItemEffects{STR: 2}},
+	{Key: "hunter_blade", Name: "Клинок охотника", Slot: models.SlotWeapon, Effects: models.ItemEffects{STR: 3, CritPercent: 3}},
+	{Key: "shadow_fang", Name: "Клык тени", Slot: models.SlotWeapon, Effects: models.ItemEffects{AGI: 2, CritPercent: 6}},
+	{Key: "leather_vest", Name: "Кожаный жилет", Slot: models.SlotArmor, Effects: models.ItemEffects{BonusHP: 20}},
+	{Key: "knight_plate", Name: "Латы рыцаря", Slot: models.SlotArmor, Effects: models.ItemEffects{STA: 2, BonusHP: 30}},
+	{Key: "frost_cloak", Name: "Морозный плащ", Slot: models.SlotArmor, Effects: models.ItemEffects{STA: 1, ShowTimePercent: 5}},
+	{Key: "focus_ring", Name: "Кольцо сосредоточения", Slot: models.SlotAccessory, Effects: models.ItemEffects{ShowTimePercent: 10}},
+	{Key: "sage_amulet", Name: "Амулет мудреца", Slot: models.SlotAccessory, Effects: models.ItemEffects{INT: 2}},
+	{Key: "lucky_charm", Name: "Талисман удачи", Slot: models.SlotAccessory, Effects: models.ItemEffects{AGI: 1, CritPercent: 5}},
+}
+
+// ItemByKey returns the catalog item with the given key.
+func ItemByKey(key string) (models.Item, bool) {
+	for _, item := range itemCatalog {
+		if item.Key == key {
+			return item, true
+		}
+	}
+	return models.Item{}, false
+}
+
+// GetInventory returns the character's items, newest first. Items whose key
+// is no longer in the catalog are skipped.
+func (e *Engine) GetInventory() ([]models.InventoryItem, error) {
+	stored, err := e.DB.GetInventory(e.Character.ID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.InventoryItem, 0, len(stored))
+	for _, it := range stored {
+		item, ok := ItemByKey(it.Item.Key)
+		if !ok {
+			continue
+		}
+		it.Item = item
+		items = append(items, it)
+	}
+	return items, nil
+}
+
+// GetEquipment returns the equipped items by slot.
+func (e *Engine) GetEquipment() (map[models.EquipmentSlot]models.InventoryItem, error) {
+	items, err := e.GetInventory()
+	if err != nil {
+		return nil, err
+	}
+	equipped := make(map[models.EquipmentSlot]models.InventoryItem)
+	for _, it := range items {
+		if it.Equipped {
+			equipped[it.Item.Slot] = it
+		}
+	}
+	return equipped, nil
+}
+
+// GetEquipmentEffects sums the effects of every equipped item.
+func (e *Engine) GetEquipmentEffects() (models.ItemEffects, error) {
+	equipped, err := e.GetEquipment()
+	if err != nil {
+		return models.ItemEffects{}, err
+	}
+	var total models.ItemEffects
+	for _, it := range equipped {
+		total = total.Add(it.Item.Effects)
+	}
+	return total, nil
+}
+
+// EquipItem puts an inventory item on, replacing the one in its slot.
+func (e *Engine) EquipItem(itemID int64) error {
+	return e.DB.SetItemEquipped(e.Character.ID, itemID, true)
+}
+
+// UnequipItem takes an inventory item off.
+func (e *Engine) UnequipItem(itemID int64) error {
+	return e.DB.SetItemEquipped(e.Character.ID, itemID, false)
+}
+
+// GetCombatStats returns the stats a fight starts with, equipment included.
+func (e *Engine) GetCombatStats() (memory.Stats, error) {
+	return e.combatStats()
+}
+
+// combatStats returns the character's combat stats with equipment applied.
+func (e *Engine) combatStats() (memory.Stats, error) {
+	levels, err := e.GetStatLevels()
+	if err != nil {
+		return memory.Stats{}, err
+	}
+	effects, err := e.GetEquipmentEffects()
+	if err != nil {
+		return memory.Stats{}, err
+	}
+	return applyEquipment(combatStatsFromLevels(levels), effects), nil
+}
+
+// applyEquipment adds item effects to combat stats.
+func applyEquipment(stats memory.Stats, effects models.ItemEffects) memory.Stats {
+	stats.STR += effects.STR
+	stats.AGI += effects.AGI
+	stats.INT += effects.INT
+	stats.STA += effects.STA
+	stats.CritBonus += float64(effects.CritPercent) / 100
+	stats.BonusHP += effects.BonusHP
+	stats.ShowTimePercent += effects.ShowTimePercent
+	return stats
+}
+
+// pickDrop chooses a random catalog item the character does not own yet, or
+// nil once the whole catalog is owned. seed makes the pick reproducible; the
+// caller stores the item.
+func (e *Engine) pickDrop(source string, seed int64) (*models.InventoryItem, error) {
+	owned, err := e.GetInventory()
+	if err != nil {
+		return nil, err
+	}
+	have := make(map[string]bool, len(owned))
+	for _, it := range owned {
+		have[it.Item.Key] = true
+	}
+	pool := make([]models.Item, 0, len(itemCatalog))
+	for _, item := range itemCatalog {
+		if !have[item.Key] {
+			pool = append(pool, item)
+		}
+	}
+	if len(pool) == 0 {
+		return nil, nil
+	}
+
+	rng := formulas.RoundSource(seed, 0)
+	return &models.InventoryItem{
+		CharID: e.Character.ID,
+		Item:   pool[rng.Intn(len(pool))],
+		Source: source,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/models"
+)
+
+func TestBossWinDropsNewItems(t *testing.T) {
+	e := newTestEngine(t)
+	regular, bossEnemy := zoneEnemies(t, e, 1)
+	for _, enemy := range regular {
+		markDefeated(t, e, enemy.ID)
+	}
+
+	state, err := e.StartBossBattle(bossEnemy.ID)
+	if err != nil {
+		t.Fatalf("start boss battle: %v", err)
+	}
+	record, err := e.FinishBoss(state)
+	if err != nil {
+		t.Fatalf("finish boss: %v", err)
+	}
+	items, err := e.GetInventory()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected one dropped item, got %+v (%v)", items, err)
+	}
+	if record.RewardItem != items[0].Item.Name || items[0].Source != bossEnemy.Name || items[0].Item.Slot == "" {
+		t.Fatalf("drop not reported or stored correctly: %q vs %+v", record.RewardItem, items[0])
+	}
+
+	saved, err := e.GetSavedBattle()
+	if err != nil || saved != nil {
+		t.Fatalf("the win must clear the saved fight with the drop, got %+v (%v)", saved, err)
+	}
+
+	// A repeat win over the same boss drops nothing.
+	record, err = e.FinishBoss(state)
+	if err != nil {
+		t.Fatalf("finish boss again: %v", err)
+	}
+	if items, _ := e.GetInventory(); record.RewardItem != "" || len(items) != 1 {
+		t.Fatalf("only the first clear drops an item, got %q and %d items", record.RewardItem, len(items))
+	}
+}
+
+func TestDropsStopOnceTheCatalogIsOwned(t *testing.T) {
+	e := newTestEngine(t)
+	seen := map[string]bool{}
+	for i := 0; i < len(itemCatalog); i++ {
+		drop, err := e.pickDrop("test", int64(i))
+		if err != nil || drop == nil {
+			t.Fatalf("expected a new item while the catalog is not owned, got %+v (%v)", drop, err)
+		}
+		if seen[drop.Item.Key] {
+			t.Fatalf("%s dropped twice before the catalog was exhausted", drop.Item.Key)
+		}
+		seen[drop.Item.Key] = true
+		if err := e.DB.InsertInventoryItem(drop); err != nil {
+			t.Fatalf("insert item: %v", err)
+		}
+	}
+	if drop, err := e.pickDrop("test", 99); err != nil || drop != nil {
+		t.Fatalf("an owned catalog must not drop duplicates, got %+v (%v)", drop, err)
+	}
+}
+
+func TestExpeditionDropIsSeededByRun(t *testing.T) {
+	e := newTestEngine(t)
+	expedition := models.Expedition{
+		Name:   "Экспедиция за трофеем",
+		Status: models.ExpeditionActive,
+		Tasks:  []models.ExpeditionTask{{Title: "Финал", ProgressTarget: 1, RewardEXP: 10, TargetStat: models.StatStrength}},
+	}
+	if err := e.CreateExpedition(&expedition); err != nil {
+		t.Fatalf("create expedition: %v", err)
+	}
+	stored, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil || stored.RunID == 0 {
+		t.Fatalf("expected a started run, got %+v (%v)", stored, err)
+	}
+	want, err := e.pickDrop(expedition.Name, stored.RunID)
+	if err != nil {
+		t.Fatalf("pick drop: %v", err)
+	}
+
+	if _, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 1); err != nil {
+		t.Fatalf("finish task: %v", err)
+	}
+	items, err := e.GetInventory()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected one dropped item, got %+v (%v)", items, err)
+	}
+	if items[0].Item.Key != want.Item.Key || items[0].Source != expedition.Name {
+		t.Fatalf("expected %s from the run seed, got %+v", want.Item.Key, items[0])
+	}
+}
+
+func TestEquipmentAppliesToFights(t *testing.T) {
+	e := newTestEngine(t)
+	current, _ := e.GetCurrentEnemy()
+	plain, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		t.Fatalf("forfeit: %v", err)
+	}
+
+	give := func(key string) int64 {
+		item, _ := ItemByKey(key)
+		it := &models.InventoryItem{CharID: e.Character.ID, Item: item, Source: "test"}
+		if err := e.DB.InsertInventoryItem(it); err != nil {
+			t.Fatalf("insert item: %v", err)
+		}
+		return it.ID
+	}
+	vest, plate, ring := give("leather_vest"), give("knight_plate"), give("focus_ring")
+	for _, id := range []int64{vest, plate, ring} {
+		if err := e.EquipItem(id); err != nil {
+			t.Fatalf("equip: %v", err)
+		}
+	}
+	equipped, _ := e.GetEquipment()
+	if equipped[models.SlotArmor].ID != plate || len(equipped) != 2 {
+		t.Fatalf("equipping must replace the item in the same slot: %+v", equipped)
+	}
+
+	armed, err := e.StartBattle(current.ID)
+	if err != nil {
+		t.Fatalf("start battle: %v", err)
+	}
+	plateItem, _ := ItemByKey("knight_plate")
+	if armed.PlayerMaxHP < plain.PlayerMaxHP+plateItem.Effects.BonusHP {
+		t.Fatalf("armor should raise HP: %d -> %d", plain.PlayerMaxHP, armed.PlayerMaxHP)
+	}
+	if want := plain.ShowTimeMs * 110 / 100; armed.ShowTimeMs < want-1 || armed.ShowTimeMs > want+1 {
+		t.Fatalf("focus ring should lengthen the dealt round to ~%dms, got %d", want, armed.ShowTimeMs)
+	}
+
+	if err := e.UnequipItem(plate); err != nil {
+		t.Fatalf("unequip: %v", err)
+	}
+	stats, _ := e.GetCombatStats()
+	if stats.BonusHP != 0 {
+		t.Fatalf("unequipped armor still counts: %+v", stats)
+	}
+}
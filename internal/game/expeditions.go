@@ -26,6 +26,7 @@ func GetPresetExpeditions() []models.Expedition {
 			Description:  "Долгосрочный старт: выстроить базовую дисциплину и ритм.",
 			RewardEXP:    50,
 			RewardStats:  map[models.StatType]int{models.StatStrength: 10, models.StatEndurance: 10},
+			Rewards:      []models.Reward{{Kind: models.RewardBadge, Value: "🌅"}},
 			IsRepeatable: false,
 			Status:       models.ExpeditionActive,
 			Tasks: []models.ExpeditionTask{
@@ -100,6 +101,10 @@ func GetPresetExpeditions() []models.Expedition {
 				models.StatIntellect: 25,
 				models.StatEndurance: 25,
 			},
+			Rewards: []models.Reward{
+				{Kind: models.RewardTitle, Value: "Универсал"},
+				{Kind: models.RewardAttempts, Amount: 2},
+			},
 			IsRepeatable: false,
 			Status:       models.ExpeditionActive,
 			Tasks: []models.ExpeditionTask{
@@ -120,6 +125,11 @@ func GetPresetExpeditions() []models.Expedition {
 				models.StatIntellect: 60,
 				models.StatEndurance: 60,
 			},
+			Rewards: []models.Reward{
+				{Kind: models.RewardTitle, Value: "Живая Легенда"},
+				{Kind: models.RewardBadge, Value: "👑"},
+				{Kind: models.RewardItem, Value: "Золотая рамка аватара"},
+			},
 			IsRepeatable: false,
 			Status:       models.ExpeditionActive,
 			Tasks: []models.ExpeditionTask{
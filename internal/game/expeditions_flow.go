@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"solo-leveling/internal/database"
 	"solo-leveling/internal/models"
 )
 
@@ -41,11 +42,11 @@ func (e *Engine) CreateExpedition(expedition *models.Expedition) error {
 	if expedition.Status == "" {
 		expedition.Status = models.ExpeditionActive
 	}
-	return e.DB.InsertExpedition(expedition)
+	return e.DB.CreateExpedition(e.Character.ID, expedition)
 }
 
 func (e *Engine) RefreshExpeditionStatuses(failExpired bool) error {
-	expeditions, err := e.DB.GetAllExpeditions()
+	expeditions, err := e.DB.GetAllExpeditions(e.Character.ID)
 	if err != nil {
 		return err
 	}
@@ -56,10 +57,7 @@ func (e *Engine) RefreshExpeditionStatuses(failExpired bool) error {
 			continue
 		}
 		if failExpired && ex.Deadline != nil && now.After(*ex.Deadline) {
-			if err := e.DB.UpdateExpeditionStatus(ex.ID, models.ExpeditionFailed); err != nil {
-				return err
-			}
-			if err := e.DB.FailActiveQuestsByExpedition(e.Character.ID, ex.ID); err != nil {
+			if err := e.resolveExpiredExpedition(&ex); err != nil {
 				return err
 			}
 			continue
@@ -79,36 +77,31 @@ func (e *Engine) RefreshExpeditionStatuses(failExpired bool) error {
 }
 
 func (e *Engine) StartExpedition(expeditionID int64) (int, error) {
-	expedition, err := e.DB.GetExpeditionByID(expeditionID)
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
 	if err != nil {
 		return 0, err
 	}
 
-	if expedition.Status == models.ExpeditionFailed {
-		if !expedition.IsRepeatable {
-			return 0, fmt.Errorf("expedition failed and is not repeatable")
-		}
-		if err := e.DB.ResetExpeditionTasks(expeditionID); err != nil {
-			return 0, err
-		}
-		if err := e.DB.UpdateExpeditionStatus(expeditionID, models.ExpeditionActive); err != nil {
-			return 0, err
-		}
+	if expedition.Status == models.ExpeditionFailed && !expedition.IsRepeatable {
+		return 0, fmt.Errorf("expedition failed and is not repeatable")
 	}
-
-	if expedition.Status == models.ExpeditionCompleted {
-		if !expedition.IsRepeatable {
-			return 0, fmt.Errorf("expedition already completed")
-		}
-		if err := e.DB.ResetExpeditionTasks(expeditionID); err != nil {
-			return 0, err
-		}
-		if err := e.DB.UpdateExpeditionStatus(expeditionID, models.ExpeditionActive); err != nil {
+	if expedition.Status == models.ExpeditionCompleted && !expedition.IsRepeatable {
+		return 0, fmt.Errorf("expedition already completed")
+	}
+	if expedition.Status == models.ExpeditionAbandoned && !expedition.IsRepeatable {
+		return 0, fmt.Errorf("экспедиция покинута и не повторяется")
+	}
+	if expedition.Status == models.ExpeditionPaused {
+		return 0, fmt.Errorf("экспедиция на паузе: сначала продолжите её")
+	}
+	// A repeat starts a new run; the finished run keeps its own progress.
+	if expedition.RunID == 0 || expedition.Status != models.ExpeditionActive {
+		if _, err := e.DB.StartExpeditionRun(e.Character.ID, expeditionID); err != nil {
 			return 0, err
 		}
 	}
 
-	tasks, err := e.DB.GetExpeditionTasks(expeditionID)
+	tasks, err := e.DB.GetExpeditionTasks(e.Character.ID, expeditionID)
 	if err != nil {
 		return 0, err
 	}
@@ -134,6 +127,107 @@ func (e *Engine) StartExpedition(expeditionID int64) (int, error) {
 	return spawned, nil
 }
 
+// PauseExpedition freezes an active expedition. Its quests are archived and
+// the deadline stops counting until ResumeExpedition.
+func (e *Engine) PauseExpedition(expeditionID int64) error {
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
+	if err != nil {
+		return err
+	}
+	if expedition.Status != models.ExpeditionActive {
+		return fmt.Errorf("поставить на паузу можно только активную экспедицию")
+	}
+	runID, err := e.DB.CurrentExpeditionRunID(e.Character.ID, expeditionID)
+	if err != nil {
+		return err
+	}
+	if runID == 0 {
+		if runID, err = e.DB.StartExpeditionRun(e.Character.ID, expeditionID); err != nil {
+			return err
+		}
+	}
+	if err := e.DB.ArchiveActiveQuestsByExpedition(e.Character.ID, expeditionID); err != nil {
+		return err
+	}
+	return e.DB.PauseExpeditionRun(runID, time.Now())
+}
+
+// ResumeExpedition reactivates a paused expedition, shifts its deadline by the
+// paused time and respawns quests for unfinished tasks.
+func (e *Engine) ResumeExpedition(expeditionID int64) (int, error) {
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
+	if err != nil {
+		return 0, err
+	}
+	if expedition.Status != models.ExpeditionPaused {
+		return 0, fmt.Errorf("экспедиция не на паузе")
+	}
+
+	deadline := expedition.Deadline
+	if deadline != nil && expedition.PausedAt != nil {
+		shifted := deadline.Add(time.Since(*expedition.PausedAt))
+		deadline = &shifted
+	}
+	if err := e.DB.ResumeExpeditionRun(expedition.RunID, deadline); err != nil {
+		return 0, err
+	}
+	return e.StartExpedition(expeditionID)
+}
+
+// AbandonExpedition ends the current run without rewards or a failure mark.
+// Its quests are archived rather than failed.
+func (e *Engine) AbandonExpedition(expeditionID int64) error {
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
+	if err != nil {
+		return err
+	}
+	if expedition.Status != models.ExpeditionActive && expedition.Status != models.ExpeditionPaused {
+		return fmt.Errorf("покинуть можно только активную экспедицию")
+	}
+	if err := e.DB.ArchiveActiveQuestsByExpedition(e.Character.ID, expeditionID); err != nil {
+		return err
+	}
+	return e.DB.UpdateExpeditionStatus(e.Character.ID, expeditionID, models.ExpeditionAbandoned)
+}
+
+// ExtendDeadline pushes the deadline back by up to MaxExpeditionExtensionDays.
+// Each run may be extended MaxExpeditionExtensions times; every extension
+// reduces the final payout by ExpeditionExtensionPenalty.
+func (e *Engine) ExtendDeadline(expeditionID int64, days int) (*time.Time, error) {
+	if days <= 0 || days > models.MaxExpeditionExtensionDays {
+		return nil, fmt.Errorf("продление: от 1 до %d дней", models.MaxExpeditionExtensionDays)
+	}
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
+	if err != nil {
+		return nil, err
+	}
+	if expedition.Status != models.ExpeditionActive && expedition.Status != models.ExpeditionPaused {
+		return nil, fmt.Errorf("продлить можно только активную экспедицию")
+	}
+	if expedition.Deadline == nil {
+		return nil, fmt.Errorf("у экспедиции нет дедлайна")
+	}
+	if expedition.Extensions >= models.MaxExpeditionExtensions {
+		return nil, fmt.Errorf("лимит продлений исчерпан")
+	}
+
+	runID := expedition.RunID
+	if runID == 0 {
+		if runID, err = e.DB.StartExpeditionRun(e.Character.ID, expeditionID); err != nil {
+			return nil, err
+		}
+	}
+	base := *expedition.Deadline
+	if now := time.Now(); base.Before(now) {
+		base = now
+	}
+	deadline := base.AddDate(0, 0, days)
+	if err := e.DB.ExtendExpeditionRunDeadline(runID, deadline); err != nil {
+		return nil, err
+	}
+	return &deadline, nil
+}
+
 func (e *Engine) createExpeditionQuest(expeditionID int64, task models.ExpeditionTask) error {
 	expID := expeditionID
 	taskID := task.ID
@@ -152,14 +246,14 @@ func (e *Engine) createExpeditionQuest(expeditionID int64, task models.Expeditio
 
 func (e *Engine) resolveExpeditionTaskForQuest(q models.Quest) (*models.ExpeditionTask, error) {
 	if q.ExpeditionTaskID != nil {
-		task, err := e.DB.GetExpeditionTaskByID(*q.ExpeditionTaskID)
+		task, err := e.DB.GetExpeditionTaskByID(e.Character.ID, *q.ExpeditionTaskID)
 		if err == nil {
 			if task.ExpeditionID == *q.ExpeditionID {
 				return task, nil
 			}
 		}
 	}
-	return e.DB.FindNextIncompleteExpeditionTaskByTitle(*q.ExpeditionID, q.Title)
+	return e.DB.FindNextIncompleteExpeditionTaskByTitle(e.Character.ID, *q.ExpeditionID, q.Title)
 }
 
 func (e *Engine) AdvanceExpeditionByQuest(q models.Quest) (*models.Expedition, bool, error) {
@@ -167,7 +261,7 @@ func (e *Engine) AdvanceExpeditionByQuest(q models.Quest) (*models.Expedition, b
 		return nil, false, nil
 	}
 
-	expedition, err := e.DB.GetExpeditionByID(*q.ExpeditionID)
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, *q.ExpeditionID)
 	if err != nil {
 		return nil, false, err
 	}
@@ -183,10 +277,21 @@ func (e *Engine) AdvanceExpeditionByQuest(q models.Quest) (*models.Expedition, b
 		return expedition, false, nil
 	}
 
-	updatedTask, err := e.DB.IncrementExpeditionTaskProgress(task.ID, 1)
+	updatedTask, err := e.DB.IncrementExpeditionTaskProgress(e.Character.ID, task.ID, 1)
 	if err != nil {
 		return expedition, false, err
 	}
+	if updatedTask.ProgressCurrent > task.ProgressCurrent {
+		if err := e.DB.InsertExpeditionTaskLog(&models.ExpeditionTaskLogEntry{
+			CharID:       e.Character.ID,
+			ExpeditionID: *q.ExpeditionID,
+			TaskID:       task.ID,
+			Quantity:     updatedTask.ProgressCurrent - task.ProgressCurrent,
+			EXPAwarded:   q.Exp,
+		}); err != nil {
+			return expedition, false, err
+		}
+	}
 	if !updatedTask.IsCompleted {
 		if err := e.createExpeditionQuest(*q.ExpeditionID, *updatedTask); err != nil {
 			return expedition, false, err
@@ -198,20 +303,113 @@ func (e *Engine) AdvanceExpeditionByQuest(q models.Quest) (*models.Expedition, b
 		return expedition, false, err
 	}
 	if !done {
-		expedition, _ = e.DB.GetExpeditionByID(*q.ExpeditionID)
+		expedition, _ = e.DB.GetExpeditionByID(e.Character.ID, *q.ExpeditionID)
 		return expedition, false, nil
 	}
 
 	if err := e.CompleteExpedition(*q.ExpeditionID); err != nil {
 		return expedition, false, err
 	}
-	expedition, _ = e.DB.GetExpeditionByID(*q.ExpeditionID)
+	expedition, _ = e.DB.GetExpeditionByID(e.Character.ID, *q.ExpeditionID)
 	return expedition, true, nil
 }
 
+// TaskLogResult describes the outcome of logging a quantity against an expedition task.
+type TaskLogResult struct {
+	Task                *models.ExpeditionTask
+	Applied             int
+	EXPAwarded          int
+	LeveledUp           bool
+	OldLevel            int
+	NewLevel            int
+	ExpeditionCompleted bool
+	ExpeditionName      string
+}
+
+// LogExpeditionTaskQuantity adds quantity units of progress to a task and awards
+// the task's RewardEXP for every unit applied. Quantities beyond the target are clamped.
+func (e *Engine) LogExpeditionTaskQuantity(taskID int64, quantity int) (*TaskLogResult, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("количество должно быть больше нуля")
+	}
+
+	task, err := e.DB.GetExpeditionTaskByID(e.Character.ID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.IsCompleted {
+		return nil, fmt.Errorf("задача уже выполнена")
+	}
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, task.ExpeditionID)
+	if err != nil {
+		return nil, err
+	}
+	if expedition.Status != models.ExpeditionActive {
+		return nil, fmt.Errorf("экспедиция не активна")
+	}
+
+	progress := min(task.ProgressCurrent+quantity, task.ProgressTarget)
+	perUnit := task.RewardEXP
+	if perUnit <= 0 {
+		perUnit = models.DefaultTaskRewardEXP
+	}
+	result := &TaskLogResult{
+		Applied:        progress - task.ProgressCurrent,
+		ExpeditionName: expedition.Name,
+	}
+	result.EXPAwarded = result.Applied * perUnit
+
+	update := database.ExpeditionTaskProgress{
+		Entry: models.ExpeditionTaskLogEntry{
+			CharID:       e.Character.ID,
+			ExpeditionID: task.ExpeditionID,
+			TaskID:       task.ID,
+			Quantity:     result.Applied,
+			EXPAwarded:   result.EXPAwarded,
+		},
+		Progress:  progress,
+		Completed: progress >= task.ProgressTarget,
+	}
+	if result.EXPAwarded > 0 {
+		stats, err := e.GetStatLevels()
+		if err != nil {
+			return nil, err
+		}
+		for i := range stats {
+			if stats[i].StatType != task.TargetStat {
+				continue
+			}
+			result.OldLevel = stats[i].Level
+			applyEXPToStat(&stats[i], result.EXPAwarded)
+			result.NewLevel = stats[i].Level
+			result.LeveledUp = result.NewLevel > result.OldLevel
+			update.Stat = &stats[i]
+			break
+		}
+	}
+	if err := e.DB.LogExpeditionTaskProgress(&update); err != nil {
+		return nil, err
+	}
+	if result.Task, err = e.DB.GetExpeditionTaskByID(e.Character.ID, task.ID); err != nil {
+		return nil, err
+	}
+
+	done, err := e.CheckExpeditionCompletion(task.ExpeditionID)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		if err := e.CompleteExpedition(task.ExpeditionID); err != nil {
+			return nil, err
+		}
+		result.ExpeditionCompleted = true
+	}
+	return result, nil
+}
+
 // CheckExpeditionCompletion returns true when all expedition tasks are complete.
 func (e *Engine) CheckExpeditionCompletion(expeditionID int64) (bool, error) {
-	tasks, err := e.DB.GetExpeditionTasks(expeditionID)
+	tasks, err := e.DB.GetExpeditionTasks(e.Character.ID, expeditionID)
 	if err != nil {
 		return false, err
 	}
@@ -228,7 +426,7 @@ func (e *Engine) CheckExpeditionCompletion(expeditionID int64) (bool, error) {
 
 // CompleteExpedition finalizes an expedition, applies rewards and stores completion.
 func (e *Engine) CompleteExpedition(expeditionID int64) error {
-	expedition, err := e.DB.GetExpeditionByID(expeditionID)
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
 	if err != nil {
 		return err
 	}
@@ -247,47 +445,109 @@ func (e *Engine) CompleteExpedition(expeditionID int64) error {
 		return nil
 	}
 
+	return e.payExpeditionTier(expedition, models.TierGold)
+}
+
+// resolveExpiredExpedition closes an expedition whose deadline passed. If the
+// completed share of tasks reaches one of its tiers, the run is completed with
+// a partial payout; otherwise it fails. Remaining quests are failed either way.
+func (e *Engine) resolveExpiredExpedition(expedition *models.Expedition) error {
+	if err := e.DB.FailActiveQuestsByExpedition(e.Character.ID, expedition.ID); err != nil {
+		return err
+	}
+	_, _, percent, err := e.GetExpeditionProgress(expedition.ID)
+	if err != nil {
+		return err
+	}
+	tier := expedition.Tiers.TierFor(percent)
+	if percent >= 100 {
+		tier = models.TierGold
+	}
+	if tier == models.TierNone {
+		return e.DB.UpdateExpeditionStatus(e.Character.ID, expedition.ID, models.ExpeditionFailed)
+	}
+	return e.payExpeditionTier(expedition, tier)
+}
+
+// payExpeditionTier applies the tier's share of RewardEXP/RewardStats and
+// records the completion. Non-EXP rewards are granted for gold only.
+func (e *Engine) payExpeditionTier(expedition *models.Expedition, tier models.ExpeditionTier) error {
 	stats, err := e.GetStatLevels()
 	if err != nil {
 		return err
 	}
 
-	if expedition.RewardEXP > 0 {
+	earned := 0
+	fraction := tier.PayoutFraction() * extensionPayoutFactor(expedition.Extensions)
+	if rewardEXP := int(float64(expedition.RewardEXP) * fraction); rewardEXP > 0 {
 		for i := range stats {
-			applyEXPToStat(&stats[i], expedition.RewardEXP)
+			applyEXPToStat(&stats[i], rewardEXP)
 		}
+		earned += rewardEXP * len(stats)
 	}
 
 	for statType, exp := range expedition.RewardStats {
+		exp = int(float64(exp) * fraction)
 		if exp <= 0 {
 			continue
 		}
 		for i := range stats {
 			if stats[i].StatType == statType {
 				applyEXPToStat(&stats[i], exp)
+				earned += exp
 				break
 			}
 		}
 	}
 
-	for i := range stats {
-		if err := e.DB.UpdateStatLevel(&stats[i]); err != nil {
+	var rewards []models.Reward
+	var drop *models.InventoryItem
+	if tier == models.TierGold {
+		rewards = ExpeditionRewards(expedition)
+		// Seeded by the run so a retried completion picks the same item.
+		if drop, err = e.pickDrop(expedition.Name, expedition.RunID); err != nil {
 			return err
 		}
 	}
-
-	if err := e.DB.UpdateExpeditionStatus(expeditionID, models.ExpeditionCompleted); err != nil {
+	if err := e.DB.CompleteExpedition(database.ExpeditionCompletion{
+		CharID:            e.Character.ID,
+		ExpeditionID:      expedition.ID,
+		Stats:             stats,
+		Rewards:           rewards,
+		Tier:              tier,
+		EXPEarned:         earned,
+		AttemptReserveCap: e.attemptReserveCap(),
+		Drop:              drop,
+	}); err != nil {
 		return err
 	}
-	if err := e.DB.CompleteExpedition(e.Character.ID, expeditionID); err != nil {
-		return err
+	if attempts, err := e.DB.GetAttempts(e.Character.ID); err == nil {
+		e.Character.Attempts = attempts
 	}
 	return e.UnlockAchievement(AchievementFirstExpedition)
 }
 
+func extensionPayoutFactor(extensions int) float64 {
+	factor := 1.0 - float64(extensions)*models.ExpeditionExtensionPenalty
+	if factor < 0 {
+		return 0
+	}
+	return factor
+}
+
+// ExpeditionRewards lists every non-EXP reward of an expedition: its own
+// rewards followed by the rewards of each task.
+func ExpeditionRewards(expedition *models.Expedition) []models.Reward {
+	rewards := append([]models.Reward(nil), expedition.Rewards...)
+	for _, task := range expedition.Tasks {
+		rewards = append(rewards, task.Rewards...)
+	}
+	return rewards
+}
+
 // GetExpeditionProgress returns completed task count, total task count and completion percentage.
 func (e *Engine) GetExpeditionProgress(expeditionID int64) (int, int, float64, error) {
-	tasks, err := e.DB.GetExpeditionTasks(expeditionID)
+	tasks, err := e.DB.GetExpeditionTasks(e.Character.ID, expeditionID)
 	if err != nil {
 		return 0, 0, 0, err
 	}
@@ -0,0 +1,151 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Expedition burn-down and deadline forecast
+// ============================================================
+
+// forecastPaceWindowDays is how many recent days the projected pace is averaged over.
+const forecastPaceWindowDays = 7
+
+// ExpeditionForecast summarises progress of the current run against its deadline.
+type ExpeditionForecast struct {
+	UnitsDone  int
+	UnitsTotal int
+	// Daily holds units logged per day since the run started, oldest first.
+	Daily []models.DailyProgress
+	// RecentPace is units per day averaged over the recent window.
+	RecentPace float64
+	// RequiredPace is units per day needed to finish by the deadline; 0 without a deadline.
+	RequiredPace float64
+	// DaysLeft is the number of days until the deadline, rounded up; 0 without a deadline.
+	DaysLeft int
+	// ProjectedFinish is nil when nothing remains or there is no pace to project from.
+	ProjectedFinish *time.Time
+	AtRisk          bool
+}
+
+// UnitsLeft returns how many units remain until the expedition is done.
+func (f ExpeditionForecast) UnitsLeft() int {
+	if f.UnitsDone >= f.UnitsTotal {
+		return 0
+	}
+	return f.UnitsTotal - f.UnitsDone
+}
+
+// GetExpeditionForecast builds the burn-down and forecast of the character's current run.
+func (e *Engine) GetExpeditionForecast(expeditionID int64) (*ExpeditionForecast, error) {
+	expedition, err := e.DB.GetExpeditionByID(e.Character.ID, expeditionID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := e.DB.GetExpeditionTasks(e.Character.ID, expeditionID)
+	if err != nil {
+		return nil, err
+	}
+	expedition.Tasks = tasks
+
+	now := time.Now()
+	since := expeditionRunStart(expedition)
+	daily, err := e.DB.GetExpeditionDailyProgress(e.Character.ID, expeditionID, since)
+	if err != nil {
+		return nil, err
+	}
+	forecast := ForecastExpedition(expedition, daily, now)
+	return &forecast, nil
+}
+
+// GetExpeditionsAtRisk returns active expeditions whose forecast misses the deadline.
+func (e *Engine) GetExpeditionsAtRisk() ([]models.Expedition, error) {
+	expeditions, err := e.DB.GetAllExpeditions(e.Character.ID)
+	if err != nil {
+		return nil, err
+	}
+	var atRisk []models.Expedition
+	for _, exp := range expeditions {
+		if exp.Status != models.ExpeditionActive || exp.Deadline == nil {
+			continue
+		}
+		forecast, err := e.GetExpeditionForecast(exp.ID)
+		if err != nil {
+			return nil, err
+		}
+		if forecast.AtRisk {
+			atRisk = append(atRisk, exp)
+		}
+	}
+	return atRisk, nil
+}
+
+// ForecastExpedition computes the forecast from tasks and daily progress as of now.
+func ForecastExpedition(expedition *models.Expedition, daily []models.DailyProgress, now time.Time) ExpeditionForecast {
+	f := ExpeditionForecast{Daily: daily}
+	for _, task := range expedition.Tasks {
+		target := task.ProgressTarget
+		if target < 1 {
+			target = 1
+		}
+		done := task.ProgressCurrent
+		if task.IsCompleted || done > target {
+			done = target
+		}
+		f.UnitsTotal += target
+		f.UnitsDone += done
+	}
+
+	started := expeditionRunStart(expedition)
+	elapsedDays := now.Sub(started).Hours() / 24
+	windowDays := math.Min(forecastPaceWindowDays, math.Max(elapsedDays, 1))
+	windowStart := now.Add(-time.Duration(windowDays * 24 * float64(time.Hour)))
+	recent := 0
+	for _, day := range daily {
+		if !day.Date.Before(startOfDay(windowStart)) {
+			recent += day.Units
+		}
+	}
+	f.RecentPace = float64(recent) / windowDays
+
+	left := f.UnitsLeft()
+	if left > 0 && f.RecentPace > 0 {
+		days := float64(left) / f.RecentPace
+		finish := now.Add(time.Duration(days * 24 * float64(time.Hour)))
+		f.ProjectedFinish = &finish
+	}
+
+	if expedition.Deadline == nil || left == 0 {
+		return f
+	}
+	remaining := expedition.Deadline.Sub(now).Hours() / 24
+	if remaining > 0 {
+		f.DaysLeft = int(math.Ceil(remaining))
+		f.RequiredPace = float64(left) / math.Max(remaining, 1)
+	}
+	switch {
+	case remaining <= 1:
+		f.AtRisk = true
+	case f.ProjectedFinish != nil:
+		f.AtRisk = f.ProjectedFinish.After(*expedition.Deadline)
+	default:
+		// No recent progress at all: only flag once the run had a day to get going.
+		f.AtRisk = elapsedDays >= 1
+	}
+	return f
+}
+
+func expeditionRunStart(expedition *models.Expedition) time.Time {
+	if expedition.StartedAt != nil {
+		return *expedition.StartedAt
+	}
+	return expedition.CreatedAt
+}
+
+func startOfDay(t time.Time) time.Time {
+	local := t.Local()
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,461 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Expedition import / export
+// ============================================================
+
+// ExpeditionDocument is the JSON representation of an expedition used for
+// import and export. Export fills in status and progress; import accepts them.
+type ExpeditionDocument struct {
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description,omitempty"`
+	Deadline     string                   `json:"deadline,omitempty"`
+	RewardEXP    int                      `json:"reward_exp"`
+	RewardStats  map[string]int           `json:"reward_stats,omitempty"`
+	IsRepeatable bool                     `json:"is_repeatable,omitempty"`
+	Status       string                   `json:"status,omitempty"`
+	Rewards      []models.Reward          `json:"rewards,omitempty"`
+	Tiers        *models.ExpeditionTiers  `json:"tiers,omitempty"`
+	Tasks        []ExpeditionTaskDocument `json:"tasks"`
+}
+
+// ExpeditionTaskDocument is the JSON representation of an expedition task.
+// Several aliases (name/desc/repeats/...) are accepted on import.
+type ExpeditionTaskDocument struct {
+	Name            string          `json:"name,omitempty"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description,omitempty"`
+	Desc            string          `json:"desc,omitempty"`
+	IsCompleted     bool            `json:"is_completed,omitempty"`
+	ProgressCurrent int             `json:"progress_current,omitempty"`
+	ProgressTarget  int             `json:"progress_target"`
+	RepeatCount     int             `json:"repeat_count,omitempty"`
+	Repeats         int             `json:"repeats,omitempty"`
+	Times           int             `json:"times,omitempty"`
+	Count           int             `json:"count,omitempty"`
+	RewardEXP       int             `json:"reward_exp"`
+	TargetStat      string          `json:"target_stat"`
+	Stat            string          `json:"stat,omitempty"`
+	Rewards         []models.Reward `json:"rewards,omitempty"`
+}
+
+// ImportFieldError points at a single invalid field of an imported document.
+type ImportFieldError struct {
+	Path    string
+	Message string
+}
+
+func (e ImportFieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ImportErrors collects every field error found in one document.
+type ImportErrors []ImportFieldError
+
+func (errs ImportErrors) Error() string {
+	parts := make([]string, 0, len(errs))
+	for _, e := range errs {
+		parts = append(parts, e.Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ExpeditionImportResult summarizes an import run.
+type ExpeditionImportResult struct {
+	Created []models.Expedition
+	Errors  []error
+}
+
+// ParseExpeditionDocuments accepts a single object, an array or {"expeditions":[...]}.
+func ParseExpeditionDocuments(raw []byte) ([]ExpeditionDocument, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" {
+		return nil, fmt.Errorf("JSON пуст")
+	}
+
+	var list []ExpeditionDocument
+	if err := json.Unmarshal([]byte(trimmed), &list); err == nil {
+		if len(list) == 0 {
+			return nil, fmt.Errorf("массив экспедиций пуст")
+		}
+		return list, nil
+	}
+
+	var wrapped struct {
+		Expeditions []ExpeditionDocument `json:"expeditions"`
+	}
+	if err := json.Unmarshal([]byte(trimmed), &wrapped); err == nil && len(wrapped.Expeditions) > 0 {
+		return wrapped.Expeditions, nil
+	}
+
+	var single ExpeditionDocument
+	if err := json.Unmarshal([]byte(trimmed), &single); err == nil {
+		if strings.TrimSpace(single.Name) == "" && len(single.Tasks) == 0 {
+			return nil, fmt.Errorf("объект экспедиции не содержит name/tasks")
+		}
+		return []ExpeditionDocument{single}, nil
+	}
+
+	return nil, fmt.Errorf("невалидный JSON: ожидается объект экспедиции, массив или {\"expeditions\":[...]}")
+}
+
+// ToModel validates the document and converts it into an expedition.
+// All invalid fields are reported at once as ImportErrors.
+func (doc ExpeditionDocument) ToModel() (*models.Expedition, error) {
+	var errs ImportErrors
+	fail := func(path, format string, args ...any) {
+		errs = append(errs, ImportFieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	name := strings.TrimSpace(doc.Name)
+	if name == "" {
+		fail("name", "обязательное поле")
+	}
+	if len(doc.Tasks) == 0 {
+		fail("tasks", "требуется минимум 1 задача")
+	}
+
+	var deadline *time.Time
+	if strings.TrimSpace(doc.Deadline) != "" {
+		parsed, err := parseExpeditionDeadline(doc.Deadline)
+		if err != nil {
+			fail("deadline", "%v", err)
+		} else {
+			deadline = &parsed
+		}
+	}
+
+	if doc.RewardEXP < 0 {
+		fail("reward_exp", "не может быть отрицательным (%d)", doc.RewardEXP)
+	}
+
+	rewardStats := make(map[models.StatType]int)
+	keys := make([]string, 0, len(doc.RewardStats))
+	for key := range doc.RewardStats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := doc.RewardStats[key]
+		stat, ok := parseStatTypeAlias(key)
+		if !ok {
+			fail("reward_stats."+key, "неизвестный стат")
+			continue
+		}
+		if value < 0 {
+			fail("reward_stats."+key, "не может быть отрицательным (%d)", value)
+			continue
+		}
+		if value > 0 {
+			rewardStats[stat] += value
+		}
+	}
+
+	status := models.ExpeditionActive
+	if raw := strings.TrimSpace(doc.Status); raw != "" {
+		switch models.ExpeditionStatus(strings.ToLower(raw)) {
+		case models.ExpeditionActive, models.ExpeditionCompleted, models.ExpeditionFailed:
+			status = models.ExpeditionStatus(strings.ToLower(raw))
+		default:
+			fail("status", "неизвестный статус %q", raw)
+		}
+	}
+
+	rewards, rewardErrs := validateRewards("rewards", doc.Rewards)
+	errs = append(errs, rewardErrs...)
+
+	var tiers models.ExpeditionTiers
+	if doc.Tiers != nil {
+		tiers = *doc.Tiers
+		errs = append(errs, validateTiers(tiers)...)
+	}
+
+	tasks := make([]models.ExpeditionTask, 0, len(doc.Tasks))
+	for i, task := range doc.Tasks {
+		parsed, taskErrs := task.toModel(fmt.Sprintf("tasks[%d]", i))
+		errs = append(errs, taskErrs...)
+		tasks = append(tasks, parsed)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &models.Expedition{
+		Name:         name,
+		Description:  strings.TrimSpace(doc.Description),
+		Deadline:     deadline,
+		RewardEXP:    doc.RewardEXP,
+		RewardStats:  rewardStats,
+		IsRepeatable: doc.IsRepeatable,
+		Status:       status,
+		Tasks:        tasks,
+		Rewards:      rewards,
+		Tiers:        tiers,
+	}, nil
+}
+
+func (doc ExpeditionTaskDocument) toModel(path string) (models.ExpeditionTask, ImportErrors) {
+	var errs ImportErrors
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, ImportFieldError{Path: path + "." + field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	title := strings.TrimSpace(doc.Title)
+	if title == "" {
+		title = strings.TrimSpace(doc.Name)
+	}
+	if title == "" {
+		fail("title", "обязательное поле")
+	}
+
+	if doc.ProgressTarget < 0 {
+		fail("progress_target", "не может быть отрицательным (%d)", doc.ProgressTarget)
+	}
+	target := doc.ProgressTarget
+	if target <= 0 {
+		target = firstPositive(doc.RepeatCount, doc.Repeats, doc.Times, doc.Count)
+	}
+	if target <= 0 {
+		target = 1
+	}
+
+	current := doc.ProgressCurrent
+	if current < 0 {
+		fail("progress_current", "не может быть отрицательным (%d)", current)
+		current = 0
+	}
+	if current > target {
+		current = target
+	}
+	completed := doc.IsCompleted || current >= target
+	if completed {
+		current = target
+	}
+
+	if doc.RewardEXP < 0 {
+		fail("reward_exp", "не может быть отрицательным (%d)", doc.RewardEXP)
+	}
+	rewardExp := doc.RewardEXP
+	if rewardExp <= 0 {
+		rewardExp = models.DefaultTaskRewardEXP
+	}
+
+	stat := models.StatStrength
+	statField, statRaw := "target_stat", strings.TrimSpace(doc.TargetStat)
+	if statRaw == "" {
+		statField, statRaw = "stat", strings.TrimSpace(doc.Stat)
+	}
+	if statRaw != "" {
+		parsed, ok := parseStatTypeAlias(statRaw)
+		if !ok {
+			fail(statField, "неизвестный стат %q (ожидается STR/AGI/INT/STA)", statRaw)
+		} else {
+			stat = parsed
+		}
+	}
+
+	description := strings.TrimSpace(doc.Description)
+	if description == "" {
+		description = strings.TrimSpace(doc.Desc)
+	}
+
+	rewards, rewardErrs := validateRewards(path+".rewards", doc.Rewards)
+	errs = append(errs, rewardErrs...)
+
+	return models.ExpeditionTask{
+		Title:           title,
+		Description:     description,
+		IsCompleted:     completed,
+		ProgressCurrent: current,
+		ProgressTarget:  target,
+		RewardEXP:       rewardExp,
+		TargetStat:      stat,
+		Rewards:         rewards,
+	}, errs
+}
+
+func validateRewards(path string, rewards []models.Reward) ([]models.Reward, ImportErrors) {
+	var errs ImportErrors
+	result := make([]models.Reward, 0, len(rewards))
+	for i, r := range rewards {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		r.Kind = models.RewardKind(strings.ToLower(strings.TrimSpace(string(r.Kind))))
+		r.Value = strings.TrimSpace(r.Value)
+		switch r.Kind {
+		case models.RewardTitle, models.RewardBadge, models.RewardItem:
+			if r.Value == "" {
+				errs = append(errs, ImportFieldError{Path: itemPath + ".value", Message: "обязательное поле"})
+				continue
+			}
+		case models.RewardAttempts:
+			if r.Amount <= 0 {
+				errs = append(errs, ImportFieldError{Path: itemPath + ".amount", Message: fmt.Sprintf("должно быть больше нуля (%d)", r.Amount)})
+				continue
+			}
+		default:
+			errs = append(errs, ImportFieldError{Path: itemPath + ".kind", Message: fmt.Sprintf("неизвестный тип награды %q (ожидается title/badge/attempts/item)", r.Kind)})
+			continue
+		}
+		result = append(result, r)
+	}
+	if len(result) == 0 {
+		result = nil
+	}
+	return result, errs
+}
+
+func validateTiers(tiers models.ExpeditionTiers) ImportErrors {
+	var errs ImportErrors
+	prev, prevName := 0, ""
+	for _, tier := range []struct {
+		name  string
+		value int
+	}{{"bronze", tiers.Bronze}, {"silver", tiers.Silver}, {"gold", tiers.Gold}} {
+		if tier.value < 0 || tier.value > 100 {
+			errs = append(errs, ImportFieldError{Path: "tiers." + tier.name, Message: fmt.Sprintf("ожидается 0-100%% (%d)", tier.value)})
+			continue
+		}
+		if tier.value == 0 {
+			continue
+		}
+		if tier.value <= prev {
+			errs = append(errs, ImportFieldError{Path: "tiers." + tier.name, Message: fmt.Sprintf("должен быть выше %s (%d <= %d)", prevName, tier.value, prev)})
+			continue
+		}
+		prev, prevName = tier.value, tier.name
+	}
+	return errs
+}
+
+// ExpeditionToDocument converts a stored expedition, including progress, into
+// the import format.
+func ExpeditionToDocument(ex models.Expedition) ExpeditionDocument {
+	doc := ExpeditionDocument{
+		Name:         ex.Name,
+		Description:  ex.Description,
+		RewardEXP:    ex.RewardEXP,
+		IsRepeatable: ex.IsRepeatable,
+		Status:       string(ex.Status),
+		Rewards:      ex.Rewards,
+		Tasks:        make([]ExpeditionTaskDocument, 0, len(ex.Tasks)),
+	}
+	if ex.Deadline != nil {
+		doc.Deadline = ex.Deadline.Format(time.RFC3339)
+	}
+	if ex.Tiers.Enabled() {
+		tiers := ex.Tiers
+		doc.Tiers = &tiers
+	}
+	if len(ex.RewardStats) > 0 {
+		doc.RewardStats = make(map[string]int, len(ex.RewardStats))
+		for stat, value := range ex.RewardStats {
+			doc.RewardStats[string(stat)] = value
+		}
+	}
+	for _, task := range ex.Tasks {
+		doc.Tasks = append(doc.Tasks, ExpeditionTaskDocument{
+			Title:           task.Title,
+			Description:     task.Description,
+			IsCompleted:     task.IsCompleted,
+			ProgressCurrent: task.ProgressCurrent,
+			ProgressTarget:  task.ProgressTarget,
+			RewardEXP:       task.RewardEXP,
+			TargetStat:      string(task.TargetStat),
+			Rewards:         task.Rewards,
+		})
+	}
+	return doc
+}
+
+// ImportExpeditionsJSON validates every document and creates the valid ones.
+// Invalid documents are reported in the result without aborting the import.
+func (e *Engine) ImportExpeditionsJSON(raw []byte) (*ExpeditionImportResult, error) {
+	docs, err := ParseExpeditionDocuments(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExpeditionImportResult{}
+	for i, doc := range docs {
+		model, err := doc.ToModel()
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("#%d: %w", i+1, err))
+			continue
+		}
+		if err := e.CreateExpedition(model); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("#%d (%s): %w", i+1, model.Name, err))
+			continue
+		}
+		result.Created = append(result.Created, *model)
+	}
+	return result, nil
+}
+
+// ExportExpeditionsJSON returns all expeditions wrapped as {"expeditions":[...]}.
+func (e *Engine) ExportExpeditionsJSON() ([]byte, error) {
+	expeditions, err := e.DB.GetAllExpeditions(e.Character.ID)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := struct {
+		Expeditions []ExpeditionDocument `json:"expeditions"`
+	}{Expeditions: make([]ExpeditionDocument, 0, len(expeditions))}
+	for _, ex := range expeditions {
+		wrapped.Expeditions = append(wrapped.Expeditions, ExpeditionToDocument(ex))
+	}
+	return json.MarshalIndent(wrapped, "", "  ")
+}
+
+func firstPositive(values ...int) int {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func parseStatTypeAlias(raw string) (models.StatType, bool) {
+	switch strings.ToUpper(strings.TrimSpace(raw)) {
+	case "STR", "STRENGTH", "СИЛА":
+		return models.StatStrength, true
+	case "AGI", "AGILITY", "ЛОВКОСТЬ":
+		return models.StatAgility, true
+	case "INT", "INTELLECT", "ИНТЕЛЛЕКТ":
+		return models.StatIntellect, true
+	case "STA", "ENDURANCE", "ВЫНОСЛИВОСТЬ":
+		return models.StatEndurance, true
+	default:
+		return "", false
+	}
+}
+
+func parseExpeditionDeadline(raw string) (time.Time, error) {
+	value := strings.TrimSpace(raw)
+	layouts := []string{
+		time.RFC3339,
+		"2006-01-02",
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+	}
+	for _, layout := range layouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			if layout == "2006-01-02" {
+				return time.Date(ts.Year(), ts.Month(), ts.Day(), 23, 59, 59, 0, time.Local), nil
+			}
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("ожидался RFC3339 или YYYY-MM-DD")
+}
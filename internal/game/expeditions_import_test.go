@@ -0,0 +1,92 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"solo-leveling/internal/models"
+)
+
+func TestExpeditionDocumentFieldErrors(t *testing.T) {
+	doc := ExpeditionDocument{
+		Deadline:    "завтра",
+		RewardStats: map[string]int{"luck": 5},
+		Tasks: []ExpeditionTaskDocument{
+			{Title: "ok", TargetStat: "INT"},
+			{TargetStat: "charisma", ProgressCurrent: -1},
+		},
+	}
+
+	_, err := doc.ToModel()
+	var fieldErrs ImportErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected ImportErrors, got %v", err)
+	}
+
+	want := map[string]bool{
+		"name":                      false,
+		"deadline":                  false,
+		"reward_stats.luck":         false,
+		"tasks[1].title":            false,
+		"tasks[1].target_stat":      false,
+		"tasks[1].progress_current": false,
+	}
+	for _, fe := range fieldErrs {
+		if _, ok := want[fe.Path]; !ok {
+			t.Fatalf("unexpected field error %q", fe.Error())
+		}
+		want[fe.Path] = true
+	}
+	for path, seen := range want {
+		if !seen {
+			t.Fatalf("missing field error for %s (got %v)", path, err)
+		}
+	}
+}
+
+func TestExpeditionImportExportRoundTrip(t *testing.T) {
+	e := newTestEngine(t)
+
+	raw := []byte(`{"expeditions":[
+		{"name":"Экспедиция: Книги","deadline":"2030-01-31","reward_exp":50,"reward_stats":{"INT":10},
+		 "tasks":[{"title":"Прочитать","repeat_count":5,"progress_current":2,"reward_exp":15,"target_stat":"INT"}]},
+		{"name":"","tasks":[]}
+	]}`)
+	result, err := e.ImportExpeditionsJSON(raw)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.Created) != 1 || len(result.Errors) != 1 {
+		t.Fatalf("expected 1 created / 1 error, got %d / %d", len(result.Created), len(result.Errors))
+	}
+
+	exported, err := e.ExportExpeditionsJSON()
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	docs, err := ParseExpeditionDocuments(exported)
+	if err != nil {
+		t.Fatalf("parse export: %v", err)
+	}
+
+	var found *ExpeditionDocument
+	for i := range docs {
+		if docs[i].Name == "Экспедиция: Книги" {
+			found = &docs[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("imported expedition missing from export")
+	}
+	model, err := found.ToModel()
+	if err != nil {
+		t.Fatalf("re-import exported document: %v", err)
+	}
+	task := model.Tasks[0]
+	if task.ProgressCurrent != 2 || task.ProgressTarget != 5 || task.TargetStat != models.StatIntellect {
+		t.Fatalf("progress not preserved: %+v", task)
+	}
+	if model.RewardStats[models.StatIntellect] != 10 || model.Deadline == nil {
+		t.Fatalf("rewards or deadline not preserved: %+v", model)
+	}
+}
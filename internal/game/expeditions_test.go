@@ -62,7 +62,7 @@ func TestExpeditionCompletionGrantsRewards(t *testing.T) {
 		}
 	}
 
-	afterExpedition, err := e.DB.GetExpeditionByID(expedition.ID)
+	afterExpedition, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
 	if err != nil {
 		t.Fatalf("get expedition after completion: %v", err)
 	}
@@ -140,7 +140,7 @@ func TestExpeditionDeadlineFailureNoRewards(t *testing.T) {
 		t.Fatalf("refresh expedition statuses: %v", err)
 	}
 
-	afterExpedition, err := e.DB.GetExpeditionByID(expedition.ID)
+	afterExpedition, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
 	if err != nil {
 		t.Fatalf("get expedition after refresh: %v", err)
 	}
@@ -177,3 +177,661 @@ func TestExpeditionDeadlineFailureNoRewards(t *testing.T) {
 		}
 	}
 }
+
+func TestExpeditionTaskQuantityLogging(t *testing.T) {
+	e := newTestEngine(t)
+
+	expedition := models.Expedition{
+		Name:   "Книжный марафон",
+		Status: models.ExpeditionActive,
+		Tasks: []models.ExpeditionTask{
+			{Title: "Прочитать 300 страниц", ProgressTarget: 300, RewardEXP: 2, TargetStat: models.StatIntellect},
+		},
+	}
+	if err := e.DB.InsertExpedition(&expedition); err != nil {
+		t.Fatalf("insert expedition: %v", err)
+	}
+	if _, err := e.StartExpedition(expedition.ID); err != nil {
+		t.Fatalf("start expedition: %v", err)
+	}
+	taskID := expedition.Tasks[0].ID
+
+	beforeStats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats before: %v", err)
+	}
+	before := statTotalsByType(beforeStats)
+
+	if _, err := e.LogExpeditionTaskQuantity(taskID, 0); err == nil {
+		t.Fatalf("expected error for zero quantity")
+	}
+
+	res, err := e.LogExpeditionTaskQuantity(taskID, 42)
+	if err != nil {
+		t.Fatalf("log quantity: %v", err)
+	}
+	if res.Applied != 42 || res.EXPAwarded != 84 {
+		t.Fatalf("expected 42 units / 84 EXP, got %d / %d", res.Applied, res.EXPAwarded)
+	}
+	if res.Task.ProgressCurrent != 42 || res.Task.IsCompleted {
+		t.Fatalf("unexpected task progress: %+v", res.Task)
+	}
+
+	res, err = e.LogExpeditionTaskQuantity(taskID, 1000)
+	if err != nil {
+		t.Fatalf("log overflow quantity: %v", err)
+	}
+	if res.Applied != 258 || !res.Task.IsCompleted || !res.ExpeditionCompleted {
+		t.Fatalf("expected clamped completion, got %+v", res)
+	}
+
+	afterStats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats after: %v", err)
+	}
+	after := statTotalsByType(afterStats)
+	if gained := after[models.StatIntellect] - before[models.StatIntellect]; gained != 600 {
+		t.Fatalf("expected 600 INT EXP from logged units, got %d", gained)
+	}
+
+	active, err := e.DB.GetExpeditionActiveQuests(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get expedition quests: %v", err)
+	}
+	if len(active) != 0 {
+		t.Fatalf("expected task quest to be closed, got %d active", len(active))
+	}
+
+	log, err := e.DB.GetExpeditionTaskLog(e.Character.ID, taskID)
+	if err != nil {
+		t.Fatalf("get task log: %v", err)
+	}
+	if len(log) != 2 || log[0].Quantity != 258 || log[1].Quantity != 42 {
+		t.Fatalf("unexpected task log: %+v", log)
+	}
+
+	if _, err := e.LogExpeditionTaskQuantity(taskID, 1); err == nil {
+		t.Fatalf("expected error for completed task")
+	}
+}
+
+func TestExpeditionTaskWithoutRewardUsesDefault(t *testing.T) {
+	e := newTestEngine(t)
+
+	expedition := models.Expedition{
+		Name:   "Без награды",
+		Status: models.ExpeditionActive,
+		Tasks:  []models.ExpeditionTask{{Title: "Шаги", ProgressTarget: 5, TargetStat: models.StatAgility}},
+	}
+	if err := e.DB.InsertExpedition(&expedition); err != nil {
+		t.Fatalf("insert expedition: %v", err)
+	}
+	if _, err := e.StartExpedition(expedition.ID); err != nil {
+		t.Fatalf("start expedition: %v", err)
+	}
+
+	res, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 2)
+	if err != nil {
+		t.Fatalf("log quantity: %v", err)
+	}
+	if want := 2 * models.DefaultTaskRewardEXP; res.EXPAwarded != want {
+		t.Fatalf("expected %d EXP from the default reward, got %d", want, res.EXPAwarded)
+	}
+
+	activity, err := e.DB.GetDailyActivityLast30(e.Character.ID)
+	if err != nil || len(activity) != 1 || activity[0].EXPEarned != res.EXPAwarded {
+		t.Fatalf("expected today's activity to count %d EXP, got %+v (%v)", res.EXPAwarded, activity, err)
+	}
+}
+
+func TestExpeditionProgressIsPerCharacterAndRun(t *testing.T) {
+	e := newTestEngine(t)
+
+	expedition := models.Expedition{
+		Name:         "Общая экспедиция",
+		IsRepeatable: true,
+		Status:       models.ExpeditionActive,
+		Tasks: []models.ExpeditionTask{
+			{Title: "Шаг", ProgressTarget: 3, RewardEXP: 5, TargetStat: models.StatAgility},
+		},
+	}
+	if err := e.DB.InsertExpedition(&expedition); err != nil {
+		t.Fatalf("insert expedition: %v", err)
+	}
+	taskID := expedition.Tasks[0].ID
+
+	other, err := e.DB.CreateCharacter("Second")
+	if err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	second := &Engine{DB: e.DB, Character: other}
+
+	if _, err := e.LogExpeditionTaskQuantity(taskID, 2); err != nil {
+		t.Fatalf("log first character: %v", err)
+	}
+	task, err := second.DB.GetExpeditionTaskByID(other.ID, taskID)
+	if err != nil {
+		t.Fatalf("get task for second character: %v", err)
+	}
+	if task.ProgressCurrent != 0 {
+		t.Fatalf("second character shares progress: %d", task.ProgressCurrent)
+	}
+
+	if _, err := e.LogExpeditionTaskQuantity(taskID, 1); err != nil {
+		t.Fatalf("finish first character: %v", err)
+	}
+	done, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get expedition: %v", err)
+	}
+	if done.Status != models.ExpeditionCompleted {
+		t.Fatalf("expected completed for first character, got %s", done.Status)
+	}
+	untouched, err := second.DB.GetExpeditionByID(other.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get expedition for second character: %v", err)
+	}
+	if untouched.Status != models.ExpeditionActive || untouched.RunID != 0 {
+		t.Fatalf("second character should have no run, got %s run=%d", untouched.Status, untouched.RunID)
+	}
+
+	firstRun := done.RunID
+	if _, err := e.StartExpedition(expedition.ID); err != nil {
+		t.Fatalf("repeat expedition: %v", err)
+	}
+	repeat, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get repeated expedition: %v", err)
+	}
+	if repeat.RunID == firstRun || repeat.Status != models.ExpeditionActive || repeat.Tasks[0].ProgressCurrent != 0 {
+		t.Fatalf("repeat should start a fresh run, got run=%d status=%s progress=%d",
+			repeat.RunID, repeat.Status, repeat.Tasks[0].ProgressCurrent)
+	}
+}
+
+func TestExpeditionCompletionGrantsRichRewards(t *testing.T) {
+	e := newTestEngine(t)
+
+	expedition := models.Expedition{
+		Name:   "Трофейная экспедиция",
+		Status: models.ExpeditionActive,
+		Rewards: []models.Reward{
+			{Kind: models.RewardTitle, Value: "Первопроходец"},
+			{Kind: models.RewardBadge, Value: "🧭"},
+			{Kind: models.RewardAttempts, Amount: 2},
+		},
+		Tasks: []models.ExpeditionTask{
+			{
+				Title: "Финал", ProgressTarget: 1, RewardEXP: 10, TargetStat: models.StatStrength,
+				Rewards: []models.Reward{{Kind: models.RewardItem, Value: "Рамка аватара"}},
+			},
+		},
+	}
+	if err := e.CreateExpedition(&expedition); err != nil {
+		t.Fatalf("create expedition: %v", err)
+	}
+	beforeAttempts, err := e.DB.GetAttempts(e.Character.ID)
+	if err != nil {
+		t.Fatalf("get attempts: %v", err)
+	}
+
+	if _, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 1); err != nil {
+		t.Fatalf("finish task: %v", err)
+	}
+
+	granted, err := e.DB.GetGrantedRewards(e.Character.ID, "")
+	if err != nil {
+		t.Fatalf("get granted rewards: %v", err)
+	}
+	if len(granted) != 4 {
+		t.Fatalf("expected 4 granted rewards, got %d", len(granted))
+	}
+	items, err := e.DB.GetGrantedRewards(e.Character.ID, models.RewardItem)
+	if err != nil {
+		t.Fatalf("get granted items: %v", err)
+	}
+	if len(items) != 1 || items[0].Reward.Value != "Рамка аватара" {
+		t.Fatalf("unexpected granted items: %+v", items)
+	}
+
+	titles, err := e.GetAllTitles()
+	if err != nil {
+		t.Fatalf("get titles: %v", err)
+	}
+	found := false
+	for _, title := range titles {
+		if title == "Первопроходец" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expedition title missing from title pool: %v", titles)
+	}
+
+	afterAttempts, err := e.DB.GetAttempts(e.Character.ID)
+	if err != nil {
+		t.Fatalf("get attempts after: %v", err)
+	}
+	if want := min(beforeAttempts+2, models.MaxAttempts); afterAttempts != want {
+		t.Fatalf("expected attempts %d, got %d", want, afterAttempts)
+	}
+}
+
+func TestExpeditionDeadlinePaysPartialTier(t *testing.T) {
+	e := newTestEngine(t)
+
+	deadline := time.Now().Add(-time.Hour)
+	expedition := models.Expedition{
+		Name:      "Почти успели",
+		Deadline:  &deadline,
+		RewardEXP: 100,
+		Rewards:   []models.Reward{{Kind: models.RewardTitle, Value: "Только за золото"}},
+		Tiers:     models.ExpeditionTiers{Bronze: 25, Silver: 50, Gold: 90},
+		Status:    models.ExpeditionActive,
+		Tasks: []models.ExpeditionTask{
+			{Title: "1", ProgressTarget: 1, RewardEXP: 1, TargetStat: models.StatStrength},
+			{Title: "2", ProgressTarget: 1, RewardEXP: 1, TargetStat: models.StatStrength},
+			{Title: "3", ProgressTarget: 1, RewardEXP: 1, TargetStat: models.StatStrength},
+			{Title: "4", ProgressTarget: 1, RewardEXP: 1, TargetStat: models.StatStrength},
+		},
+	}
+	if err := e.CreateExpedition(&expedition); err != nil {
+		t.Fatalf("create expedition: %v", err)
+	}
+	for _, task := range expedition.Tasks[:3] {
+		if _, err := e.DB.IncrementExpeditionTaskProgress(e.Character.ID, task.ID, 1); err != nil {
+			t.Fatalf("progress task: %v", err)
+		}
+	}
+
+	beforeStats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats before: %v", err)
+	}
+	before := statTotalsByType(beforeStats)
+
+	if err := e.RefreshExpeditionStatuses(true); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	after, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get expedition: %v", err)
+	}
+	if after.Status != models.ExpeditionCompleted {
+		t.Fatalf("expected partial completion, got %s", after.Status)
+	}
+
+	completed, err := e.DB.GetCompletedExpeditions(e.Character.ID)
+	if err != nil {
+		t.Fatalf("get completed: %v", err)
+	}
+	if len(completed) != 1 || completed[0].Tier != models.TierSilver {
+		t.Fatalf("expected one silver completion, got %+v", completed)
+	}
+
+	afterStats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats after: %v", err)
+	}
+	for statType, total := range statTotalsByType(afterStats) {
+		if gained := total - before[statType]; gained != 50 {
+			t.Fatalf("expected 50 %s EXP for silver, got %d", statType, gained)
+		}
+	}
+
+	granted, err := e.DB.GetGrantedRewards(e.Character.ID, "")
+	if err != nil {
+		t.Fatalf("get granted rewards: %v", err)
+	}
+	if len(granted) != 0 {
+		t.Fatalf("non-gold tier should not grant trophies, got %+v", granted)
+	}
+}
+
+func TestExpeditionPauseResumeAbandonExtend(t *testing.T) {
+	e := newTestEngine(t)
+
+	deadline := time.Now().Add(48 * time.Hour)
+	expedition := models.Expedition{
+		Name:      "Походная экспедиция",
+		Deadline:  &deadline,
+		RewardEXP: 100,
+		Status:    models.ExpeditionActive,
+		Tasks: []models.ExpeditionTask{
+			{Title: "Шаг", ProgressTarget: 2, RewardEXP: 5, TargetStat: models.StatAgility},
+		},
+	}
+	if err := e.CreateExpedition(&expedition); err != nil {
+		t.Fatalf("create expedition: %v", err)
+	}
+	if _, err := e.StartExpedition(expedition.ID); err != nil {
+		t.Fatalf("start expedition: %v", err)
+	}
+
+	if err := e.PauseExpedition(expedition.ID); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	active, err := e.DB.GetExpeditionActiveQuests(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get active quests: %v", err)
+	}
+	if len(active) != 0 {
+		t.Fatalf("expected quests archived on pause, got %d", len(active))
+	}
+	if _, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 1); err == nil {
+		t.Fatalf("expected logging to be blocked while paused")
+	}
+
+	// Pretend the pause started a day ago.
+	paused, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get paused expedition: %v", err)
+	}
+	if err := e.DB.PauseExpeditionRun(paused.RunID, time.Now().Add(-24*time.Hour)); err != nil {
+		t.Fatalf("backdate pause: %v", err)
+	}
+	if spawned, err := e.ResumeExpedition(expedition.ID); err != nil || spawned != 1 {
+		t.Fatalf("resume: spawned=%d err=%v", spawned, err)
+	}
+	resumed, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get resumed expedition: %v", err)
+	}
+	if shift := resumed.Deadline.Sub(deadline); shift < 23*time.Hour || shift > 25*time.Hour {
+		t.Fatalf("expected deadline shifted by ~24h, got %v", shift)
+	}
+
+	if _, err := e.ExtendDeadline(expedition.ID, 3); err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	if _, err := e.ExtendDeadline(expedition.ID, 3); err == nil {
+		t.Fatalf("expected second extension to be rejected")
+	}
+
+	if err := e.AbandonExpedition(expedition.ID); err != nil {
+		t.Fatalf("abandon: %v", err)
+	}
+	abandoned, err := e.DB.GetExpeditionByID(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get abandoned expedition: %v", err)
+	}
+	if abandoned.Status != models.ExpeditionAbandoned {
+		t.Fatalf("expected abandoned, got %s", abandoned.Status)
+	}
+	all, err := e.DB.GetExpeditionAllQuests(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get all quests: %v", err)
+	}
+	for _, q := range all {
+		if q.Status == models.QuestFailed || q.Status == models.QuestActive {
+			t.Fatalf("expected quests archived, got %s", q.Status)
+		}
+	}
+}
+
+func TestFailedExpeditionCreateLeavesNothing(t *testing.T) {
+	e := newTestEngine(t)
+	before, err := e.DB.GetExpeditionCount()
+	if err != nil {
+		t.Fatalf("count expeditions: %v", err)
+	}
+
+	expedition := models.Expedition{
+		Name:  "Экспедиция без героя",
+		Tasks: []models.ExpeditionTask{{Title: "Шаг", ProgressTarget: 1, TargetStat: models.StatAgility}},
+	}
+	// The run references a character that does not exist, so the last write fails.
+	if err := e.DB.CreateExpedition(e.Character.ID+1000, &expedition); err == nil {
+		t.Fatal("expected the run insert to fail")
+	}
+	after, err := e.DB.GetExpeditionCount()
+	if err != nil {
+		t.Fatalf("count expeditions: %v", err)
+	}
+	if after != before {
+		t.Fatalf("a failed create must not keep the definition, got %d expeditions instead of %d", after, before)
+	}
+}
+
+func TestAbandonedOneShotExpeditionCannotRestart(t *testing.T) {
+	e := newTestEngine(t)
+
+	oneShot := models.Expedition{
+		Name:   "Разовая вылазка",
+		Status: models.ExpeditionActive,
+		Tasks:  []models.ExpeditionTask{{Title: "Шаг", ProgressTarget: 2, RewardEXP: 5, TargetStat: models.StatAgility}},
+	}
+	repeatable := models.Expedition{
+		Name:         "Ежедневный обход",
+		IsRepeatable: true,
+		Status:       models.ExpeditionActive,
+		Tasks:        []models.ExpeditionTask{{Title: "Круг", ProgressTarget: 2, RewardEXP: 5, TargetStat: models.StatAgility}},
+	}
+	for _, ex := range []*models.Expedition{&oneShot, &repeatable} {
+		if err := e.CreateExpedition(ex); err != nil {
+			t.Fatalf("create %s: %v", ex.Name, err)
+		}
+		if _, err := e.LogExpeditionTaskQuantity(ex.Tasks[0].ID, 1); err != nil {
+			t.Fatalf("log %s: %v", ex.Name, err)
+		}
+		if err := e.AbandonExpedition(ex.ID); err != nil {
+			t.Fatalf("abandon %s: %v", ex.Name, err)
+		}
+	}
+
+	if _, err := e.StartExpedition(oneShot.ID); err == nil {
+		t.Fatal("an abandoned one-shot expedition must not start a fresh run")
+	}
+	runs, err := e.DB.GetExpeditionRuns(e.Character.ID, oneShot.ID)
+	if err != nil {
+		t.Fatalf("get runs: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != models.ExpeditionAbandoned {
+		t.Fatalf("expected only the abandoned run, got %+v", runs)
+	}
+
+	if _, err := e.StartExpedition(repeatable.ID); err != nil {
+		t.Fatalf("a repeatable expedition may restart after abandoning: %v", err)
+	}
+	restarted, err := e.DB.GetExpeditionByID(e.Character.ID, repeatable.ID)
+	if err != nil {
+		t.Fatalf("get restarted expedition: %v", err)
+	}
+	if restarted.Status != models.ExpeditionActive || restarted.Tasks[0].ProgressCurrent != 0 {
+		t.Fatalf("expected a fresh active run, got %s with progress %d", restarted.Status, restarted.Tasks[0].ProgressCurrent)
+	}
+}
+
+func TestExpeditionExtensionReducesPayout(t *testing.T) {
+	e := newTestEngine(t)
+
+	deadline := time.Now().Add(time.Hour)
+	expedition := models.Expedition{
+		Name:      "Продлённая экспедиция",
+		Deadline:  &deadline,
+		RewardEXP: 100,
+		Status:    models.ExpeditionActive,
+		Tasks: []models.ExpeditionTask{
+			{Title: "Финал", ProgressTarget: 1, RewardEXP: 1, TargetStat: models.StatStrength},
+		},
+	}
+	if err := e.CreateExpedition(&expedition); err != nil {
+		t.Fatalf("create expedition: %v", err)
+	}
+	if _, err := e.ExtendDeadline(expedition.ID, 1); err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+
+	beforeStats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats before: %v", err)
+	}
+	before := statTotalsByType(beforeStats)
+	if _, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 1); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	afterStats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats after: %v", err)
+	}
+	after := statTotalsByType(afterStats)
+	if gained := after[models.StatAgility] - before[models.StatAgility]; gained != 80 {
+		t.Fatalf("expected 80 AGI EXP after one extension, got %d", gained)
+	}
+}
+
+func TestForecastExpeditionPaceAndRisk(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	started := now.AddDate(0, 0, -4)
+	deadline := now.AddDate(0, 0, 5)
+	expedition := &models.Expedition{
+		Status:    models.ExpeditionActive,
+		StartedAt: &started,
+		Deadline:  &deadline,
+		Tasks: []models.ExpeditionTask{
+			{ProgressCurrent: 20, ProgressTarget: 100},
+		},
+	}
+	daily := []models.DailyProgress{
+		{Date: startOfDay(now.AddDate(0, 0, -3)), Units: 8},
+		{Date: startOfDay(now.AddDate(0, 0, -1)), Units: 12},
+	}
+
+	f := ForecastExpedition(expedition, daily, now)
+	if f.UnitsDone != 20 || f.UnitsTotal != 100 || f.UnitsLeft() != 80 {
+		t.Fatalf("unexpected units: %+v", f)
+	}
+	if f.RecentPace != 5 {
+		t.Fatalf("expected pace 5/day over 4 days, got %.2f", f.RecentPace)
+	}
+	if f.DaysLeft != 5 || f.RequiredPace != 16 {
+		t.Fatalf("expected 5 days left at 16/day, got %d at %.2f", f.DaysLeft, f.RequiredPace)
+	}
+	if f.ProjectedFinish == nil || !f.ProjectedFinish.Equal(now.Add(16*24*time.Hour)) {
+		t.Fatalf("unexpected projected finish: %v", f.ProjectedFinish)
+	}
+	if !f.AtRisk {
+		t.Fatalf("expected expedition to be at risk")
+	}
+
+	expedition.Tasks[0].ProgressCurrent = 85
+	f = ForecastExpedition(expedition, daily, now)
+	if f.AtRisk {
+		t.Fatalf("expected expedition on track, projected %v", f.ProjectedFinish)
+	}
+
+	expedition.Tasks[0].IsCompleted = true
+	f = ForecastExpedition(expedition, daily, now)
+	if f.UnitsLeft() != 0 || f.ProjectedFinish != nil || f.AtRisk {
+		t.Fatalf("expected finished forecast, got %+v", f)
+	}
+}
+
+func TestExpeditionForecastUsesTaskLog(t *testing.T) {
+	e := newTestEngine(t)
+
+	deadline := time.Now().Add(12 * time.Hour)
+	expedition := models.Expedition{
+		Name:     "Спринт",
+		Status:   models.ExpeditionActive,
+		Deadline: &deadline,
+		Tasks: []models.ExpeditionTask{
+			{Title: "Отжимания", ProgressTarget: 100, RewardEXP: 1, TargetStat: models.StatStrength},
+		},
+	}
+	if err := e.DB.InsertExpedition(&expedition); err != nil {
+		t.Fatalf("insert expedition: %v", err)
+	}
+	if _, err := e.StartExpedition(expedition.ID); err != nil {
+		t.Fatalf("start expedition: %v", err)
+	}
+	if _, err := e.LogExpeditionTaskQuantity(expedition.Tasks[0].ID, 30); err != nil {
+		t.Fatalf("log quantity: %v", err)
+	}
+
+	f, err := e.GetExpeditionForecast(expedition.ID)
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	if len(f.Daily) != 1 || f.Daily[0].Units != 30 || f.UnitsDone != 30 {
+		t.Fatalf("unexpected burn-down: %+v", f)
+	}
+	if f.RecentPace != 30 || !f.AtRisk {
+		t.Fatalf("expected 30/day and at risk with <1 day left, got %+v", f)
+	}
+
+	atRisk, err := e.GetExpeditionsAtRisk()
+	if err != nil {
+		t.Fatalf("at risk: %v", err)
+	}
+	if len(atRisk) != 1 || atRisk[0].ID != expedition.ID {
+		t.Fatalf("expected expedition flagged at risk, got %+v", atRisk)
+	}
+}
+
+func TestExpeditionRunHistoryKeepsEveryAttempt(t *testing.T) {
+	e := newTestEngine(t)
+
+	expedition := models.Expedition{
+		Name:         "Марафонская База",
+		IsRepeatable: true,
+		Status:       models.ExpeditionActive,
+		RewardEXP:    10,
+		Tasks: []models.ExpeditionTask{
+			{Title: "Пробежать км", ProgressTarget: 5, RewardEXP: 3, TargetStat: models.StatEndurance},
+			{Title: "Растяжка", ProgressTarget: 2, RewardEXP: 1, TargetStat: models.StatAgility},
+		},
+	}
+	if err := e.DB.InsertExpedition(&expedition); err != nil {
+		t.Fatalf("insert expedition: %v", err)
+	}
+	runTask, stretchTask := expedition.Tasks[0].ID, expedition.Tasks[1].ID
+
+	if _, err := e.LogExpeditionTaskQuantity(runTask, 5); err != nil {
+		t.Fatalf("log run: %v", err)
+	}
+	if _, err := e.LogExpeditionTaskQuantity(stretchTask, 2); err != nil {
+		t.Fatalf("log stretch: %v", err)
+	}
+
+	if _, err := e.StartExpedition(expedition.ID); err != nil {
+		t.Fatalf("repeat expedition: %v", err)
+	}
+	if _, err := e.LogExpeditionTaskQuantity(runTask, 3); err != nil {
+		t.Fatalf("log second attempt: %v", err)
+	}
+	if err := e.AbandonExpedition(expedition.ID); err != nil {
+		t.Fatalf("abandon: %v", err)
+	}
+
+	runs, err := e.DB.GetExpeditionRuns(e.Character.ID, expedition.ID)
+	if err != nil {
+		t.Fatalf("get runs: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+
+	latest, first := runs[0], runs[1]
+	if first.Status != models.ExpeditionCompleted || first.Tier != models.TierGold || first.EndedAt == nil {
+		t.Fatalf("unexpected first run: %+v", first)
+	}
+	stats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+	if want := 5*3 + 2*1 + 10*len(stats); first.EXPEarned != want {
+		t.Fatalf("expected first run to earn %d EXP, got %d", want, first.EXPEarned)
+	}
+	if first.CompletedTasks() != 2 {
+		t.Fatalf("expected both tasks completed in first run, got %+v", first.Tasks)
+	}
+
+	if latest.Status != models.ExpeditionAbandoned || latest.EndedAt == nil || latest.EXPEarned != 9 {
+		t.Fatalf("unexpected second run: %+v", latest)
+	}
+	if latest.Tasks[0].ProgressCurrent != 3 || latest.Tasks[0].IsCompleted || latest.Tasks[1].ProgressCurrent != 0 {
+		t.Fatalf("unexpected second run tasks: %+v", latest.Tasks)
+	}
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"testing"
+
+	"solo-leveling/internal/game/combat"
+	"solo-leveling/internal/models"
+	"solo-leveling/internal/sim"
+)
+
+// TestEngineAndSimulatorAgreeOnSeed plays the same seeded fight through the
+// engine and through the simulator. Any drift between the two combat paths
+// shows up as a different outcome.
+func TestEngineAndSimulatorAgreeOnSeed(t *testing.T) {
+	e := newTestEngine(t)
+	enemies, err := e.DB.GetAllEnemies()
+	if err != nil {
+		t.Fatalf("get enemies: %v", err)
+	}
+	stats, err := e.GetStatLevels()
+	if err != nil {
+		t.Fatalf("stat levels: %v", err)
+	}
+	// A character strong enough to win most fights, so regen and crits matter.
+	for i := range stats {
+		stats[i].Level = 12
+		if err := e.DB.UpdateStatLevel(&stats[i]); err != nil {
+			t.Fatalf("update stat level: %v", err)
+		}
+	}
+	combatStats := combatStatsFromLevels(stats)
+	wins := 0
+
+	checked := map[string]bool{}
+	for _, enemy := range enemies {
+		if isEnemyBoss(enemy) || checked[enemy.Role] {
+			continue
+		}
+		checked[enemy.Role] = true
+
+		seed := int64(1000 + enemy.ID)
+		state, err := newBattleState(enemy, combatStats, seed)
+		if err != nil {
+			t.Fatalf("%s: new battle: %v", enemy.Name, err)
+		}
+		// Alternate perfect and half-finished rounds so both sides of the
+		// low-accuracy penalty are exercised.
+		for !state.BattleOver && state.Round <= 100 {
+			answer := BattleChallenge(state).Answer
+			if state.Round%2 == 0 {
+				answer = answer[:len(answer)/2]
+			}
+			if err := e.ProcessRound(state, answer); err != nil {
+				t.Fatalf("%s: round %d: %v", enemy.Name, state.Round, err)
+			}
+		}
+
+		outcome := sim.SimulateSeededBattle(
+			combatStats.STR, combatStats.AGI, combatStats.STA,
+			sim.EnemyDef{Name: enemy.Name, Role: enemy.Role, HP: enemy.HP, Attack: enemy.Attack},
+			seed,
+			func(round int) float64 { return state.Rounds[round-1].Accuracy },
+		)
+		if outcome.Win {
+			wins++
+		}
+		if outcome.Win != (state.Result == models.BattleWin) ||
+			outcome.Rounds != len(state.Rounds) ||
+			outcome.DamageDealt != state.DamageDealt ||
+			outcome.DamageTaken != state.DamageTaken ||
+			outcome.Crits != state.TotalCrits ||
+			outcome.EnemyHPLeft != state.EnemyHP {
+			t.Fatalf("%s (%s, %s): engine and simulator diverged: engine win=%v rounds=%d dealt=%d taken=%d crits=%d hp=%d, sim %+v",
+				enemy.Name, enemy.Role, combat.KindForEnemy(enemy),
+				state.Result == models.BattleWin, len(state.Rounds), state.DamageDealt, state.DamageTaken, state.TotalCrits, state.EnemyHP, outcome)
+		}
+	}
+	if len(checked) < 2 || wins == 0 {
+		t.Fatalf("expected several enemy roles and some wins, checked %v, wins %d", checked, wins)
+	}
+}
@@ -0,0 +1,193 @@
+package game
+
+import (
+	"fmt"
+
+	"solo-leveling/internal/game/combat"
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+)
+
+// ============================================================
+// Practice arena
+// ============================================================
+
+const (
+	// practiceRole keeps the training dummy on the classic grid.
+	practiceRole = "PRACTICE"
+	// practiceDummyRounds is how many perfect rounds the training dummy lasts.
+	practiceDummyRounds = 8
+	// PracticeMinGrid and PracticeMaxGrid bound a custom practice field.
+	PracticeMinGrid = 3
+	PracticeMaxGrid = 8
+	// PracticeMinCells is the fewest highlighted cells of a custom field.
+	PracticeMinCells = 2
+	// practiceTrendWindow is how many runs each side of the accuracy trend averages.
+	practiceTrendWindow = 5
+)
+
+// PracticeMaxCells is the most highlighted cells a custom field of size grid allows.
+func PracticeMaxCells(grid int) int {
+	return grid * grid / 2
+}
+
+// PracticeProgress is the recent practice history with its accuracy trend.
+type PracticeProgress struct {
+	Runs     []models.PracticeRun // newest first
+	Recent   float64              // average accuracy of the latest runs
+	Previous float64              // average accuracy of the runs before them
+}
+
+// HasTrend reports whether there are enough runs to compare.
+func (p PracticeProgress) HasTrend() bool {
+	return len(p.Runs) > practiceTrendWindow
+}
+
+// Delta is the accuracy change between the two windows, in percentage points.
+func (p PracticeProgress) Delta() float64 {
+	return p.Recent - p.Previous
+}
+
+// StartPractice opens a practice fight against an enemy the character has
+// already defeated. It costs no attempt and never changes tower progress.
+// Bosses are fought on their grid without scripted phases.
+func (e *Engine) StartPractice(enemyID int64) (*models.BattleState, error) {
+	defeated, err := e.defeatedEnemyIDs(e.Character.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !defeated[enemyID] {
+		return nil, fmt.Errorf("тренировка доступна только с побеждёнными врагами")
+	}
+	enemy, err := e.DB.GetEnemyByID(enemyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		return nil, err
+	}
+
+	stats, err := e.combatStats()
+	if err != nil {
+		return nil, err
+	}
+	state, err := newBattleState(*enemy, stats, memory.NewSeed())
+	if err != nil {
+		return nil, err
+	}
+	state.Practice = true
+	if err := e.saveBattle(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// StartCustomPractice opens a practice fight on a grid×grid field with cells
+// highlighted every round, against a dummy that barely hits back.
+func (e *Engine) StartCustomPractice(grid, cells int) (*models.BattleState, error) {
+	if grid < PracticeMinGrid || grid > PracticeMaxGrid {
+		return nil, fmt.Errorf("размер поля должен быть от %d до %d", PracticeMinGrid, PracticeMaxGrid)
+	}
+	if cells < PracticeMinCells || cells > PracticeMaxCells(grid) {
+		return nil, fmt.Errorf("клеток для поля %dx%d должно быть от %d до %d", grid, grid, PracticeMinCells, PracticeMaxCells(grid))
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		return nil, err
+	}
+
+	stats, err := e.combatStats()
+	if err != nil {
+		return nil, err
+	}
+	dummy := models.Enemy{
+		Name:        fmt.Sprintf("Тренировочный манекен %dx%d", grid, grid),
+		Description: "Почти не бьёт в ответ: только поле и память.",
+		Rank:        models.RankE,
+		Type:        models.EnemyRegular,
+		HP:          practiceDummyRounds * memory.BasePlayerDamage(stats.STR),
+		Role:        practiceRole,
+	}
+	state, err := newBattleState(dummy, stats, memory.NewSeed())
+	if err != nil {
+		return nil, err
+	}
+	state.Practice = true
+	state.PracticeGrid = grid
+	state.PracticeCells = cells
+	round, err := dealRound(state, state.Minigame, stats, memory.RoundRNG(state.Seed, 0))
+	if err != nil {
+		return nil, err
+	}
+	setBattleChallenge(state, round)
+	if err := e.saveBattle(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// dealRound deals the next round of a fight, honouring a custom practice field.
+func dealRound(state *models.BattleState, kind models.MinigameKind, stats memory.Stats, rng memory.RNG) (models.MinigameRound, error) {
+	if state.PracticeGrid == 0 {
+		return combat.NewRound(kind, state.Enemy, stats, rng)
+	}
+	cells, err := memory.GenerateShownCells(state.PracticeGrid, state.PracticeCells, rng)
+	if err != nil {
+		return models.MinigameRound{}, err
+	}
+	return combat.GridRound(state.PracticeGrid, cells, memory.TimeToShow(state.Enemy, stats)), nil
+}
+
+// finishPractice stores a finished practice fight in its own history.
+func (e *Engine) finishPractice(state *models.BattleState, record *models.BattleRecord) (*models.BattleRecord, error) {
+	run := &models.PracticeRun{
+		CharID:    e.Character.ID,
+		EnemyID:   state.Enemy.ID,
+		EnemyName: state.Enemy.Name,
+		GridSize:  state.GridSize,
+		Cells:     state.PracticeCells,
+		Result:    state.Result,
+		Accuracy:  record.Accuracy,
+		Rounds:    len(state.Rounds),
+	}
+	if err := e.DB.InsertPracticeRun(run); err != nil {
+		return nil, err
+	}
+	if err := e.DB.DeleteActiveBattle(e.Character.ID); err != nil {
+		return nil, err
+	}
+	record.FoughtAt = run.PlayedAt
+	return record, nil
+}
+
+// GetPracticeProgress returns the latest practice runs and their accuracy trend.
+func (e *Engine) GetPracticeProgress(limit int) (*PracticeProgress, error) {
+	if limit < 2*practiceTrendWindow {
+		limit = 2 * practiceTrendWindow
+	}
+	runs, err := e.DB.GetPracticeRuns(e.Character.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	progress := &PracticeProgress{Runs: runs}
+	recent, previous := runs, []models.PracticeRun(nil)
+	if len(runs) > practiceTrendWindow {
+		recent, previous = runs[:practiceTrendWindow], runs[practiceTrendWindow:]
+		if len(previous) > practiceTrendWindow {
+			previous = previous[:practiceTrendWindow]
+		}
+	}
+	progress.Recent = averageAccuracy(recent)
+	progress.Previous = averageAccuracy(previous)
+	return progress, nil
+}
+
+func averageAccuracy(runs []models.PracticeRun) float64 {
+	if len(runs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, r := range runs {
+		sum += r.Accuracy
+	}
+	return sum / float64(len(runs))
+}
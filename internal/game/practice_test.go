@@ -0,0 +1,119 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"solo-leveling/internal/models"
+)
+
+func TestPracticeAgainstClearedEnemyKeepsProgression(t *testing.T) {
+	e := newTestEngine(t)
+	rules := models.DefaultAttemptRules()
+	e.AttemptRules = &rules
+	if _, err := e.DB.ClaimDailyAttempts(e.Character.ID, time.Now().Format("2006-01-02"), 0, 0); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+
+	current, err := e.GetCurrentEnemy()
+	if err != nil || current == nil {
+		t.Fatalf("expected current enemy, got %v (%v)", current, err)
+	}
+	if _, err := e.StartPractice(current.ID); err == nil {
+		t.Fatal("practice must require a defeated enemy")
+	}
+	markDefeated(t, e, current.ID)
+	next, _ := e.GetCurrentEnemy()
+	battlesBefore, _ := e.GetBattleStats()
+	attemptsBefore := e.GetAttempts()
+
+	state, err := e.StartPractice(current.ID)
+	if err != nil {
+		t.Fatalf("start practice: %v", err)
+	}
+	if !state.Practice || state.Enemy.ID != current.ID {
+		t.Fatalf("unexpected practice state: %+v", state.Enemy)
+	}
+	if err := e.ProcessRound(state, BattleChallenge(state).Answer); err != nil {
+		t.Fatalf("process round: %v", err)
+	}
+	state.BattleOver = true
+	state.Result = models.BattleLose
+	if _, err := e.FinishBattle(state); err != nil {
+		t.Fatalf("finish practice: %v", err)
+	}
+
+	if e.GetAttempts() != attemptsBefore {
+		t.Fatalf("practice must not spend attempts: %d -> %d", attemptsBefore, e.GetAttempts())
+	}
+	if after, _ := e.GetBattleStats(); after.TotalBattles != battlesBefore.TotalBattles {
+		t.Fatal("practice must not be written to the battle history")
+	}
+	if again, _ := e.GetCurrentEnemy(); again == nil || again.ID != next.ID {
+		t.Fatal("practice must not move tower progress")
+	}
+	progress, err := e.GetPracticeProgress(5)
+	if err != nil || len(progress.Runs) != 1 {
+		t.Fatalf("expected one practice run, got %+v (%v)", progress, err)
+	}
+	if run := progress.Runs[0]; run.EnemyID != current.ID || run.Rounds != 1 || run.Accuracy != 100 {
+		t.Fatalf("unexpected practice run: %+v", run)
+	}
+}
+
+func TestCustomPracticeUsesChosenField(t *testing.T) {
+	e := newTestEngine(t)
+
+	if _, err := e.StartCustomPractice(PracticeMaxGrid+1, 4); err == nil {
+		t.Fatal("oversized field must be rejected")
+	}
+	if _, err := e.StartCustomPractice(4, PracticeMaxCells(4)+1); err == nil {
+		t.Fatal("too many cells must be rejected")
+	}
+
+	state, err := e.StartCustomPractice(6, 7)
+	if err != nil {
+		t.Fatalf("start custom practice: %v", err)
+	}
+	for round := 0; round < 2; round++ {
+		if state.GridSize != 6 || len(state.ShownCells) != 7 {
+			t.Fatalf("round %d: expected a 6x6 field with 7 cells, got %dx%d with %d", round+1, state.GridSize, state.GridSize, len(state.ShownCells))
+		}
+		if err := e.ProcessRound(state, nil); err != nil {
+			t.Fatalf("process round: %v", err)
+		}
+	}
+
+	saved, err := e.GetSavedBattle()
+	if err != nil || saved == nil || saved.Battle.PracticeGrid != 6 {
+		t.Fatalf("custom field must survive a resume: %+v (%v)", saved, err)
+	}
+	if _, err := e.ForfeitSavedBattle(); err != nil {
+		t.Fatalf("forfeit: %v", err)
+	}
+	progress, _ := e.GetPracticeProgress(5)
+	if len(progress.Runs) != 1 || progress.Runs[0].Cells != 7 || progress.Runs[0].Result != models.BattleLose {
+		t.Fatalf("forfeited practice should be recorded as a lost run: %+v", progress.Runs)
+	}
+}
+
+func TestPracticeProgressComparesRecentRuns(t *testing.T) {
+	e := newTestEngine(t)
+	for i := 0; i < 2*practiceTrendWindow; i++ {
+		accuracy := 50.0
+		if i >= practiceTrendWindow {
+			accuracy = 80
+		}
+		if err := e.DB.InsertPracticeRun(&models.PracticeRun{CharID: e.Character.ID, Accuracy: accuracy}); err != nil {
+			t.Fatalf("insert run: %v", err)
+		}
+	}
+
+	progress, err := e.GetPracticeProgress(0)
+	if err != nil {
+		t.Fatalf("progress: %v", err)
+	}
+	if !progress.HasTrend() || progress.Recent != 80 || progress.Previous != 50 || progress.Delta() != 30 {
+		t.Fatalf("unexpected trend: %+v", progress)
+	}
+}
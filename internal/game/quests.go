@@ -126,7 +126,7 @@ func (e *Engine) CompleteQuest(questID int64) (*CompleteResult, error) {
 
 	// Award battle attempts based on quest EXP.
 	attemptsAwarded := models.AttemptsForQuestEXP(quest.Exp)
-	totalAttempts, _ := e.DB.AddAttempts(e.Character.ID, attemptsAwarded)
+	totalAttempts, _ := e.awardAttempts(attemptsAwarded)
 	e.Character.Attempts = totalAttempts
 
 	// First completion achievement (idempotent).
@@ -74,7 +74,8 @@ func (e *Engine) IsBossAvailable(userID int64, zone int) (bool, error) {
 }
 
 // PickNextEnemy chooses a single next enemy for Today:
-// regular enemies in current zone first, then the zone boss.
+// regular enemies in current zone first, then the zone boss. Once the catalog
+// is cleared it returns the character's next endless floor without storing it.
 func (e *Engine) PickNextEnemy(userID int64) (*models.Enemy, error) {
 	allEnemies, err := e.DB.GetAllEnemies()
 	if err != nil {
@@ -141,7 +142,8 @@ func (e *Engine) PickNextEnemy(userID int64) (*models.Enemy, error) {
 	if boss != nil && !defeated[boss.ID] {
 		return boss, nil
 	}
-	return nil, nil
+	// Everything in the catalog is cleared: the tower goes on with generated floors.
+	return e.nextEndlessFloor(userID, allEnemies)
 }
 
 // GetNextEnemyForPlayer keeps compatibility with existing callers.
@@ -177,14 +179,15 @@ func (e *Engine) validateCurrentEnemyForFight(enemyID int64) (*models.Enemy, err
 	if current.ID != enemyID {
 		return nil, fmt.Errorf("доступен только текущий враг")
 	}
+	if current.Endless && current.ID == 0 {
+		// A generated floor gets its row when it is first fought.
+		if err := e.DB.InsertEndlessFloor(e.Character.ID, current); err != nil {
+			return nil, err
+		}
+	}
 	return current, nil
 }
 
-func (e *Engine) spendBattleAttempt() error {
-	// Battle attempts mechanic is disabled: fights are always available.
-	return nil
-}
-
 func (e *Engine) defeatedEnemyIDs(userID int64) (map[int64]bool, error) {
 	return e.DB.GetDefeatedEnemyIDs(userID)
 }
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
@@ -146,6 +148,8 @@ const (
 	QuestActive    QuestStatus = "active"
 	QuestCompleted QuestStatus = "completed"
 	QuestFailed    QuestStatus = "failed"
+	// QuestArchived quests were closed without outcome (e.g. abandoned expedition).
+	QuestArchived QuestStatus = "archived"
 )
 
 type Character struct {
@@ -209,6 +213,59 @@ type AISuggestion struct {
 
 const MaxAttempts = 8
 
+// AttemptRules configures the battle-attempts economy.
+type AttemptRules struct {
+	DailyFree      int  // attempts granted once per calendar day
+	ReserveCap     int  // attempts earned above MaxAttempts kept in reserve, up to this many
+	RefundUnplayed bool // a fight abandoned before its first round returns its attempt
+}
+
+// DefaultAttemptRules returns the rules used when the attempts economy is on.
+func DefaultAttemptRules() AttemptRules {
+	return AttemptRules{
+		DailyFree:      1,
+		ReserveCap:     4,
+		RefundUnplayed: true,
+	}
+}
+
+// AdaptiveRules configures adaptive difficulty: how much recent battle
+// performance may nudge the memory field towards each enemy's target win rate.
+type AdaptiveRules struct {
+	Window           int     // recent tower battles looked at
+	MinBattles       int     // battles needed before anything is adjusted
+	MaxCellShift     int     // most highlighted cells added or removed
+	ShowTimeStep     int     // show time change per step, in percent
+	WinRateStep      float64 // estimated win rate outside the target band per step, in points
+	EstimateFights   int     // simulated fights behind the win-rate estimate
+	EstimateMaxRound int     // rounds after which a simulated fight counts as lost
+}
+
+// DefaultAdaptiveRules returns the rules used when adaptive difficulty is on.
+func DefaultAdaptiveRules() AdaptiveRules {
+	return AdaptiveRules{
+		Window:           10,
+		MinBattles:       3,
+		MaxCellShift:     2,
+		ShowTimeStep:     10,
+		WinRateStep:      15,
+		EstimateFights:   200,
+		EstimateMaxRound: 30,
+	}
+}
+
+// DifficultyShift is the adaptive nudge applied to an enemy's memory field.
+// The zero value leaves the field as the stats and rank make it.
+type DifficultyShift struct {
+	Cells           int // highlighted cells added (negative removes)
+	ShowTimePercent int // show time change in percent (negative is shorter)
+}
+
+// IsZero reports whether the shift changes nothing.
+func (d DifficultyShift) IsZero() bool {
+	return d.Cells == 0 && d.ShowTimePercent == 0
+}
+
 // AttemptsForRank is legacy mapping retained for compatibility in non-quest systems.
 func AttemptsForRank(rank QuestRank) int {
 	switch rank {
@@ -331,6 +388,17 @@ const (
 	ExpeditionActive    ExpeditionStatus = "active"
 	ExpeditionCompleted ExpeditionStatus = "completed"
 	ExpeditionFailed    ExpeditionStatus = "failed"
+	ExpeditionPaused    ExpeditionStatus = "paused"
+	ExpeditionAbandoned ExpeditionStatus = "abandoned"
+)
+
+const (
+	// MaxExpeditionExtensions is how many times one run's deadline may be extended.
+	MaxExpeditionExtensions = 1
+	// MaxExpeditionExtensionDays caps a single deadline extension.
+	MaxExpeditionExtensionDays = 7
+	// ExpeditionExtensionPenalty is the payout share lost per extension.
+	ExpeditionExtensionPenalty = 0.2
 )
 
 type Expedition struct {
@@ -345,8 +413,19 @@ type Expedition struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	Tasks        []ExpeditionTask
+	Rewards      []Reward
+	Tiers        ExpeditionTiers
+	// RunID is the character's current run; 0 when the character never started it.
+	RunID int64
+	// StartedAt, PausedAt and Extensions describe the current run.
+	StartedAt  *time.Time
+	PausedAt   *time.Time
+	Extensions int
 }
 
+// DefaultTaskRewardEXP is the per-unit EXP of an expedition task that sets none.
+const DefaultTaskRewardEXP = 20
+
 type ExpeditionTask struct {
 	ID              int64
 	ExpeditionID    int64
@@ -357,17 +436,162 @@ type ExpeditionTask struct {
 	ProgressTarget  int
 	RewardEXP       int
 	TargetStat      StatType
+	Rewards         []Reward
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
+// ExpeditionTaskLogEntry records a quantity logged against an expedition task.
+type ExpeditionTaskLogEntry struct {
+	ID           int64
+	CharID       int64
+	ExpeditionID int64
+	RunID        int64
+	TaskID       int64
+	Quantity     int
+	EXPAwarded   int
+	LoggedAt     time.Time
+}
+
+// DailyProgress is the number of expedition units logged on one local day.
+type DailyProgress struct {
+	Date  time.Time
+	Units int
+}
+
+// ExpeditionRun is one attempt of a character at an expedition.
+type ExpeditionRun struct {
+	ID           int64
+	CharID       int64
+	ExpeditionID int64
+	Status       ExpeditionStatus
+	// Tier is set for completed runs only.
+	Tier       ExpeditionTier
+	Deadline   *time.Time
+	StartedAt  time.Time
+	EndedAt    *time.Time
+	Extensions int
+	// EXPEarned sums task EXP logged during the run and the completion payout.
+	EXPEarned int
+	Tasks     []ExpeditionRunTask
+}
+
+// Duration returns how long the run took, or has taken so far if it is still open.
+func (r ExpeditionRun) Duration(now time.Time) time.Duration {
+	end := now
+	if r.EndedAt != nil {
+		end = *r.EndedAt
+	}
+	if end.Before(r.StartedAt) {
+		return 0
+	}
+	return end.Sub(r.StartedAt)
+}
+
+// CompletedTasks returns how many of the run's tasks were finished.
+func (r ExpeditionRun) CompletedTasks() int {
+	n := 0
+	for _, task := range r.Tasks {
+		if task.IsCompleted {
+			n++
+		}
+	}
+	return n
+}
+
+// ExpeditionRunTask is the result of a single task within a run.
+type ExpeditionRunTask struct {
+	TaskID          int64
+	Title           string
+	ProgressCurrent int
+	ProgressTarget  int
+	IsCompleted     bool
+}
+
 type CompletedExpedition struct {
 	ID           int64
 	CharID       int64
 	ExpeditionID int64
+	Tier         ExpeditionTier
 	CompletedAt  time.Time
 }
 
+// ExpeditionTier is the outcome grade of an expedition run.
+type ExpeditionTier string
+
+const (
+	TierNone   ExpeditionTier = ""
+	TierBronze ExpeditionTier = "bronze"
+	TierSilver ExpeditionTier = "silver"
+	TierGold   ExpeditionTier = "gold"
+)
+
+// PayoutFraction is the share of RewardEXP/RewardStats paid for the tier.
+func (t ExpeditionTier) PayoutFraction() float64 {
+	switch t {
+	case TierGold:
+		return 1.0
+	case TierSilver:
+		return 0.5
+	case TierBronze:
+		return 0.25
+	default:
+		return 0
+	}
+}
+
+func (t ExpeditionTier) Icon() string {
+	switch t {
+	case TierGold:
+		return "🥇"
+	case TierSilver:
+		return "🥈"
+	case TierBronze:
+		return "🥉"
+	default:
+		return ""
+	}
+}
+
+func (t ExpeditionTier) DisplayName() string {
+	switch t {
+	case TierGold:
+		return "Золото"
+	case TierSilver:
+		return "Серебро"
+	case TierBronze:
+		return "Бронза"
+	default:
+		return "Нет"
+	}
+}
+
+// ExpeditionTiers holds completion-percent thresholds (0-100) for partial
+// outcomes at the deadline. A zero threshold disables that tier.
+type ExpeditionTiers struct {
+	Bronze int `json:"bronze,omitempty"`
+	Silver int `json:"silver,omitempty"`
+	Gold   int `json:"gold,omitempty"`
+}
+
+func (t ExpeditionTiers) Enabled() bool {
+	return t.Bronze > 0 || t.Silver > 0 || t.Gold > 0
+}
+
+// TierFor returns the best tier reached at the given completion percent.
+func (t ExpeditionTiers) TierFor(percent float64) ExpeditionTier {
+	switch {
+	case t.Gold > 0 && percent >= float64(t.Gold):
+		return TierGold
+	case t.Silver > 0 && percent >= float64(t.Silver):
+		return TierSilver
+	case t.Bronze > 0 && percent >= float64(t.Bronze):
+		return TierBronze
+	default:
+		return TierNone
+	}
+}
+
 // --- Enemy System ---
 
 type EnemyType string
@@ -391,9 +615,98 @@ type Enemy struct {
 	IsBoss           bool
 	Biome            string
 	Role             string
+	Image            string // file name under assets/enemies, optional
 	IsTransition     bool
 	TargetWinRateMin float64
 	TargetWinRateMax float64
+	// Endless marks a floor generated past the catalog; it is never reseeded.
+	Endless bool
+	// Modifiers are traits added on top of the biome and role ones, e.g. by the daily challenge.
+	Modifiers []EnemyModifier
+	// BossPhases script a boss fight; empty for regular enemies.
+	BossPhases []BossPhase
+	// Difficulty is the adaptive nudge set when a fight starts; never stored in the catalog.
+	Difficulty DifficultyShift
+}
+
+// EnemyModifier is a biome, role or challenge trait that changes how a regular fight plays.
+type EnemyModifier string
+
+const (
+	ModifierFog     EnemyModifier = "fog"
+	ModifierFlicker EnemyModifier = "flicker"
+	ModifierShuffle EnemyModifier = "shuffle"
+	ModifierRegen   EnemyModifier = "regen"
+
+	// Daily challenge modifiers.
+	ModifierHaste    EnemyModifier = "haste"
+	ModifierWideGrid EnemyModifier = "wide_grid"
+	ModifierNoCrit   EnemyModifier = "no_crit"
+)
+
+func (m EnemyModifier) DisplayName() string {
+	switch m {
+	case ModifierFog:
+		return "Туман"
+	case ModifierFlicker:
+		return "Мерцание"
+	case ModifierShuffle:
+		return "Перетасовка"
+	case ModifierRegen:
+		return "Регенерация"
+	case ModifierHaste:
+		return "Спешка"
+	case ModifierWideGrid:
+		return "Широкое поле"
+	case ModifierNoCrit:
+		return "Без критов"
+	default:
+		return string(m)
+	}
+}
+
+// Description explains the modifier on the enemy card.
+func (m EnemyModifier) Description() string {
+	switch m {
+	case ModifierFog:
+		return "Часть поля скрыта туманом: клетки под ним гаснут раньше. На одну клетку меньше."
+	case ModifierFlicker:
+		return "Подсвеченные клетки мерцают и время от времени гаснут."
+	case ModifierShuffle:
+		return "На середине показа часть клеток перескакивает на новые места."
+	case ModifierRegen:
+		return "Враг восстанавливает часть HP каждый раунд."
+	case ModifierHaste:
+		return "Показ короче обычного."
+	case ModifierWideGrid:
+		return "Поле больше обычного."
+	case ModifierNoCrit:
+		return "Критические удары отключены."
+	default:
+		return ""
+	}
+}
+
+// BossMechanic is the effect a boss phase switches on.
+type BossMechanic string
+
+const (
+	// BossEnrage shortens the highlight phase to Value percent.
+	BossEnrage BossMechanic = "enrage"
+	// BossShield raises Value shield layers; each needs a perfect round to break.
+	BossShield BossMechanic = "shield"
+	// BossDecoys adds Value decoy cells that hurt the player when clicked.
+	BossDecoys BossMechanic = "decoys"
+	// BossLastStand raises boss attack by Value percent and survives one lethal blow.
+	BossLastStand BossMechanic = "last_stand"
+)
+
+// BossPhase triggers once the boss HP fraction drops to HPThreshold or below.
+type BossPhase struct {
+	Name        string
+	HPThreshold float64
+	Mechanic    BossMechanic
+	Value       int
 }
 
 // StreakTitle returns the title earned at a given streak milestone, or empty string.
@@ -448,16 +761,155 @@ type BattleRecord struct {
 	Accuracy     float64
 	CriticalHits int
 	Dodges       int
+	Seed         int64
+	// ChallengeDay is the date (YYYY-MM-DD) of a daily challenge fight, empty otherwise.
+	ChallengeDay string
 	FoughtAt     time.Time
+	// Rounds are written together with the battle; history queries leave them empty.
+	Rounds []BattleRound
 	// Runtime-only reward info (not persisted)
 	RewardTitle       string
 	RewardBadge       string
+	RewardCosmetic    string
+	RewardItem        string
 	UnlockedEnemyName string
 }
 
+// EquipmentSlot is where an item is worn; one item per slot can be equipped.
+type EquipmentSlot string
+
+const (
+	SlotWeapon    EquipmentSlot = "weapon"
+	SlotArmor     EquipmentSlot = "armor"
+	SlotAccessory EquipmentSlot = "accessory"
+)
+
+// AllEquipmentSlots lists the slots in display order.
+var AllEquipmentSlots = []EquipmentSlot{SlotWeapon, SlotArmor, SlotAccessory}
+
+func (s EquipmentSlot) DisplayName() string {
+	switch s {
+	case SlotWeapon:
+		return "Оружие"
+	case SlotArmor:
+		return "Броня"
+	case SlotAccessory:
+		return "Аксессуар"
+	default:
+		return string(s)
+	}
+}
+
+func (s EquipmentSlot) Icon() string {
+	switch s {
+	case SlotWeapon:
+		return "🗡"
+	case SlotArmor:
+		return "🛡"
+	case SlotAccessory:
+		return "💍"
+	default:
+		return "•"
+	}
+}
+
+// ItemEffects are the combat bonuses an item gives while equipped.
+type ItemEffects struct {
+	STR             int
+	AGI             int
+	INT             int
+	STA             int
+	ShowTimePercent int // longer highlight, in percent
+	CritPercent     int // added crit chance, in percent
+	BonusHP         int
+}
+
+// Add returns the sum of both effects.
+func (e ItemEffects) Add(other ItemEffects) ItemEffects {
+	return ItemEffects{
+		STR:             e.STR + other.STR,
+		AGI:             e.AGI + other.AGI,
+		INT:             e.INT + other.INT,
+		STA:             e.STA + other.STA,
+		ShowTimePercent: e.ShowTimePercent + other.ShowTimePercent,
+		CritPercent:     e.CritPercent + other.CritPercent,
+		BonusHP:         e.BonusHP + other.BonusHP,
+	}
+}
+
+// Summary lists the non-zero effects, e.g. "+2 Сила, +5% крит".
+func (e ItemEffects) Summary() string {
+	var parts []string
+	for _, stat := range []struct {
+		value int
+		name  string
+	}{
+		{e.STR, StatStrength.DisplayName()},
+		{e.AGI, StatAgility.DisplayName()},
+		{e.INT, StatIntellect.DisplayName()},
+		{e.STA, StatEndurance.DisplayName()},
+	} {
+		if stat.value != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", stat.value, stat.name))
+		}
+	}
+	if e.ShowTimePercent != 0 {
+		parts = append(parts, fmt.Sprintf("%+d%% показ", e.ShowTimePercent))
+	}
+	if e.CritPercent != 0 {
+		parts = append(parts, fmt.Sprintf("%+d%% крит", e.CritPercent))
+	}
+	if e.BonusHP != 0 {
+		parts = append(parts, fmt.Sprintf("%+d HP", e.BonusHP))
+	}
+	if len(parts) == 0 {
+		return "без эффектов"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Item is an equipment template; Key identifies it in the inventory.
+type Item struct {
+	Key     string
+	Name    string
+	Slot    EquipmentSlot
+	Effects ItemEffects
+}
+
+// InventoryItem is an item the character owns.
+type InventoryItem struct {
+	ID         int64
+	CharID     int64
+	Item       Item
+	Source     string // where it dropped, e.g. the boss or expedition name
+	Equipped   bool
+	AcquiredAt time.Time
+}
+
+// PracticeRun is a finished practice fight, kept apart from the battle history.
+type PracticeRun struct {
+	ID        int64
+	CharID    int64
+	EnemyID   int64 // 0 for a custom field
+	EnemyName string
+	GridSize  int
+	Cells     int // highlighted cells of a custom field, 0 otherwise
+	Result    BattleResult
+	Accuracy  float64
+	Rounds    int
+	PlayedAt  time.Time
+}
+
 // BattleState holds the live state of a memory-game battle
 type BattleState struct {
 	Enemy         Enemy
+	Seed          int64  // drives every random draw of the fight
+	ChallengeDay  string // set for the daily challenge fight
+	Practice      bool   // practice fights touch neither attempts nor progression
+	PracticeGrid  int    // custom practice field size; 0 deals rounds from the enemy
+	PracticeCells int    // custom practice highlighted cells
+	Minigame      MinigameKind
+	Challenge     MinigameRound // current round; grid fields below mirror it
 	PlayerHP      int
 	PlayerMaxHP   int
 	EnemyHP       int
@@ -485,6 +937,125 @@ type BattleState struct {
 	LastRoundAccuracy float64
 	LastRoundCrit     bool
 	RoundLog          []string // last N log lines
+
+	// Full per-round recording for replays; RoundStartedAt times the current round.
+	Rounds         []BattleRound
+	RoundStartedAt time.Time
+}
+
+// MinigameKind identifies the combat minigame a fight is played with.
+type MinigameKind string
+
+const (
+	MinigameGridMemory MinigameKind = "grid_memory"
+	MinigameSequence   MinigameKind = "sequence"
+	MinigameNBack      MinigameKind = "n_back"
+	MinigameStroop     MinigameKind = "stroop"
+	MinigameRotation   MinigameKind = "rotation"
+)
+
+func (k MinigameKind) DisplayName() string {
+	switch k {
+	case MinigameSequence:
+		return "Последовательность"
+	case MinigameNBack:
+		return "N-назад"
+	case MinigameStroop:
+		return "Струп"
+	case MinigameRotation:
+		return "Поворот узора"
+	default:
+		return "Память на сетке"
+	}
+}
+
+// IsGrid reports whether the minigame's answer is a set of cells on a grid.
+func (k MinigameKind) IsGrid() bool {
+	return k == "" || k == MinigameGridMemory || k == MinigameSequence || k == MinigameRotation
+}
+
+// MinigameRound is one generated challenge. Which fields are used depends on Kind.
+type MinigameRound struct {
+	Kind     MinigameKind
+	GridSize int
+	// Cells is the stimulus: highlighted cells, an ordered sequence, the n-back
+	// stream of positions or the pattern to rotate.
+	Cells []int
+	// N is the n-back distance.
+	N int
+	// Turns is how many quarter turns clockwise the rotation pattern must be turned.
+	Turns   int
+	Prompts []StroopPrompt
+	// ShowTimeMs is the whole highlight phase for grid and rotation, and the
+	// time per stimulus for sequence, n-back and Stroop.
+	ShowTimeMs int
+	// Inputs is how many answers finish the round.
+	Inputs int
+	// Answer is the correct input.
+	Answer []int
+	// Decoys are cells highlighted as traps during a boss fight; clicking them hurts.
+	Decoys []int
+	// Fog covers cells whose highlight fades after the first half of the show time.
+	Fog []int
+	// Flicker blinks the highlighted cells instead of holding them steady.
+	Flicker bool
+	// Shuffled moves some highlighted cells halfway through the show time;
+	// Answer then holds their final positions.
+	Shuffled bool
+}
+
+// StroopPrompt is a colour word (Word) drawn in another colour (Ink); both index StroopColors.
+type StroopPrompt struct {
+	Word int
+	Ink  int
+}
+
+// StroopColors are the colour names used by the Stroop minigame.
+var StroopColors = []string{"КРАСНЫЙ", "СИНИЙ", "ЗЕЛЁНЫЙ", "ЖЁЛТЫЙ"}
+
+// BattleRound is the recording of one combat round.
+type BattleRound struct {
+	ID         int64
+	BattleID   int64
+	Round      int
+	Minigame   MinigameKind
+	GridSize   int
+	ShownCells []int // the expected answer
+	// DecoyCells are the boss traps highlighted alongside the pattern.
+	DecoyCells  []int
+	Choices     []int
+	Accuracy    float64
+	DamageDealt int
+	DamageTaken int
+	IsCrit      bool
+	ShowTimeMs  int
+	// DurationMs is the time from the round being shown to the answer.
+	DurationMs int
+}
+
+// RoundDurationMs returns milliseconds elapsed since start, or 0 if start is unset.
+func RoundDurationMs(start time.Time, now time.Time) int {
+	if start.IsZero() || now.Before(start) {
+		return 0
+	}
+	return int(now.Sub(start) / time.Millisecond)
+}
+
+// BattleKind tells which state type a saved battle holds.
+type BattleKind string
+
+const (
+	BattleKindRegular BattleKind = "battle"
+	BattleKindBoss    BattleKind = "boss"
+)
+
+// ActiveBattle is the serialized state of a fight that has not been recorded yet.
+type ActiveBattle struct {
+	CharID    int64
+	Kind      BattleKind
+	EnemyID   int64
+	State     string // JSON of BattleState or boss.State
+	UpdatedAt time.Time
 }
 
 // --- Extended Statistics ---
@@ -500,6 +1071,50 @@ type BattleStatistics struct {
 	EnemiesDefeated map[string]int // enemy name -> defeat count
 }
 
+// --- Rewards ---
+
+type RewardKind string
+
+const (
+	RewardTitle    RewardKind = "title"
+	RewardBadge    RewardKind = "badge"
+	RewardAttempts RewardKind = "attempts"
+	RewardItem     RewardKind = "item"
+)
+
+// Reward is a non-EXP prize. Value holds the title/badge/item name;
+// Amount is used by attempts.
+type Reward struct {
+	Kind   RewardKind `json:"kind"`
+	Value  string     `json:"value,omitempty"`
+	Amount int        `json:"amount,omitempty"`
+}
+
+func (r Reward) Label() string {
+	switch r.Kind {
+	case RewardTitle:
+		return "Титул «" + r.Value + "»"
+	case RewardBadge:
+		return "Значок " + r.Value
+	case RewardAttempts:
+		return fmt.Sprintf("+%d попыт. боя", r.Amount)
+	case RewardItem:
+		return "Предмет: " + r.Value
+	default:
+		return string(r.Kind)
+	}
+}
+
+// GrantedReward is a reward that was given to a character, with its source.
+type GrantedReward struct {
+	ID           int64
+	CharID       int64
+	ExpeditionID int64
+	RunID        int64
+	Reward       Reward
+	GrantedAt    time.Time
+}
+
 // --- Battle Rewards ---
 
 type BattleReward struct {
@@ -508,9 +1123,15 @@ type BattleReward struct {
 	EnemyID   int64
 	Title     string
 	Badge     string
+	Cosmetic  string
 	AwardedAt time.Time
 }
 
+// IsEmpty reports whether the reward grants nothing.
+func (r BattleReward) IsEmpty() bool {
+	return r.Title == "" && r.Badge == "" && r.Cosmetic == ""
+}
+
 type DefeatedEnemy struct {
 	EnemyID     int64
 	Name        string
@@ -519,4 +1140,8 @@ type DefeatedEnemy struct {
 	Zone        int
 	IsBoss      bool
 	DefeatedAt  *time.Time
+	// First-win reward, empty when the victory predates rewards.
+	RewardTitle    string
+	RewardBadge    string
+	RewardCosmetic string
 }
@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"solo-leveling/internal/game/catalog"
+)
+
+// StatChange is one enemy whose HP or attack differs after tuning.
+type StatChange struct {
+	EnemyName string
+	OldHP     int
+	OldAttack int
+	NewHP     int
+	NewAttack int
+}
+
+// TuneDiff lists the enemies whose stats differ between base and tuned,
+// which must come from the same catalog in the same order.
+func TuneDiff(base, tuned []EnemyDef) []StatChange {
+	var changes []StatChange
+	for i := range base {
+		if i >= len(tuned) {
+			break
+		}
+		if base[i].HP == tuned[i].HP && base[i].Attack == tuned[i].Attack {
+			continue
+		}
+		changes = append(changes, StatChange{
+			EnemyName: base[i].Name,
+			OldHP:     base[i].HP,
+			OldAttack: base[i].Attack,
+			NewHP:     tuned[i].HP,
+			NewAttack: tuned[i].Attack,
+		})
+	}
+	return changes
+}
+
+// TuneDiffPreview formats the changes --apply is about to write.
+func TuneDiffPreview(changes []StatChange) string {
+	var sb strings.Builder
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	sb.WriteString("  APPLY PREVIEW\n")
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if len(changes) == 0 {
+		sb.WriteString("  No stat changes.\n\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("  %-30s %13s %13s\n", "Enemy", "HP", "ATK"))
+	sb.WriteString(fmt.Sprintf("  %s\n", strings.Repeat("-", 58)))
+	for _, c := range changes {
+		sb.WriteString(fmt.Sprintf("  %-30s %5d → %-5d %5d → %-5d\n",
+			c.EnemyName, c.OldHP, c.NewHP, c.OldAttack, c.NewAttack))
+	}
+	sb.WriteString(fmt.Sprintf("\n  %d enemies changed.\n\n", len(changes)))
+	return sb.String()
+}
+
+// ApplyTuning writes the tuned HP/ATK into the catalog file in dir, together
+// with the seed and options that produced them. The previous catalog is kept
+// as a backup whose path is returned.
+func ApplyTuning(dir string, tuned []EnemyDef, opts AutoTuneOptions, now time.Time) (string, error) {
+	file, err := catalog.ReadFile(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range tuned {
+		if !file.SetStats(e.Name, e.HP, e.Attack) {
+			return "", fmt.Errorf("enemy %q is not in the catalog", e.Name)
+		}
+	}
+	file.Tuning = &catalog.Tuning{
+		Seed:        opts.Seed,
+		RunsPerEval: opts.RunsPerEval,
+		Iterations:  opts.Iterations,
+		MinPower:    opts.MinPower,
+		MaxPower:    opts.MaxPower,
+		AppliedAt:   now.Format(time.RFC3339),
+	}
+	return file.Save(dir)
+}
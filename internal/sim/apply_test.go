@@ -0,0 +1,60 @@
+package sim
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"solo-leveling/internal/game/catalog"
+)
+
+func TestApplyTuningWritesOverrideWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	base, err := LoadPresetEnemies(dir)
+	if err != nil {
+		t.Fatalf("load catalog: %v", err)
+	}
+	tuned := make([]EnemyDef, len(base))
+	copy(tuned, base)
+	tuned[0].HP += 17
+	tuned[0].Attack += 3
+
+	changes := TuneDiff(base, tuned)
+	if len(changes) != 1 || changes[0].EnemyName != base[0].Name || changes[0].NewHP != base[0].HP+17 {
+		t.Fatalf("unexpected diff: %+v", changes)
+	}
+
+	opts := DefaultAutoTuneOptions(42)
+	backup, err := ApplyTuning(dir, tuned, opts, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	live, err := LoadPresetEnemies(dir)
+	if err != nil {
+		t.Fatalf("reload catalog: %v", err)
+	}
+	if live[0].HP != tuned[0].HP || live[0].Attack != tuned[0].Attack || live[1].HP != base[1].HP {
+		t.Fatalf("override does not hold tuned stats: %+v", live[0])
+	}
+	file, err := catalog.ReadFile(dir)
+	if err != nil {
+		t.Fatalf("read catalog: %v", err)
+	}
+	if file.Tuning == nil || file.Tuning.Seed != 42 || file.Tuning.RunsPerEval != opts.RunsPerEval {
+		t.Fatalf("tuning run not recorded: %+v", file.Tuning)
+	}
+
+	saved, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if filepath.Dir(backup) != dir {
+		t.Fatalf("backup outside data dir: %s", backup)
+	}
+	enemies, err := catalog.Parse(saved)
+	if err != nil || enemies[0].HP != base[0].HP {
+		t.Fatalf("backup should hold the previous catalog: %v", err)
+	}
+}
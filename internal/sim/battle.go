@@ -3,6 +3,8 @@ package sim
 import (
 	"math"
 	"math/rand"
+
+	"solo-leveling/internal/game/combat/formulas"
 )
 
 // BattleOutcome represents a single simulated battle result.
@@ -13,6 +15,7 @@ type BattleOutcome struct {
 	DamageTaken int
 	Crits       int
 	Accuracy    float64
+	EnemyHPLeft int
 }
 
 // SimulateBattle runs one complete battle between player stats and an enemy.
@@ -21,53 +24,52 @@ func SimulateBattle(
 	enemy EnemyDef,
 	rng *rand.Rand,
 ) BattleOutcome {
-	playerLevel := PlayerCombatLevel(str, sta)
-	enemyScale := EnemyScaleFactor(enemy, playerLevel)
-
-	playerHP := PlayerHP(sta)
-	enemyHP := int(math.Round(float64(enemy.HP) * enemyScale))
-	if enemyHP < 1 {
-		enemyHP = 1
+	seed := rng.Int63()
+	accuracy := func(int) float64 {
+		return SimulatedAccuracy(intStat, rng.Float64)
 	}
-	enemyAttack := int(math.Round(float64(enemy.Attack) * enemyScale))
-	if enemyAttack < 1 {
-		enemyAttack = 1
+	return SimulateSeededBattle(str, agi, sta, enemy, seed, accuracy)
+}
+
+// SimulateSeededBattle replays a battle the way the engine resolves it: round
+// N draws from formulas.RoundSource(seed, N), and accuracy gives the player's
+// result for each round. Bosses fight without their scripted phases.
+func SimulateSeededBattle(
+	str, agi, sta int,
+	enemy EnemyDef,
+	seed int64,
+	accuracy func(round int) float64,
+) BattleOutcome {
+	playerHP := formulas.PlayerHP(sta)
+	enemyHP := enemy.HP
+	regen := 0
+	if formulas.HasRegen(enemy.Role, enemy.IsBoss) {
+		regen = formulas.Regen(enemy.HP)
 	}
 
 	totalDamageDealt := 0
 	totalDamageTaken := 0
 	totalCrits := 0
-	totalHits := 0
-	totalShown := 0
+	totalAcc := 0.0
 	round := 0
 
-	rngFloat := func() float64 { return rng.Float64() }
-
 	for playerHP > 0 && enemyHP > 0 {
 		round++
-		accuracy := SimulatedAccuracy(intStat, rngFloat)
-		totalShown += 100
-		totalHits += int(math.Round(accuracy * 100.0))
+		acc := accuracy(round)
+		totalAcc += acc
 
-		damage, isCrit := ComputePlayerDamage(str, agi, accuracy, rngFloat)
-		if isCrit {
+		exchange := formulas.ResolveRound(str, agi, sta, enemy.Attack, acc, formulas.RoundSource(seed, round))
+		if exchange.Crit {
 			totalCrits++
 		}
-		enemyHP -= damage
-		totalDamageDealt += damage
+		enemyHP -= exchange.PlayerDamage
+		playerHP -= exchange.EnemyDamage
+		totalDamageDealt += exchange.PlayerDamage
+		totalDamageTaken += exchange.EnemyDamage
 
-		if enemyHP <= 0 {
-			break
+		if regen > 0 && enemyHP > 0 && playerHP > 0 {
+			enemyHP = min(enemyHP+regen, enemy.HP)
 		}
-
-		eDmg := ComputeEnemyDamage(enemyAttack, sta, rngFloat)
-		playerHP -= eDmg
-		totalDamageTaken += eDmg
-	}
-
-	overallAcc := 0.0
-	if totalShown > 0 {
-		overallAcc = float64(totalHits) / float64(totalShown)
 	}
 
 	return BattleOutcome{
@@ -76,7 +78,8 @@ func SimulateBattle(
 		DamageDealt: totalDamageDealt,
 		DamageTaken: totalDamageTaken,
 		Crits:       totalCrits,
-		Accuracy:    overallAcc,
+		Accuracy:    totalAcc / float64(round),
+		EnemyHPLeft: max(enemyHP, 0),
 	}
 }
 
@@ -139,21 +142,6 @@ func StatSweep(
 	rng *rand.Rand,
 ) []StatSweepResult {
 	results := make([]StatSweepResult, 0, maxVal+1)
-	baselineLevel := PlayerCombatLevel(fixedSTR, fixedSTA)
-	baselineScale := EnemyScaleFactor(enemy, baselineLevel)
-	sweepEnemy := enemy
-	// Sweep is meant to isolate stat impact, so keep enemy baseline fixed
-	// at the reference player level.
-	sweepEnemy.HP = int(math.Round(float64(sweepEnemy.HP) * baselineScale))
-	if sweepEnemy.HP < 1 {
-		sweepEnemy.HP = 1
-	}
-	sweepEnemy.Attack = int(math.Round(float64(sweepEnemy.Attack) * baselineScale))
-	if sweepEnemy.Attack < 1 {
-		sweepEnemy.Attack = 1
-	}
-	sweepEnemy.ExpectedMinLevel = 0
-	sweepEnemy.ExpectedMaxLevel = 0
 
 	for v := 0; v <= maxVal; v++ {
 		str, agi, intS, sta := fixedSTR, fixedAGI, fixedINT, fixedSTA
@@ -168,7 +156,7 @@ func StatSweep(
 			sta = v
 		}
 
-		mc := MonteCarloAnalysis(str, agi, intS, sta, sweepEnemy, runsPerPoint, rng)
+		mc := MonteCarloAnalysis(str, agi, intS, sta, enemy, runsPerPoint, rng)
 
 		results = append(results, StatSweepResult{
 			StatName:  statName,
@@ -11,8 +11,6 @@ func TestMonteCarloAnalysis_HigherINTImprovesWinRate(t *testing.T) {
 		Rank:   "B",
 		HP:     280,
 		Attack: 24,
-		AGI:    12,
-		INT:    14,
 	}
 
 	const runs = 2000
@@ -2,9 +2,14 @@ package sim
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
+
+	"solo-leveling/internal/config"
+	"solo-leveling/internal/game/catalog"
 )
 
 // RunCLI checks os.Args for --simulate and runs the simulator.
@@ -35,11 +40,14 @@ func RunCLI() bool {
 // RunCLIAutoTune runs binary-search enemy auto-tuning.
 // Usage:
 //
-//	--simulate-tune [seed] [runsPerEval] [iterations]
-//	--simulate-autotune [seed] [runsPerEval] [iterations]
+//	--simulate-tune [seed] [runsPerEval] [iterations] [--apply [--yes]]
+//	--simulate-autotune [seed] [runsPerEval] [iterations] [--apply [--yes]]
 //
 // The first prints only tuning summary.
 // The second prints tuning summary + full simulation report on tuned enemies.
+// With --apply the live catalog is tuned and the diff is previewed; only with
+// --yes as well is the result written to the catalog override in the data
+// directory, which the game picks up on next start.
 func RunCLIAutoTune() bool {
 	args := os.Args[1:]
 	if len(args) == 0 {
@@ -51,6 +59,20 @@ func RunCLIAutoTune() bool {
 
 	fullReport := args[0] == "--simulate-autotune"
 
+	apply, confirmed := false, false
+	positional := make([]string, 0, len(args))
+	for _, a := range args {
+		switch a {
+		case "--apply":
+			apply = true
+		case "--yes":
+			confirmed = true
+		default:
+			positional = append(positional, a)
+		}
+	}
+	args = positional
+
 	seed := time.Now().UnixNano()
 	if len(args) >= 2 {
 		if n, err := strconv.ParseInt(args[1], 10, 64); err == nil {
@@ -71,12 +93,40 @@ func RunCLIAutoTune() bool {
 	}
 
 	base := GetPresetEnemies()
+	dataDir := ""
+	if apply {
+		dir, err := config.DataDir()
+		if err != nil {
+			log.Fatalf("Failed to locate data dir: %v", err)
+		}
+		dataDir = dir
+		if base, err = LoadPresetEnemies(dataDir); err != nil {
+			log.Fatalf("Failed to load enemy catalog: %v", err)
+		}
+	}
+
 	tuned, results := AutoTuneEnemies(base, opts)
 	fmt.Print(AutoTuneSummary(results))
 
 	if fullReport {
 		fmt.Println(FullReportWithEnemies(seed, tuned))
 	}
+
+	if apply {
+		fmt.Print(TuneDiffPreview(TuneDiff(base, tuned)))
+		if !confirmed {
+			fmt.Println("Dry run: nothing written. Re-run with --apply --yes to write the tuned catalog.")
+			return true
+		}
+		backup, err := ApplyTuning(dataDir, tuned, opts, time.Now())
+		if err != nil {
+			log.Fatalf("Failed to apply tuning: %v", err)
+		}
+		fmt.Printf("Tuned catalog written to %s (seed %d, %d runs/eval, %d iterations).\n",
+			filepath.Join(dataDir, catalog.OverrideFile), opts.Seed, opts.RunsPerEval, opts.Iterations)
+		fmt.Printf("Previous catalog saved to %s.\n", backup)
+		fmt.Println("Run with --seed-enemies or start the game to load the new stats.")
+	}
 	return true
 }
 
@@ -113,6 +163,7 @@ func RunCLICompact() bool {
 		Days:      days,
 		Seed:      time.Now().UnixNano(),
 		Archetype: archetypes[archIdx],
+		Attempts:  GameAttemptRules(),
 		Enemies:   GetPresetEnemies(),
 	}
 
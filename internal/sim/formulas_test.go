@@ -1,22 +1,10 @@
 package sim
 
 import (
-	"math"
 	"testing"
-)
-
-func nearlyEqual(got, want, eps float64) bool {
-	return math.Abs(got-want) <= eps
-}
 
-func seq(values ...float64) func() float64 {
-	i := 0
-	return func() float64 {
-		v := values[i%len(values)]
-		i++
-		return v
-	}
-}
+	"solo-leveling/internal/models"
+)
 
 func TestSimulatedAccuracy_AlwaysAboveSixtyPercent(t *testing.T) {
 	for _, intStat := range []int{0, 5, 20, 50} {
@@ -43,44 +31,25 @@ func TestSimulatedAccuracy_IncreasesWithINT(t *testing.T) {
 	}
 }
 
-func TestComputePlayerDamage_ScalesWithAccuracy(t *testing.T) {
-	low, _ := ComputePlayerDamage(20, 10, 0.61, seq(0.5, 0.99))
-	high, _ := ComputePlayerDamage(20, 10, 0.90, seq(0.5, 0.99))
-	if high <= low {
-		t.Fatalf("higher accuracy must increase damage, got low=%d high=%d", low, high)
-	}
-}
-
-func TestCritChanceAndCritMultiplier(t *testing.T) {
-	if !nearlyEqual(CritChance(), 0.10, 1e-9) {
-		t.Fatalf("crit chance should be fixed at 0.10, got %f", CritChance())
-	}
+func TestAwardAttemptsBanksOverflow(t *testing.T) {
+	player := NewPlayerState()
+	rules := &models.AttemptRules{ReserveCap: 3}
 
-	low := CritDamageMultiplier(0)
-	high := CritDamageMultiplier(30)
-	if high <= low {
-		t.Fatalf("AGI should increase crit damage multiplier, got low=%f high=%f", low, high)
+	AwardAttempts(player, MaxAttempts+5, rules)
+	if player.Attempts != MaxAttempts || player.AttemptReserve != 3 {
+		t.Fatalf("expected %d attempts and 3 in reserve, got %d/%d", MaxAttempts, player.Attempts, player.AttemptReserve)
 	}
-	if !nearlyEqual(CritDamageMultiplier(1000), 2.0, 1e-9) {
-		t.Fatalf("crit damage multiplier should cap at 2.0, got %f", CritDamageMultiplier(1000))
+	SpendAttempt(player)
+	if player.Attempts != MaxAttempts || player.AttemptReserve != 2 {
+		t.Fatalf("expected pool refilled from reserve, got %d/%d", player.Attempts, player.AttemptReserve)
 	}
 }
 
-func TestDamageMitigation_ExpectedBounds(t *testing.T) {
-	if !nearlyEqual(DamageMitigation(0), 0.95, 1e-9) {
-		t.Fatalf("mitigation at STA=0 should clamp to 0.95, got %f", DamageMitigation(0))
-	}
-	if !nearlyEqual(DamageMitigation(40), 0.5, 1e-9) {
-		t.Fatalf("mitigation at STA=40 should be 0.5, got %f", DamageMitigation(40))
-	}
-	if !nearlyEqual(DamageMitigation(999), 0.35, 1e-9) {
-		t.Fatalf("mitigation lower bound should clamp to 0.35, got %f", DamageMitigation(999))
-	}
-}
+func TestAwardAttemptsWithoutRulesDropsOverflow(t *testing.T) {
+	player := NewPlayerState()
 
-func TestCellsToShow_NoIntegerDivisionStepAtTwoINT(t *testing.T) {
-	got := CellsToShow("A", false, 2)
-	if got != 13 {
-		t.Fatalf("expected smooth reduction at INT=2 (13 cells), got %d", got)
+	AwardAttempts(player, MaxAttempts+5, nil)
+	if player.Attempts != MaxAttempts || player.AttemptReserve != 0 {
+		t.Fatalf("expected %d attempts and no reserve, got %d/%d", MaxAttempts, player.Attempts, player.AttemptReserve)
 	}
 }
@@ -44,13 +44,6 @@ func SimulateQuest(player *PlayerState, arch Archetype, rng *rand.Rand) (int, st
 	// Award stat EXP and handle level-ups
 	addStatEXP(player, stat, statEXP)
 
-	// Award battle attempts
-	attempts := AttemptsForQuestEXP(questEXP)
-	player.Attempts += attempts
-	if player.Attempts > MaxAttempts {
-		player.Attempts = MaxAttempts
-	}
-
 	// Tracking
 	player.TotalQuestsCompleted++
 	player.TotalEXPEarned += statEXP
@@ -116,6 +116,7 @@ func FullReportWithEnemies(seed int64, enemies []EnemyDef) string {
 				Days:      days,
 				Seed:      seed,
 				Archetype: arch,
+				Attempts:  GameAttemptRules(),
 				Enemies:   enemies,
 			}
 
@@ -165,6 +166,7 @@ func FullReportWithEnemies(seed int64, enemies []EnemyDef) string {
 			Days:      365,
 			Seed:      seed,
 			Archetype: arch,
+			Attempts:  GameAttemptRules(),
 			Enemies:   enemies,
 		}
 		est := EstimateFullClear(cfg, 10)
@@ -1,6 +1,10 @@
 package sim
 
-import "math/rand"
+import (
+	"math/rand"
+
+	"solo-leveling/internal/models"
+)
 
 type FullClearEstimate struct {
 	Runs        int
@@ -11,16 +15,22 @@ type FullClearEstimate struct {
 }
 
 // SimulateDay runs one day of gameplay: quests + battles.
-func SimulateDay(player *PlayerState, arch Archetype, enemies []EnemyDef, rng *rand.Rand) DaySnapshot {
+func SimulateDay(player *PlayerState, arch Archetype, enemies []EnemyDef, rules *models.AttemptRules, rng *rand.Rand) DaySnapshot {
 	player.DayNumber++
 	questsToday := 0
 	expToday := 0
 	battlesToday := 0
 	winsToday := 0
 
+	// Daily free attempts
+	if rules != nil && rules.DailyFree > 0 {
+		AwardAttempts(player, rules.DailyFree, rules)
+	}
+
 	// Complete quests
 	for q := 0; q < arch.QuestsPerDay; q++ {
 		questEXP, _ := SimulateQuest(player, arch, rng)
+		AwardAttempts(player, AttemptsForQuestEXP(questEXP), rules)
 		questsToday++
 		expToday += questEXP
 	}
@@ -40,7 +50,7 @@ func SimulateDay(player *PlayerState, arch Archetype, enemies []EnemyDef, rng *r
 				break // all enemies defeated
 			}
 
-			player.Attempts--
+			SpendAttempt(player)
 			player.TotalBattles++
 			battlesToday++
 
@@ -184,7 +194,7 @@ func RunProgression(cfg SimConfig) ([]DaySnapshot, *PlayerState) {
 	snapshots := make([]DaySnapshot, 0, cfg.Days)
 
 	for day := 1; day <= cfg.Days; day++ {
-		snap := SimulateDay(player, cfg.Archetype, enemies, rng)
+		snap := SimulateDay(player, cfg.Archetype, enemies, cfg.Attempts, rng)
 		snapshots = append(snapshots, snap)
 	}
 
@@ -335,7 +345,7 @@ func estimateFullClearDay(cfg SimConfig) int {
 	}
 
 	for day := 1; day <= cfg.Days; day++ {
-		SimulateDay(player, cfg.Archetype, enemies, rng)
+		SimulateDay(player, cfg.Archetype, enemies, cfg.Attempts, rng)
 		if len(player.DefeatedEnemyIDs) >= total {
 			return day
 		}
@@ -1,6 +1,11 @@
 package sim
 
-import "math/rand"
+import (
+	"math/rand"
+
+	"solo-leveling/internal/config"
+	"solo-leveling/internal/models"
+)
 
 // PlayerState holds a simulated player's full state.
 type PlayerState struct {
@@ -16,8 +21,9 @@ type PlayerState struct {
 	INTEXP int
 	STAEXP int
 
-	// Battle attempts available
-	Attempts int
+	// Battle attempts available, plus the overflow kept in reserve
+	Attempts       int
+	AttemptReserve int
 
 	// Zone progression
 	CurrentZone       int
@@ -79,8 +85,6 @@ type EnemyDef struct {
 	Level  int
 	HP     int
 	Attack int
-	AGI    int
-	INT    int
 	// ExpectedMinLevel/ExpectedMaxLevel define the intended player-level window.
 	ExpectedMinLevel int
 	ExpectedMaxLevel int
@@ -122,7 +126,18 @@ type SimConfig struct {
 	Archetype      Archetype
 	Enemies        []EnemyDef
 	Verbose        bool
-	MonteCarloRuns int // for battle MC analysis; 0 = skip
+	MonteCarloRuns int                  // for battle MC analysis; 0 = skip
+	Attempts       *models.AttemptRules // nil: no daily attempt and no reserve
+}
+
+// GameAttemptRules returns the attempt rules the game starts with: the default
+// rules when the BattleAttempts feature is on, nil otherwise.
+func GameAttemptRules() *models.AttemptRules {
+	if !config.DefaultFeatures().BattleAttempts {
+		return nil
+	}
+	rules := models.DefaultAttemptRules()
+	return &rules
 }
 
 // SimRNG wraps *rand.Rand to satisfy the memory.RNG interface.
@@ -16,8 +16,8 @@ import (
 
 	"solo-leveling/internal/config"
 	"solo-leveling/internal/game"
+	"solo-leveling/internal/game/combat"
 	"solo-leveling/internal/game/combat/boss"
-	"solo-leveling/internal/game/combat/memory"
 	"solo-leveling/internal/models"
 	"solo-leveling/internal/ui/components"
 	"solo-leveling/internal/ui/tabs"
@@ -84,6 +84,22 @@ func (a *App) Run() {
 		StartBattle: func(enemy models.Enemy) {
 			a.startBattle(enemy)
 		},
+		StartDailyChallenge: func() {
+			a.offerSavedBattle(a.beginDailyChallenge)
+		},
+		StartPractice: func(enemyID int64) {
+			a.offerSavedBattle(func() {
+				a.beginPractice(a.engine.StartPractice(enemyID))
+			})
+		},
+		StartCustomPractice: func(grid, cells int) {
+			a.offerSavedBattle(func() {
+				a.beginPractice(a.engine.StartCustomPractice(grid, cells))
+			})
+		},
+		ShowBattleReplay: func(record models.BattleRecord) {
+			a.showBattleReplay(record)
+		},
 	}
 
 	if components.T().HeaderUppercase {
@@ -96,7 +112,9 @@ func (a *App) Run() {
 
 	content := a.buildMainLayout()
 	a.window.SetContent(content)
-	a.window.ShowAndRun()
+	a.window.Show()
+	a.offerSavedBattle(nil)
+	a.app.Run()
 }
 
 func (a *App) buildMainMenu() *fyne.MainMenu {
@@ -140,6 +158,9 @@ func (a *App) buildMainLayout() fyne.CanvasObject {
 		achievementsTab := container.NewTabItem("Достижения", tabs.BuildAchievements(a.tabsCtx))
 		expeditionsTab := container.NewTabItem("Экспедиции", tabs.BuildExpeditions(a.tabsCtx))
 		tabItems := []*container.TabItem{todayTab, questsTab, progressTab, achievementsTab, expeditionsTab}
+		if a.features.Combat {
+			tabItems = append(tabItems, container.NewTabItem("Снаряжение", tabs.BuildEquipment(a.tabsCtx)))
+		}
 		appTabs = container.NewAppTabs(tabItems...)
 	} else {
 		charTab := container.NewTabItem("Охотник", tabs.BuildToday(a.tabsCtx))
@@ -149,6 +170,9 @@ func (a *App) buildMainLayout() fyne.CanvasObject {
 		achievementsTab := container.NewTabItem("Достижения", tabs.BuildAchievements(a.tabsCtx))
 
 		tabItems := []*container.TabItem{charTab, questsTab, expeditionsTab, statsTab, achievementsTab}
+		if a.features.Combat {
+			tabItems = append(tabItems, container.NewTabItem("Снаряжение", tabs.BuildEquipment(a.tabsCtx)))
+		}
 		tabItems = append(tabItems,
 			container.NewTabItem("История", a.buildHistoryTab()),
 		)
@@ -260,6 +284,7 @@ func (a *App) refreshAll() {
 	a.refreshStatsPanel()
 	a.refreshAchievementsPanel()
 	a.refreshExpeditionsPanel()
+	a.refreshEquipmentPanel()
 }
 
 // ================================================================
@@ -352,6 +377,10 @@ func (a *App) refreshAchievementsPanel() {
 	tabs.RefreshAchievements(a.tabsCtx)
 }
 
+func (a *App) refreshEquipmentPanel() {
+	tabs.RefreshEquipment(a.tabsCtx)
+}
+
 // ================================================================
 // Expeditions Tab
 // ================================================================
@@ -365,7 +394,55 @@ func (a *App) refreshExpeditionsPanel() {
 	tabs.RefreshExpeditions(a.tabsCtx)
 }
 
+// offerSavedBattle asks whether to resume or forfeit an unfinished fight.
+// onNone runs when there is no saved fight or once it has been forfeited.
+func (a *App) offerSavedBattle(onNone func()) {
+	if onNone == nil {
+		onNone = func() {}
+	}
+	saved, err := a.engine.GetSavedBattle()
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	if saved == nil {
+		onNone()
+		return
+	}
+
+	msg := fmt.Sprintf("Бой с «%s» прерван на раунде %d.\nПродолжить бой или сдаться? Сдача засчитывается как поражение.",
+		saved.EnemyName(), saved.Round())
+	confirm := dialog.NewConfirm("Незавершённый бой", msg, func(resume bool) {
+		if resume {
+			if saved.Boss != nil {
+				a.currentBoss = saved.Boss
+				a.showBossScreen()
+				return
+			}
+			a.currentBattle = saved.Battle
+			a.showBattleScreen()
+			return
+		}
+		if _, err := a.engine.ForfeitSavedBattle(); err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+		a.refreshCharacterPanel()
+		a.refreshStatsPanel()
+		onNone()
+	}, a.window)
+	confirm.SetConfirmText("Продолжить")
+	confirm.SetDismissText("Сдаться")
+	confirm.Show()
+}
+
 func (a *App) startBattle(enemy models.Enemy) {
+	a.offerSavedBattle(func() {
+		a.beginBattle(enemy)
+	})
+}
+
+func (a *App) beginBattle(enemy models.Enemy) {
 	if enemy.Type == models.EnemyBoss {
 		state, err := a.engine.StartBossBattle(enemy.ID)
 		if err != nil {
@@ -385,6 +462,25 @@ func (a *App) startBattle(enemy models.Enemy) {
 	a.showBattleScreen()
 }
 
+func (a *App) beginDailyChallenge() {
+	state, err := a.engine.StartDailyChallenge()
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	a.currentBattle = state
+	a.showBattleScreen()
+}
+
+func (a *App) beginPractice(state *models.BattleState, err error) {
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	a.currentBattle = state
+	a.showBattleScreen()
+}
+
 func (a *App) showBattleScreen() {
 	state := a.currentBattle
 
@@ -396,12 +492,6 @@ func (a *App) showBattleScreen() {
 	centerRef := container.NewVBox()
 	bottomRef := container.NewVBox()
 
-	var cells []*battleCell
-	var primaryStatus *canvas.Text
-	var secondaryStatus *canvas.Text
-	var confirmBtn *widget.Button
-	var resetBtn *widget.Button
-	var surrenderBtn *widget.Button
 	var resolved bool
 	var resolvedRecord *models.BattleRecord
 	var resolvedErr error
@@ -584,6 +674,7 @@ func (a *App) showBattleScreen() {
 					components.MakeLabel(fmt.Sprintf("Точность: %.1f%%  |  Криты: %d  |  Раундов: %d", resolvedRecord.Accuracy, state.TotalCrits, state.Round), t.TextSecondary),
 					components.MakeLabel(fmt.Sprintf("Урон нанесён: %d  |  Урон получен: %d", state.DamageDealt, state.DamageTaken), t.TextSecondary),
 				)
+				statsItems = append(statsItems, buildFirstWinRewardItems(*resolvedRecord)...)
 			}
 
 			statsBox := container.NewVBox(statsItems...)
@@ -611,199 +702,25 @@ func (a *App) showBattleScreen() {
 		}
 
 		// --- Active round ---
-		choices := make([]int, 0, state.CellsToShow)
-		selected := make(map[int]struct{}, state.CellsToShow)
-		shown := make(map[int]struct{}, len(state.ShownCells))
-		for _, idx := range state.ShownCells {
-			shown[idx] = struct{}{}
-		}
-		acceptingInput := false
-		roundSubmitted := false
-
 		// Show round damage info if not first round
 		if state.Round > 1 && state.LastRoundDamage > 0 {
 			showDamageOverlay(topRef, state.LastRoundDamage, state.LastRoundCrit)
 		}
 
-		primaryStatus = components.MakeLabel("Запомни подсвеченные клетки", components.T().Gold)
-		primaryStatus.TextSize = components.TextBodyLG
-		primaryStatus.TextStyle = fyne.TextStyle{Bold: true}
-		secondaryStatus = components.MakeLabel(
-			fmt.Sprintf("Сетка %dx%d • Выбрано 0/%d", state.GridSize, state.GridSize, state.CellsToShow),
-			components.T().TextSecondary,
-		)
-		secondaryStatus.TextSize = components.TextNumberSM
-
-		cellCount := state.GridSize * state.GridSize
-		cells = make([]*battleCell, cellCount)
-		var gridCells []fyne.CanvasObject
-		cellSize := cellSizeForGrid(battleWindow, state.GridSize)
-		for i := 0; i < cellCount; i++ {
-			cell := newBattleCell(cellSize)
-			cell.Disable()
-			cell.SetState(battleCellStateIdle)
-			cells[i] = cell
-			gridCells = append(gridCells, cell)
-		}
-
-		gridContainer := container.NewGridWithColumns(state.GridSize, gridCells...)
-		fieldCard := components.MakeCard(container.NewPadded(gridContainer))
-
-		logWidget := buildRoundLog()
-		gridCol := container.NewVBox(
-			container.NewCenter(primaryStatus),
-			container.NewCenter(fieldCard),
-			container.NewCenter(secondaryStatus),
-			logWidget,
+		a.mountMinigameRound(battleWindow, centerRef, bottomRef, game.BattleChallenge(state), buildRoundLog(),
+			func(input []int) error { return a.engine.ProcessRound(state, input) },
+			rebuildScreen,
+			func() {
+				state.BattleOver = true
+				state.Result = models.BattleLose
+				state.PlayerHP = 0
+				rebuildScreen()
+			},
 		)
-		centerContent := container.NewBorder(nil, nil, nil, nil, gridCol)
-		centerRef.Add(centerContent)
-
-		updateSelectionStatus := func() {
-			secondaryStatus.Text = fmt.Sprintf("Сетка %dx%d • Выбрано %d/%d", state.GridSize, state.GridSize, len(choices), state.CellsToShow)
-			secondaryStatus.Refresh()
-		}
-
-		refreshAllCells := func() {
-			for _, cell := range cells {
-				cell.Refresh()
-			}
-		}
-
-		showRoundResult := func() {
-			for i := 0; i < cellCount; i++ {
-				_, wasSelected := selected[i]
-				_, wasShown := shown[i]
-				switch {
-				case wasSelected && wasShown:
-					cells[i].state = battleCellStateResultCorrect
-				case wasSelected && !wasShown:
-					cells[i].state = battleCellStateResultWrong
-				case wasShown:
-					cells[i].state = battleCellStateShown
-				default:
-					cells[i].state = battleCellStateIdle
-				}
-				cells[i].disabled = true
-			}
-			refreshAllCells()
-		}
-
-		submitRound := func() {
-			if !acceptingInput || roundSubmitted {
-				return
-			}
-			acceptingInput = false
-			roundSubmitted = true
-			confirmBtn.Disable()
-			resetBtn.Disable()
-
-			showRoundResult()
-			accuracy := memory.ComputeAccuracy(state.ShownCells, choices)
-			primaryStatus.Text = fmt.Sprintf("Точность %.0f%% • расчёт урона...", accuracy*100)
-			primaryStatus.Color = components.T().Gold
-			primaryStatus.Refresh()
-
-			roundChoices := append([]int(nil), choices...)
-			go func() {
-				time.Sleep(450 * time.Millisecond)
-				err := a.engine.ProcessRound(state, roundChoices)
-				runOnMain(func() {
-					if err != nil {
-						dialog.ShowError(err, battleWindow)
-						return
-					}
-					rebuildScreen()
-				})
-			}()
-		}
-
-		confirmBtn = widget.NewButtonWithIcon("Готово", theme.ConfirmIcon(), submitRound)
-		confirmBtn.Importance = widget.HighImportance
-		confirmBtn.Disable()
-
-		resetBtn = widget.NewButtonWithIcon("Сбросить выбор", theme.ViewRefreshIcon(), func() {
-			if !acceptingInput || roundSubmitted {
-				return
-			}
-			choices = choices[:0]
-			selected = make(map[int]struct{}, state.CellsToShow)
-			for _, c := range cells {
-				c.state = battleCellStateIdle
-			}
-			refreshAllCells()
-			updateSelectionStatus()
-			confirmBtn.Disable()
-		})
-		resetBtn.Importance = widget.MediumImportance
-		resetBtn.Disable()
-
-		surrenderBtn = widget.NewButtonWithIcon("Сдаться", theme.CancelIcon(), func() {
-			state.BattleOver = true
-			state.Result = models.BattleLose
-			state.PlayerHP = 0
-			rebuildScreen()
-		})
-		surrenderBtn.Importance = widget.DangerImportance
-
-		bottomRef.Add(container.NewHBox(confirmBtn, resetBtn, layout.NewSpacer(), surrenderBtn))
 
 		topRef.Refresh()
 		centerRef.Refresh()
 		bottomRef.Refresh()
-
-		showTimeMs, _ := a.engine.GetShowTimeMs(state.ShowTimeMs)
-		if showTimeMs <= 0 {
-			showTimeMs = 1000
-		}
-		go func() {
-			runOnMain(func() {
-				for _, idx := range state.ShownCells {
-					if idx >= 0 && idx < cellCount {
-						cells[idx].state = battleCellStateShown
-					}
-				}
-				refreshAllCells()
-			})
-
-			time.Sleep(time.Duration(showTimeMs) * time.Millisecond)
-
-			runOnMain(func() {
-				primaryStatus.Text = fmt.Sprintf("Выбери клетки: %d", state.CellsToShow)
-				primaryStatus.Color = components.T().Text
-				primaryStatus.Refresh()
-
-				for i := 0; i < cellCount; i++ {
-					idx := i
-					cells[idx].state = battleCellStateIdle
-					cells[idx].disabled = false
-					cells[idx].SetOnTapped(func() {
-						if !acceptingInput || roundSubmitted {
-							return
-						}
-						if _, exists := selected[idx]; exists {
-							return
-						}
-
-						selected[idx] = struct{}{}
-						choices = append(choices, idx)
-						cells[idx].SetState(battleCellStateSelected)
-						updateSelectionStatus()
-
-						if len(choices) >= state.CellsToShow {
-							submitRound()
-							return
-						}
-						confirmBtn.Enable()
-					})
-				}
-				refreshAllCells()
-
-				acceptingInput = true
-				resetBtn.Enable()
-				updateSelectionStatus()
-			})
-		}()
 	}
 
 	root := container.NewBorder(topRef, bottomRef, nil, nil, container.NewVScroll(centerRef))
@@ -823,25 +740,11 @@ func (a *App) showBossScreen() {
 	centerRef := container.NewVBox()
 	bottomRef := container.NewVBox()
 
-	var cells []*battleCell
-	var primaryStatus *canvas.Text
-	var secondaryStatus *canvas.Text
-	var confirmBtn *widget.Button
-	var resetBtn *widget.Button
-	var surrenderBtn *widget.Button
 	var resolved bool
 	var resolvedRecord *models.BattleRecord
 	var resolvedErr error
 	var roundLogBox *fyne.Container
 
-	runOnMain := func(fn func()) {
-		if d, ok := a.app.Driver().(interface{ RunOnMain(func()) }); ok {
-			d.RunOnMain(fn)
-			return
-		}
-		fn()
-	}
-
 	// --- VS panel builder ---
 	buildBossVSPanel := func() fyne.CanvasObject {
 		t := components.T()
@@ -872,13 +775,23 @@ func (a *App) showBossScreen() {
 		vsText.TextSize = 28
 		vsText.TextStyle = fyne.TextStyle{Bold: true}
 		vsText.Alignment = fyne.TextAlignCenter
-		phaseText := components.MakeLabel(phaseDisplay(state.Phase), t.Gold)
+		phaseName := phaseDisplay(state.Phase)
+		if name := state.CurrentPhaseName(); name != "" && state.Phase == boss.PhaseMemory {
+			phaseName = name
+		}
+		phaseText := components.MakeLabel(phaseName, t.Gold)
 		phaseText.TextSize = components.TextHeadingSM
 		phaseText.Alignment = fyne.TextAlignCenter
 		roundText := components.MakeLabel(fmt.Sprintf("Раунд %d", state.Round), t.TextSecondary)
 		roundText.TextSize = components.TextBodySM
 		roundText.Alignment = fyne.TextAlignCenter
-		vsSide := container.NewVBox(layout.NewSpacer(), container.NewCenter(vsText), container.NewCenter(phaseText), container.NewCenter(roundText), layout.NewSpacer())
+		vsItems := []fyne.CanvasObject{layout.NewSpacer(), container.NewCenter(vsText), container.NewCenter(phaseText), container.NewCenter(roundText)}
+		if state.Shield > 0 {
+			shieldText := components.MakeLabel(fmt.Sprintf("🛡 Щит: %d", state.Shield), t.Blue)
+			shieldText.TextSize = components.TextBodySM
+			vsItems = append(vsItems, container.NewCenter(shieldText))
+		}
+		vsSide := container.NewVBox(append(vsItems, layout.NewSpacer())...)
 
 		// Enemy (boss) side
 		bossIcon := canvas.NewText("👑", t.Gold)
@@ -996,6 +909,7 @@ func (a *App) showBossScreen() {
 					components.MakeLabel(fmt.Sprintf("Точность: %.1f%%  |  Криты: %d  |  Раундов: %d", resolvedRecord.Accuracy, state.TotalCrits, state.Round), t.TextSecondary),
 					components.MakeLabel(fmt.Sprintf("Урон нанесён: %d  |  Урон получен: %d", state.DamageDealt, state.DamageTaken), t.TextSecondary),
 				)
+				statsItems = append(statsItems, buildFirstWinRewardItems(*resolvedRecord)...)
 			}
 
 			statsBox := container.NewVBox(statsItems...)
@@ -1012,6 +926,7 @@ func (a *App) showBossScreen() {
 				battleWindow.Close()
 				a.refreshCharacterPanel()
 				a.refreshStatsPanel()
+				a.refreshEquipmentPanel()
 			})
 			closeBtn.Importance = widget.HighImportance
 			bottomRef.Add(container.NewHBox(layout.NewSpacer(), closeBtn, layout.NewSpacer()))
@@ -1023,192 +938,21 @@ func (a *App) showBossScreen() {
 		}
 
 		if state.Phase == boss.PhaseMemory {
-			choices := make([]int, 0, state.Memory.CellsToShow)
-			selected := make(map[int]struct{}, state.Memory.CellsToShow)
-			shown := make(map[int]struct{}, len(state.Memory.ShownCells))
-			for _, idx := range state.Memory.ShownCells {
-				shown[idx] = struct{}{}
-			}
-			acceptingInput := false
-			roundSubmitted := false
-
-			primaryStatus = components.MakeLabel("Запомни подсвеченные клетки", components.T().Gold)
-			primaryStatus.TextSize = components.TextBodyLG
-			primaryStatus.TextStyle = fyne.TextStyle{Bold: true}
-			secondaryStatus = components.MakeLabel(
-				fmt.Sprintf("Сетка %dx%d • Выбрано 0/%d", state.Memory.GridSize, state.Memory.GridSize, state.Memory.CellsToShow),
-				components.T().TextSecondary,
-			)
-			secondaryStatus.TextSize = components.TextNumberSM
-
-			cellCount := state.Memory.GridSize * state.Memory.GridSize
-			cells = make([]*battleCell, cellCount)
-			var gridCells []fyne.CanvasObject
-			cellSize := cellSizeForGrid(battleWindow, state.Memory.GridSize)
-			for i := 0; i < cellCount; i++ {
-				cell := newBattleCell(cellSize)
-				cell.Disable()
-				cell.SetState(battleCellStateIdle)
-				cells[i] = cell
-				gridCells = append(gridCells, cell)
-			}
-
-			gridContainer := container.NewGridWithColumns(state.Memory.GridSize, gridCells...)
-			fieldCard := components.MakeCard(container.NewPadded(gridContainer))
-			logWidget := buildBossRoundLog()
-
-			gridCol := container.NewVBox(
-				container.NewCenter(primaryStatus),
-				container.NewCenter(fieldCard),
-				container.NewCenter(secondaryStatus),
-				logWidget,
+			round := combat.GridRound(state.Memory.GridSize, state.Memory.ShownCells, state.Memory.ShowTimeMs)
+			round.Decoys = state.Memory.DecoyCells
+			a.mountMinigameRound(battleWindow, centerRef, bottomRef, round, buildBossRoundLog(),
+				func(input []int) error { return a.engine.ProcessBossMemory(state, input) },
+				rebuildScreen,
+				func() {
+					state.Phase = boss.PhaseLose
+					state.PlayerHP = 0
+					rebuildScreen()
+				},
 			)
-			centerRef.Add(gridCol)
-
-			updateSelectionStatus := func() {
-				secondaryStatus.Text = fmt.Sprintf("Сетка %dx%d • Выбрано %d/%d", state.Memory.GridSize, state.Memory.GridSize, len(choices), state.Memory.CellsToShow)
-				secondaryStatus.Refresh()
-			}
-
-			refreshAllCells := func() {
-				for _, cell := range cells {
-					cell.Refresh()
-				}
-			}
-
-			showRoundResult := func() {
-				for i := 0; i < cellCount; i++ {
-					_, wasSelected := selected[i]
-					_, wasShown := shown[i]
-					switch {
-					case wasSelected && wasShown:
-						cells[i].state = battleCellStateResultCorrect
-					case wasSelected && !wasShown:
-						cells[i].state = battleCellStateResultWrong
-					case wasShown:
-						cells[i].state = battleCellStateShown
-					default:
-						cells[i].state = battleCellStateIdle
-					}
-					cells[i].disabled = true
-				}
-				refreshAllCells()
-			}
-
-			submitRound := func() {
-				if !acceptingInput || roundSubmitted {
-					return
-				}
-				acceptingInput = false
-				roundSubmitted = true
-				confirmBtn.Disable()
-				resetBtn.Disable()
-				showRoundResult()
-
-				accuracy := memory.ComputeAccuracy(state.Memory.ShownCells, choices)
-				primaryStatus.Text = fmt.Sprintf("Точность %.0f%% • расчёт урона...", accuracy*100)
-				primaryStatus.Color = components.T().Gold
-				primaryStatus.Refresh()
-
-				roundChoices := append([]int(nil), choices...)
-				go func() {
-					time.Sleep(450 * time.Millisecond)
-					err := a.engine.ProcessBossMemory(state, roundChoices)
-					runOnMain(func() {
-						if err != nil {
-							dialog.ShowError(err, battleWindow)
-							return
-						}
-						rebuildScreen()
-					})
-				}()
-			}
-
-			confirmBtn = widget.NewButtonWithIcon("Готово", theme.ConfirmIcon(), submitRound)
-			confirmBtn.Importance = widget.HighImportance
-			confirmBtn.Disable()
-
-			resetBtn = widget.NewButtonWithIcon("Сбросить выбор", theme.ViewRefreshIcon(), func() {
-				if !acceptingInput || roundSubmitted {
-					return
-				}
-				choices = choices[:0]
-				selected = make(map[int]struct{}, state.Memory.CellsToShow)
-				for _, c := range cells {
-					c.state = battleCellStateIdle
-				}
-				refreshAllCells()
-				updateSelectionStatus()
-				confirmBtn.Disable()
-			})
-			resetBtn.Importance = widget.MediumImportance
-			resetBtn.Disable()
-
-			surrenderBtn = widget.NewButtonWithIcon("Сдаться", theme.CancelIcon(), func() {
-				state.Phase = boss.PhaseLose
-				state.PlayerHP = 0
-				rebuildScreen()
-			})
-			surrenderBtn.Importance = widget.DangerImportance
-
-			bottomRef.Add(container.NewHBox(confirmBtn, resetBtn, layout.NewSpacer(), surrenderBtn))
 
 			topRef.Refresh()
 			centerRef.Refresh()
 			bottomRef.Refresh()
-
-			showTimeMs, _ := a.engine.GetShowTimeMs(state.Memory.ShowTimeMs)
-			if showTimeMs <= 0 {
-				showTimeMs = 1000
-			}
-			go func() {
-				runOnMain(func() {
-					for _, idx := range state.Memory.ShownCells {
-						if idx >= 0 && idx < cellCount {
-							cells[idx].state = battleCellStateShown
-						}
-					}
-					refreshAllCells()
-				})
-
-				time.Sleep(time.Duration(showTimeMs) * time.Millisecond)
-
-				runOnMain(func() {
-					primaryStatus.Text = fmt.Sprintf("Выбери клетки: %d", state.Memory.CellsToShow)
-					primaryStatus.Color = components.T().Text
-					primaryStatus.Refresh()
-
-					for i := 0; i < cellCount; i++ {
-						idx := i
-						cells[idx].state = battleCellStateIdle
-						cells[idx].disabled = false
-						cells[idx].SetOnTapped(func() {
-							if !acceptingInput || roundSubmitted {
-								return
-							}
-							if _, exists := selected[idx]; exists {
-								return
-							}
-
-							selected[idx] = struct{}{}
-							choices = append(choices, idx)
-							cells[idx].SetState(battleCellStateSelected)
-							updateSelectionStatus()
-
-							if len(choices) >= state.Memory.CellsToShow {
-								submitRound()
-								return
-							}
-							confirmBtn.Enable()
-						})
-					}
-					refreshAllCells()
-
-					acceptingInput = true
-					resetBtn.Enable()
-					updateSelectionStatus()
-				})
-			}()
 			return
 		}
 	}
@@ -1219,6 +963,31 @@ func (a *App) showBossScreen() {
 	rebuildScreen()
 }
 
+// buildFirstWinRewardItems lists what a first victory granted; empty for repeat wins.
+func buildFirstWinRewardItems(record models.BattleRecord) []fyne.CanvasObject {
+	t := components.T()
+	var items []fyne.CanvasObject
+	if record.RewardTitle != "" || record.RewardBadge != "" || record.RewardCosmetic != "" {
+		items = append(items, widget.NewSeparator(), components.MakeLabel("Награда за первую победу", t.Gold))
+	}
+	if record.RewardTitle != "" {
+		items = append(items, components.MakeLabel("Титул: "+record.RewardTitle, t.Text))
+	}
+	if record.RewardBadge != "" {
+		items = append(items, components.MakeLabel("Значок: "+record.RewardBadge, t.Text))
+	}
+	if record.RewardCosmetic != "" {
+		items = append(items, components.MakeLabel("Украшение: "+record.RewardCosmetic, t.Text))
+	}
+	if record.RewardItem != "" {
+		items = append(items, components.MakeLabel("Добыча: "+record.RewardItem, t.Gold))
+	}
+	if record.UnlockedEnemyName != "" {
+		items = append(items, components.MakeLabel("Открыт противник: "+record.UnlockedEnemyName, t.Accent))
+	}
+	return items
+}
+
 func phaseDisplay(p boss.Phase) string {
 	switch p {
 	case boss.PhaseMemory:
@@ -1375,6 +1144,8 @@ const (
 	battleCellStateSelected
 	battleCellStateResultCorrect
 	battleCellStateResultWrong
+	battleCellStateDecoy
+	battleCellStateFog
 )
 
 type battleCell struct {
@@ -1532,8 +1303,24 @@ func battleCellPalette(state battleCellState, disabled bool) (color.NRGBA, color
 		glow:   withAlpha(t.Danger, 160),
 	}
 
+	decoy := palette{
+		fill:   withAlpha(t.Danger, 50),
+		border: withAlpha(t.Danger, 200),
+		glow:   withAlpha(t.Danger, 120),
+	}
+
+	fog := palette{
+		fill:   withAlpha(t.TextMuted, 90),
+		border: withAlpha(t.TextMuted, 120),
+		glow:   color.NRGBA{A: 0},
+	}
+
 	current := idle
 	switch state {
+	case battleCellStateDecoy:
+		current = decoy
+	case battleCellStateFog:
+		current = fog
 	case battleCellStateShown:
 		current = shown
 	case battleCellStateSelected:
@@ -29,6 +29,8 @@ type QuestCardSystemActions struct {
 	OnComplete func()
 	OnFail     func()
 	OnDelete   func()
+	// OnLogQuantity is optional; when set a "+N" button is shown.
+	OnLogQuantity func()
 }
 
 // MakeQuestCardSystem renders a compact HUD-style quest card.
@@ -94,6 +96,11 @@ func MakeQuestCardSystem(data QuestCardSystemData, actions QuestCardSystemAction
 	deleteWrap := container.NewGridWrap(fyne.NewSize(30, 30), deleteBtn)
 
 	actionRow := container.NewHBox(completeWrap, failWrap, deleteWrap)
+	if actions.OnLogQuantity != nil {
+		logBtn := widget.NewButton("+N", actions.OnLogQuantity)
+		logBtn.Importance = widget.LowImportance
+		actionRow.Objects = append([]fyne.CanvasObject{container.NewGridWrap(fyne.NewSize(44, 30), logBtn)}, actionRow.Objects...)
+	}
 	actionsCol := container.NewVBox(layout.NewSpacer(), actionRow)
 
 	body := container.NewVBox(bodyItems...)
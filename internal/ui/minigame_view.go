@@ -0,0 +1,601 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"solo-leveling/internal/game/combat"
+	"solo-leveling/internal/models"
+	"solo-leveling/internal/ui/components"
+)
+
+// ================================================================
+// Minigame adapter
+// ================================================================
+
+// minigameHooks lets a minigame view drive the status lines and buttons of the battle screen.
+type minigameHooks struct {
+	runOnMain   func(func())
+	setPrimary  func(text string, clr color.Color)
+	setProgress func(text string)
+	// setReady toggles the confirm/reset buttons while the player is answering.
+	setReady func(ready bool)
+	// submit is called by the view once the input is complete.
+	submit func()
+}
+
+// minigameView renders one round of a combat minigame.
+type minigameView interface {
+	Content() fyne.CanvasObject
+	// Start plays the stimulus and then accepts input.
+	Start()
+	Input() []int
+	// Reset clears the input given so far.
+	Reset()
+	// Lock stops accepting input and reveals the correct answer.
+	Lock()
+}
+
+func newMinigameView(win fyne.Window, round models.MinigameRound, hooks minigameHooks) minigameView {
+	switch round.Kind {
+	case models.MinigameNBack:
+		return newNBackView(win, round, hooks)
+	case models.MinigameStroop:
+		return newStroopView(round, hooks)
+	default:
+		return newGridMinigameView(win, round, hooks)
+	}
+}
+
+// mountMinigameRound renders round into the battle screen and starts it.
+// process resolves the submitted input (off the main thread); onResolved runs
+// on the main thread afterwards.
+func (a *App) mountMinigameRound(win fyne.Window, centerRef, bottomRef *fyne.Container, round models.MinigameRound,
+	logWidget fyne.CanvasObject, process func(input []int) error, onResolved func(), onSurrender func()) {
+	t := components.T()
+	runOnMain := func(fn func()) {
+		if d, ok := a.app.Driver().(interface{ RunOnMain(func()) }); ok {
+			d.RunOnMain(fn)
+			return
+		}
+		fn()
+	}
+
+	if round.ShowTimeMs <= 0 {
+		round.ShowTimeMs = 1000
+	}
+
+	primaryStatus := components.MakeLabel("", t.Gold)
+	primaryStatus.TextSize = components.TextBodyLG
+	primaryStatus.TextStyle = fyne.TextStyle{Bold: true}
+	secondaryStatus := components.MakeLabel("", t.TextSecondary)
+	secondaryStatus.TextSize = components.TextNumberSM
+	gameLabel := components.MakeLabel(round.Kind.DisplayName(), t.TextMuted)
+	gameLabel.TextSize = components.TextBodySM
+
+	var confirmBtn, resetBtn *widget.Button
+	var view minigameView
+	submitted := false
+
+	submit := func() {
+		if submitted {
+			return
+		}
+		submitted = true
+		confirmBtn.Disable()
+		resetBtn.Disable()
+		view.Lock()
+
+		input := append([]int(nil), view.Input()...)
+		result := combat.ForKind(round.Kind).Evaluate(round, input)
+		primaryStatus.Text = fmt.Sprintf("Точность %.0f%% • расчёт урона...", result.Accuracy*100)
+		primaryStatus.Color = t.Gold
+		primaryStatus.Refresh()
+
+		go func() {
+			time.Sleep(450 * time.Millisecond)
+			err := process(input)
+			runOnMain(func() {
+				if err != nil {
+					dialog.ShowError(err, win)
+					return
+				}
+				onResolved()
+			})
+		}()
+	}
+
+	view = newMinigameView(win, round, minigameHooks{
+		runOnMain: runOnMain,
+		setPrimary: func(text string, clr color.Color) {
+			primaryStatus.Text = text
+			primaryStatus.Color = clr
+			primaryStatus.Refresh()
+		},
+		setProgress: func(text string) {
+			secondaryStatus.Text = text
+			secondaryStatus.Refresh()
+		},
+		setReady: func(ready bool) {
+			if submitted {
+				return
+			}
+			if ready {
+				confirmBtn.Enable()
+				resetBtn.Enable()
+				return
+			}
+			confirmBtn.Disable()
+			resetBtn.Disable()
+		},
+		submit: submit,
+	})
+
+	confirmBtn = widget.NewButtonWithIcon("Готово", theme.ConfirmIcon(), submit)
+	confirmBtn.Importance = widget.HighImportance
+	confirmBtn.Disable()
+
+	resetBtn = widget.NewButtonWithIcon("Сбросить выбор", theme.ViewRefreshIcon(), func() {
+		if !submitted {
+			view.Reset()
+		}
+	})
+	resetBtn.Importance = widget.MediumImportance
+	resetBtn.Disable()
+
+	surrenderBtn := widget.NewButtonWithIcon("Сдаться", theme.CancelIcon(), onSurrender)
+	surrenderBtn.Importance = widget.DangerImportance
+
+	centerRef.Add(container.NewVBox(
+		container.NewCenter(gameLabel),
+		container.NewCenter(primaryStatus),
+		container.NewCenter(view.Content()),
+		container.NewCenter(secondaryStatus),
+		logWidget,
+	))
+	bottomRef.Add(container.NewHBox(confirmBtn, resetBtn, layout.NewSpacer(), surrenderBtn))
+	view.Start()
+}
+
+// ================================================================
+// Grid-based minigames: grid memory, sequence, rotation
+// ================================================================
+
+// highlightTickMs is how often the highlight phase is redrawn for animated modifiers.
+const highlightTickMs = 150
+
+type gridMinigameView struct {
+	round     models.MinigameRound
+	hooks     minigameHooks
+	cells     []*battleCell
+	content   fyne.CanvasObject
+	choices   []int
+	selected  map[int]bool
+	accepting bool
+	locked    bool
+}
+
+func newGridMinigameView(win fyne.Window, round models.MinigameRound, hooks minigameHooks) *gridMinigameView {
+	v := &gridMinigameView{round: round, hooks: hooks, selected: map[int]bool{}}
+	count := round.GridSize * round.GridSize
+	v.cells = make([]*battleCell, count)
+	objs := make([]fyne.CanvasObject, count)
+	side := cellSizeForGrid(win, round.GridSize)
+	for i := range v.cells {
+		idx := i
+		cell := newBattleCell(side)
+		cell.SetOnTapped(func() { v.tap(idx) })
+		v.cells[i] = cell
+		objs[i] = cell
+	}
+	v.content = components.MakeCard(container.NewPadded(container.NewGridWithColumns(round.GridSize, objs...)))
+	return v
+}
+
+func (v *gridMinigameView) Content() fyne.CanvasObject { return v.content }
+
+func (v *gridMinigameView) Input() []int { return v.choices }
+
+func (v *gridMinigameView) Start() {
+	t := components.T()
+	switch v.round.Kind {
+	case models.MinigameSequence:
+		v.hooks.setPrimary("Запомни порядок вспышек", t.Gold)
+	case models.MinigameRotation:
+		v.hooks.setPrimary("Запомни узор", t.Gold)
+	default:
+		switch {
+		case len(v.round.Decoys) > 0:
+			v.hooks.setPrimary("Запомни клетки и не трогай красные ловушки", t.Gold)
+		case v.round.Shuffled:
+			v.hooks.setPrimary("Запомни, где клетки окажутся в конце", t.Gold)
+		default:
+			v.hooks.setPrimary("Запомни подсвеченные клетки", t.Gold)
+		}
+	}
+	v.updateProgress()
+
+	go func() {
+		if v.round.Kind == models.MinigameSequence {
+			for _, idx := range v.round.Cells {
+				cell := idx
+				v.hooks.runOnMain(func() { v.setOnly(map[int]bool{cell: true}) })
+				time.Sleep(time.Duration(v.round.ShowTimeMs) * time.Millisecond)
+				v.hooks.runOnMain(func() { v.setOnly(nil) })
+				time.Sleep(150 * time.Millisecond)
+			}
+		} else {
+			// Tick through the highlight so fog, flicker and shuffle can change the field midway.
+			total := v.round.ShowTimeMs
+			for elapsed := 0; elapsed < total; elapsed += highlightTickMs {
+				secondHalf := elapsed >= total/2
+				visible := !v.round.Flicker || (elapsed/highlightTickMs)%3 != 2
+				v.hooks.runOnMain(func() { v.renderHighlight(secondHalf, visible) })
+				step := highlightTickMs
+				if rest := total - elapsed; rest < step {
+					step = rest
+				}
+				time.Sleep(time.Duration(step) * time.Millisecond)
+			}
+		}
+
+		v.hooks.runOnMain(func() {
+			if v.locked {
+				return
+			}
+			switch v.round.Kind {
+			case models.MinigameSequence:
+				v.hooks.setPrimary(fmt.Sprintf("Повтори порядок: %d клеток", v.round.Inputs), t.Text)
+			case models.MinigameRotation:
+				v.hooks.setPrimary(fmt.Sprintf("Отметь узор, повёрнутый на %d° по часовой", v.round.Turns*90), t.Text)
+			default:
+				v.hooks.setPrimary(fmt.Sprintf("Выбери клетки: %d", v.round.Inputs), t.Text)
+			}
+			for _, cell := range v.cells {
+				cell.state = battleCellStateIdle
+				cell.disabled = false
+				cell.Refresh()
+			}
+			v.accepting = true
+			v.updateProgress()
+		})
+	}()
+}
+
+// renderHighlight draws one tick of the highlight phase. In the second half
+// shuffled cells sit at their final positions and fogged cells go dark.
+func (v *gridMinigameView) renderHighlight(secondHalf, visible bool) {
+	if v.locked {
+		return
+	}
+	cells := v.round.Cells
+	if secondHalf && v.round.Shuffled {
+		cells = v.round.Answer
+	}
+	lit := make(map[int]bool, len(cells))
+	if visible {
+		for _, idx := range cells {
+			lit[idx] = true
+		}
+	}
+	fog := make(map[int]bool, len(v.round.Fog))
+	for _, idx := range v.round.Fog {
+		fog[idx] = true
+	}
+	decoys := make(map[int]bool, len(v.round.Decoys))
+	for _, idx := range v.round.Decoys {
+		decoys[idx] = true
+	}
+	for i, cell := range v.cells {
+		cell.disabled = true
+		switch {
+		case decoys[i]:
+			cell.state = battleCellStateDecoy
+			cell.disabled = false
+		case fog[i] && (secondHalf || !lit[i]):
+			cell.state = battleCellStateFog
+		case lit[i]:
+			cell.state = battleCellStateShown
+			cell.disabled = false
+		default:
+			cell.state = battleCellStateIdle
+		}
+		cell.Refresh()
+	}
+}
+
+func (v *gridMinigameView) setOnly(lit map[int]bool) {
+	for i, cell := range v.cells {
+		cell.disabled = true
+		cell.state = battleCellStateIdle
+		if lit[i] {
+			cell.state = battleCellStateShown
+			cell.disabled = false
+		}
+		cell.Refresh()
+	}
+}
+
+func (v *gridMinigameView) tap(idx int) {
+	if !v.accepting || v.locked || v.selected[idx] {
+		return
+	}
+	v.selected[idx] = true
+	v.choices = append(v.choices, idx)
+	v.cells[idx].SetState(battleCellStateSelected)
+	v.updateProgress()
+	if len(v.choices) >= v.round.Inputs {
+		v.hooks.submit()
+	}
+}
+
+func (v *gridMinigameView) updateProgress() {
+	v.hooks.setProgress(fmt.Sprintf("Сетка %dx%d • Выбрано %d/%d", v.round.GridSize, v.round.GridSize, len(v.choices), v.round.Inputs))
+	v.hooks.setReady(v.accepting && len(v.choices) > 0)
+}
+
+func (v *gridMinigameView) Reset() {
+	if !v.accepting || v.locked {
+		return
+	}
+	v.choices = nil
+	v.selected = map[int]bool{}
+	for _, cell := range v.cells {
+		cell.state = battleCellStateIdle
+		cell.Refresh()
+	}
+	v.updateProgress()
+}
+
+func (v *gridMinigameView) Lock() {
+	v.accepting = false
+	v.locked = true
+
+	answer := make(map[int]bool, len(v.round.Answer))
+	for _, idx := range v.round.Answer {
+		answer[idx] = true
+	}
+	decoys := make(map[int]bool, len(v.round.Decoys))
+	for _, idx := range v.round.Decoys {
+		decoys[idx] = true
+	}
+	correct := make(map[int]bool, len(v.choices))
+	for i, idx := range v.choices {
+		if v.round.Kind == models.MinigameSequence {
+			correct[idx] = i < len(v.round.Answer) && v.round.Answer[i] == idx
+		} else {
+			correct[idx] = answer[idx]
+		}
+	}
+	for i, cell := range v.cells {
+		switch {
+		case v.selected[i] && correct[i]:
+			cell.state = battleCellStateResultCorrect
+		case v.selected[i]:
+			cell.state = battleCellStateResultWrong
+		case answer[i]:
+			cell.state = battleCellStateShown
+		case decoys[i]:
+			cell.state = battleCellStateDecoy
+		default:
+			cell.state = battleCellStateIdle
+		}
+		cell.disabled = true
+		cell.Refresh()
+	}
+}
+
+// ================================================================
+// N-back
+// ================================================================
+
+type nBackView struct {
+	round   models.MinigameRound
+	hooks   minigameHooks
+	cells   []*battleCell
+	content fyne.CanvasObject
+	button  *widget.Button
+	step    int
+	flagged map[int]bool
+	locked  bool
+}
+
+func newNBackView(win fyne.Window, round models.MinigameRound, hooks minigameHooks) *nBackView {
+	v := &nBackView{round: round, hooks: hooks, step: -1, flagged: map[int]bool{}}
+	count := round.GridSize * round.GridSize
+	v.cells = make([]*battleCell, count)
+	objs := make([]fyne.CanvasObject, count)
+	side := cellSizeForGrid(win, round.GridSize)
+	for i := range v.cells {
+		v.cells[i] = newBattleCell(side)
+		objs[i] = v.cells[i]
+	}
+	v.button = widget.NewButtonWithIcon("Совпадает!", theme.ConfirmIcon(), v.flag)
+	v.button.Importance = widget.HighImportance
+	v.button.Disable()
+	grid := components.MakeCard(container.NewPadded(container.NewGridWithColumns(round.GridSize, objs...)))
+	v.content = container.NewVBox(grid, container.NewCenter(v.button))
+	return v
+}
+
+func (v *nBackView) Content() fyne.CanvasObject { return v.content }
+
+func (v *nBackView) Input() []int {
+	out := make([]int, 0, len(v.flagged))
+	for step := range v.round.Cells {
+		if v.flagged[step] {
+			out = append(out, step)
+		}
+	}
+	return out
+}
+
+func (v *nBackView) Start() {
+	v.hooks.setPrimary(fmt.Sprintf("Жми, если позиция совпадает с той, что была %d шаг(а) назад", v.round.N), components.T().Gold)
+	v.hooks.setReady(false)
+	go func() {
+		for i, pos := range v.round.Cells {
+			step, cell := i, pos
+			v.hooks.runOnMain(func() {
+				if v.locked {
+					return
+				}
+				v.step = step
+				for j, c := range v.cells {
+					c.state = battleCellStateIdle
+					if j == cell {
+						c.state = battleCellStateShown
+					}
+					c.Refresh()
+				}
+				if step >= v.round.N {
+					v.button.Enable()
+				}
+				v.hooks.setProgress(fmt.Sprintf("Шаг %d/%d • Отмечено %d", step+1, len(v.round.Cells), len(v.flagged)))
+			})
+			time.Sleep(time.Duration(v.round.ShowTimeMs) * time.Millisecond)
+		}
+		v.hooks.runOnMain(v.hooks.submit)
+	}()
+}
+
+func (v *nBackView) flag() {
+	if v.locked || v.step < v.round.N || v.flagged[v.step] {
+		return
+	}
+	v.flagged[v.step] = true
+	v.cells[v.round.Cells[v.step]].SetState(battleCellStateSelected)
+	v.hooks.setProgress(fmt.Sprintf("Шаг %d/%d • Отмечено %d", v.step+1, len(v.round.Cells), len(v.flagged)))
+}
+
+func (v *nBackView) Reset() {}
+
+func (v *nBackView) Lock() {
+	v.locked = true
+	v.button.Disable()
+	for _, c := range v.cells {
+		c.state = battleCellStateIdle
+		c.disabled = true
+		c.Refresh()
+	}
+}
+
+// ================================================================
+// Stroop
+// ================================================================
+
+type stroopView struct {
+	round   models.MinigameRound
+	hooks   minigameHooks
+	word    *canvas.Text
+	buttons []*widget.Button
+	content fyne.CanvasObject
+	answers []int
+	current int
+	locked  bool
+}
+
+func newStroopView(round models.MinigameRound, hooks minigameHooks) *stroopView {
+	v := &stroopView{round: round, hooks: hooks, current: -1}
+	v.word = canvas.NewText("", components.T().Text)
+	v.word.TextSize = 40
+	v.word.TextStyle = fyne.TextStyle{Bold: true}
+	v.word.Alignment = fyne.TextAlignCenter
+
+	var buttons []fyne.CanvasObject
+	for i, name := range models.StroopColors {
+		ink := i
+		btn := widget.NewButton(name, func() { v.answer(ink) })
+		btn.Disable()
+		v.buttons = append(v.buttons, btn)
+		buttons = append(buttons, btn)
+	}
+	bg := canvas.NewRectangle(components.T().BGPanel)
+	bg.CornerRadius = components.RadiusLG
+	bg.SetMinSize(fyne.NewSize(360, 120))
+	v.content = container.NewVBox(
+		container.NewStack(bg, container.NewCenter(v.word)),
+		container.NewGridWithColumns(len(buttons), buttons...),
+	)
+	return v
+}
+
+func stroopInk(idx int) color.Color {
+	t := components.T()
+	switch idx {
+	case 0:
+		return t.Danger
+	case 1:
+		return t.Blue
+	case 2:
+		return t.Success
+	default:
+		return t.Gold
+	}
+}
+
+func (v *stroopView) Content() fyne.CanvasObject { return v.content }
+
+func (v *stroopView) Input() []int { return v.answers }
+
+func (v *stroopView) Start() {
+	v.hooks.setPrimary("Выбери цвет букв, а не слово", components.T().Gold)
+	v.hooks.setReady(false)
+	v.next()
+}
+
+// next shows the following prompt with its own time limit, or submits after the last one.
+func (v *stroopView) next() {
+	if v.locked {
+		return
+	}
+	v.current++
+	if v.current >= len(v.round.Prompts) {
+		v.hooks.submit()
+		return
+	}
+	prompt := v.round.Prompts[v.current]
+	v.word.Text = models.StroopColors[prompt.Word]
+	v.word.Color = stroopInk(prompt.Ink)
+	v.word.Refresh()
+	for _, btn := range v.buttons {
+		btn.Enable()
+	}
+	v.hooks.setProgress(fmt.Sprintf("Слово %d/%d", v.current+1, len(v.round.Prompts)))
+
+	idx := v.current
+	go func() {
+		time.Sleep(time.Duration(v.round.ShowTimeMs) * time.Millisecond)
+		v.hooks.runOnMain(func() {
+			if v.current == idx && len(v.answers) == idx {
+				v.answer(-1)
+			}
+		})
+	}()
+}
+
+func (v *stroopView) answer(ink int) {
+	if v.locked || len(v.answers) != v.current {
+		return
+	}
+	v.answers = append(v.answers, ink)
+	v.next()
+}
+
+func (v *stroopView) Reset() {}
+
+func (v *stroopView) Lock() {
+	v.locked = true
+	for _, btn := range v.buttons {
+		btn.Disable()
+	}
+}
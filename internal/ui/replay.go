@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"solo-leveling/internal/models"
+	"solo-leveling/internal/ui/components"
+)
+
+// replayResultPauseMs is how long each round's outcome stays on screen.
+const replayResultPauseMs = 1500
+
+// replayMaxShowMs caps the highlight phase so long rounds don't stall the replay.
+const replayMaxShowMs = 2000
+
+// showBattleReplay opens a window that animates a recorded battle round by round.
+func (a *App) showBattleReplay(record models.BattleRecord) {
+	rounds, err := a.engine.DB.GetBattleRounds(record.ID)
+	if err != nil {
+		dialog.ShowError(err, a.window)
+		return
+	}
+	if len(rounds) == 0 {
+		dialog.ShowInformation("Повтор боя", "Для этого боя нет записи раундов.", a.window)
+		return
+	}
+
+	win := a.app.NewWindow(fmt.Sprintf("Повтор: %s", record.EnemyName))
+	win.Resize(fyne.NewSize(640, 700))
+	win.CenterOnScreen()
+
+	runOnMain := func(fn func()) {
+		if d, ok := a.app.Driver().(interface{ RunOnMain(func()) }); ok {
+			d.RunOnMain(fn)
+			return
+		}
+		fn()
+	}
+
+	t := components.T()
+	title := components.MakeTitle(fmt.Sprintf("%s — %s", record.EnemyName, record.FoughtAt.Local().Format("02.01.2006 15:04")), t.Text, components.TextHeadingMD)
+	roundLabel := components.MakeLabel("", t.Gold)
+	roundLabel.TextStyle = fyne.TextStyle{Bold: true}
+	detailLabel := components.MakeLabel("", t.TextSecondary)
+	detailLabel.TextSize = components.TextBodySM
+	gridHolder := container.NewCenter()
+
+	current := 0
+	playing := true
+	// generation invalidates pending animation steps whenever the user navigates.
+	generation := 0
+	var cells []*battleCell
+	var playBtn *widget.Button
+
+	setCells := func(round models.BattleRound, showResult bool) {
+		shown := make(map[int]bool, len(round.ShownCells))
+		for _, idx := range round.ShownCells {
+			shown[idx] = true
+		}
+		decoys := make(map[int]bool, len(round.DecoyCells))
+		for _, idx := range round.DecoyCells {
+			decoys[idx] = true
+		}
+		chosen := make(map[int]bool, len(round.Choices))
+		for _, idx := range round.Choices {
+			chosen[idx] = true
+		}
+		for i, cell := range cells {
+			cell.disabled = false
+			switch {
+			case decoys[i] && (!showResult || !chosen[i]):
+				cell.state = battleCellStateDecoy
+			case !showResult && shown[i]:
+				cell.state = battleCellStateShown
+			case !showResult:
+				cell.state = battleCellStateIdle
+			case chosen[i] && shown[i]:
+				cell.state = battleCellStateResultCorrect
+			case chosen[i]:
+				cell.state = battleCellStateResultWrong
+			case shown[i]:
+				cell.state = battleCellStateShown
+				cell.disabled = true
+			default:
+				cell.state = battleCellStateIdle
+			}
+			cell.Refresh()
+		}
+	}
+
+	var showRound func(idx int)
+	showRound = func(idx int) {
+		generation++
+		gen := generation
+		current = idx
+		round := rounds[idx]
+
+		if round.Minigame.IsGrid() {
+			cells = make([]*battleCell, round.GridSize*round.GridSize)
+			objs := make([]fyne.CanvasObject, len(cells))
+			side := cellSizeForGrid(win, round.GridSize)
+			for i := range cells {
+				cells[i] = newBattleCell(side)
+				objs[i] = cells[i]
+			}
+			gridHolder.Objects = []fyne.CanvasObject{components.MakeCard(container.NewPadded(container.NewGridWithColumns(round.GridSize, objs...)))}
+		} else {
+			// Non-grid minigames have no field to animate; summarise the answers instead.
+			cells = nil
+			summary := components.MakeLabel(replayAnswerSummary(round), t.TextSecondary)
+			gridHolder.Objects = []fyne.CanvasObject{components.MakeCard(container.NewPadded(summary))}
+		}
+		gridHolder.Refresh()
+
+		roundLabel.Text = fmt.Sprintf("Раунд %d / %d • %s", idx+1, len(rounds), round.Minigame.DisplayName())
+		roundLabel.Refresh()
+		detailLabel.Text = "Запоминание..."
+		detailLabel.Refresh()
+		setCells(round, false)
+
+		showMs := round.ShowTimeMs
+		if showMs <= 0 || showMs > replayMaxShowMs {
+			showMs = replayMaxShowMs
+		}
+		go func() {
+			time.Sleep(time.Duration(showMs) * time.Millisecond)
+			runOnMain(func() {
+				if gen != generation {
+					return
+				}
+				setCells(round, true)
+				detail := fmt.Sprintf("Точность %.0f%% • Урон %d • Получено %d • Ответ за %.1f с",
+					round.Accuracy*100, round.DamageDealt, round.DamageTaken, float64(round.DurationMs)/1000)
+				if round.IsCrit {
+					detail = "⚡ Крит! " + detail
+				}
+				detailLabel.Text = detail
+				detailLabel.Refresh()
+			})
+			time.Sleep(replayResultPauseMs * time.Millisecond)
+			runOnMain(func() {
+				if gen != generation || !playing || idx+1 >= len(rounds) {
+					return
+				}
+				showRound(idx + 1)
+			})
+		}()
+	}
+
+	prevBtn := widget.NewButtonWithIcon("", theme.MediaSkipPreviousIcon(), func() {
+		if current > 0 {
+			showRound(current - 1)
+		}
+	})
+	nextBtn := widget.NewButtonWithIcon("", theme.MediaSkipNextIcon(), func() {
+		if current+1 < len(rounds) {
+			showRound(current + 1)
+		}
+	})
+	playBtn = widget.NewButtonWithIcon("Пауза", theme.MediaPauseIcon(), func() {
+		playing = !playing
+		if playing {
+			playBtn.SetText("Пауза")
+			playBtn.SetIcon(theme.MediaPauseIcon())
+			next := current
+			if next+1 >= len(rounds) {
+				next = 0
+			}
+			showRound(next)
+			return
+		}
+		playBtn.SetText("Играть")
+		playBtn.SetIcon(theme.MediaPlayIcon())
+	})
+
+	resultText := "Поражение"
+	resultColor := t.Danger
+	if record.Result == models.BattleWin {
+		resultText = "Победа"
+		resultColor = t.Success
+	}
+	result := canvas.NewText(resultText, resultColor)
+	result.TextStyle = fyne.TextStyle{Bold: true}
+
+	seedLabel := components.MakeLabel(fmt.Sprintf("Seed: %d", record.Seed), t.TextMuted)
+	seedLabel.TextSize = components.TextBodySM
+
+	header := container.NewVBox(container.NewHBox(title, layout.NewSpacer(), result), seedLabel, roundLabel, detailLabel)
+	controls := container.NewHBox(layout.NewSpacer(), prevBtn, playBtn, nextBtn, layout.NewSpacer())
+	win.SetContent(container.NewPadded(container.NewBorder(header, controls, nil, nil, gridHolder)))
+	win.SetOnClosed(func() {
+		generation++
+	})
+	win.Show()
+	showRound(0)
+}
+
+// replayAnswerSummary describes a non-grid round as correct answers out of the total.
+func replayAnswerSummary(round models.BattleRound) string {
+	switch round.Minigame {
+	case models.MinigameNBack:
+		matched := 0
+		expected := make(map[int]bool, len(round.ShownCells))
+		for _, step := range round.ShownCells {
+			expected[step] = true
+		}
+		for _, step := range round.Choices {
+			if expected[step] {
+				matched++
+			}
+		}
+		return fmt.Sprintf("Совпадений найдено: %d из %d • Ложных нажатий: %d",
+			matched, len(round.ShownCells), len(round.Choices)-matched)
+	case models.MinigameStroop:
+		correct := 0
+		for i, ink := range round.ShownCells {
+			if i < len(round.Choices) && round.Choices[i] == ink {
+				correct++
+			}
+		}
+		return fmt.Sprintf("Верно названо цветов: %d из %d", correct, len(round.ShownCells))
+	default:
+		return fmt.Sprintf("Ответов: %d", len(round.Choices))
+	}
+}
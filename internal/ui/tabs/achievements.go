@@ -51,7 +51,12 @@ func RefreshAchievements(ctx *Context) {
 		container.NewVScroll(buildEnemyGalleryTabContent(ctx)),
 	)
 
-	tabs := container.NewAppTabs(achievementsTab, enemyGalleryTab)
+	expeditionsTab := container.NewTabItem(
+		"Экспедиции",
+		container.NewVScroll(buildExpeditionHistoryTabContent(ctx)),
+	)
+
+	tabs := container.NewAppTabs(achievementsTab, enemyGalleryTab, expeditionsTab)
 	tabs.SetTabLocation(container.TabLocationTop)
 
 	header := container.NewPadded(components.MakeSectionHeader("Путь охотника"))
@@ -137,6 +142,63 @@ func buildEnemyGalleryTabContent(ctx *Context) fyne.CanvasObject {
 	return centerWithMaxWidth(content, 1200)
 }
 
+func buildExpeditionHistoryTabContent(ctx *Context) fyne.CanvasObject {
+	t := components.T()
+	title := components.MakeSystemHeaderCompact("История экспедиций")
+
+	completed, err := ctx.Engine.DB.GetCompletedExpeditions(ctx.Engine.Character.ID)
+	if err != nil {
+		return components.MakeHUDPanel(container.NewVBox(
+			title,
+			components.MakeLabel("Ошибка: "+err.Error(), t.Danger),
+		))
+	}
+	if len(completed) == 0 {
+		empty := components.MakeLabel("Завершённых экспедиций пока нет.", t.TextSecondary)
+		empty.TextSize = components.TextBodySM
+		return centerWithMaxWidth(container.NewVBox(makeAchievementsGap(components.SpaceLG), title, empty), 960)
+	}
+
+	names := map[int64]string{}
+	if expeditions, err := ctx.Engine.DB.GetAllExpeditions(ctx.Engine.Character.ID); err == nil {
+		for _, ex := range expeditions {
+			names[ex.ID] = ex.Name
+		}
+	}
+
+	counts := map[models.ExpeditionTier]int{}
+	list := container.NewVBox()
+	for _, c := range completed {
+		counts[c.Tier]++
+		name := names[c.ExpeditionID]
+		if name == "" {
+			name = fmt.Sprintf("Экспедиция #%d", c.ExpeditionID)
+		}
+		line := components.MakeLabel(
+			fmt.Sprintf("%s %s · %s · %s", c.Tier.Icon(), name, c.Tier.DisplayName(), c.CompletedAt.Local().Format("02.01.2006")),
+			t.Text,
+		)
+		list.Add(line)
+	}
+
+	summary := components.MakeLabel(
+		fmt.Sprintf("%s %d   %s %d   %s %d",
+			models.TierGold.Icon(), counts[models.TierGold],
+			models.TierSilver.Icon(), counts[models.TierSilver],
+			models.TierBronze.Icon(), counts[models.TierBronze]),
+		t.Gold,
+	)
+
+	content := container.NewVBox(
+		makeAchievementsGap(components.SpaceLG),
+		title,
+		summary,
+		makeAchievementsGap(components.SpaceSM),
+		components.MakeCard(list),
+	)
+	return centerWithMaxWidth(content, 960)
+}
+
 func buildZoneEnemyList(ctx *Context, enemies []models.DefeatedEnemy) fyne.CanvasObject {
 	list := container.NewVBox()
 	for i, enemy := range enemies {
@@ -307,19 +369,38 @@ func buildDefeatedEnemyGalleryCard(ctx *Context, enemy models.DefeatedEnemy) fyn
 	hint := components.MakeLabel("Нажмите на портрет, чтобы открыть историю", t.AccentDim)
 	hint.TextSize = components.TextBodySM
 
-	content := container.NewVBox(
-		nameRow,
-		metaLabel,
-		hint,
-		makeAchievementsGap(components.SpaceSM),
-		buildEnemyPortrait(ctx, enemy),
-	)
+	items := []fyne.CanvasObject{nameRow, metaLabel}
+	if reward := defeatedEnemyRewardText(enemy); reward != "" {
+		rewardLabel := components.MakeLabel(reward, t.Gold)
+		rewardLabel.TextSize = components.TextBodySM
+		items = append(items, rewardLabel)
+	}
+	items = append(items, hint, makeAchievementsGap(components.SpaceSM), buildEnemyPortrait(ctx, enemy))
+	content := container.NewVBox(items...)
 	inset := container.New(layout.NewCustomPaddedLayout(
 		components.SpaceMD, components.SpaceMD, components.SpaceMD, components.SpaceMD,
 	), content)
 	return container.NewStack(panelBg, inset)
 }
 
+// defeatedEnemyRewardText summarises the first-win reward earned from enemy.
+func defeatedEnemyRewardText(enemy models.DefeatedEnemy) string {
+	var parts []string
+	if enemy.RewardBadge != "" {
+		parts = append(parts, enemy.RewardBadge)
+	}
+	if enemy.RewardTitle != "" {
+		parts = append(parts, "«"+enemy.RewardTitle+"»")
+	}
+	if enemy.RewardCosmetic != "" {
+		parts = append(parts, enemy.RewardCosmetic)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Награда: " + strings.Join(parts, " · ")
+}
+
 func buildEnemyPortrait(ctx *Context, enemy models.DefeatedEnemy) fyne.CanvasObject {
 	t := components.T()
 	const maxWidth float32 = 320
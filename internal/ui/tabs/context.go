@@ -20,6 +20,7 @@ type Context struct {
 	StatsPanel        *fyne.Container
 	ExpeditionsPanel  *fyne.Container
 	AchievementsPanel *fyne.Container
+	EquipmentPanel    *fyne.Container
 
 	RefreshAll          func()
 	RefreshCharacter    func()
@@ -29,5 +30,9 @@ type Context struct {
 	RefreshAchievements func()
 	RefreshHistory      func()
 	StartBattle         func(enemy models.Enemy)
+	StartDailyChallenge func()
+	StartPractice       func(enemyID int64)
+	StartCustomPractice func(grid, cells int)
+	ShowBattleReplay    func(record models.BattleRecord)
 	QuestThemeMode      string
 }
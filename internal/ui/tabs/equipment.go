@@ -0,0 +1,153 @@
+package tabs
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"solo-leveling/internal/game/combat/memory"
+	"solo-leveling/internal/models"
+	"solo-leveling/internal/ui/components"
+)
+
+func BuildEquipment(ctx *Context) fyne.CanvasObject {
+	ctx.EquipmentPanel = container.NewVBox()
+	RefreshEquipment(ctx)
+	return container.NewVScroll(container.NewPadded(
+		container.NewVBox(components.MakeSectionHeader("Снаряжение Охотника"), ctx.EquipmentPanel),
+	))
+}
+
+func RefreshEquipment(ctx *Context) {
+	if ctx.EquipmentPanel == nil {
+		return
+	}
+	ctx.EquipmentPanel.Objects = nil
+
+	t := components.T()
+	items, err := ctx.Engine.GetInventory()
+	if err != nil {
+		ctx.EquipmentPanel.Add(components.MakeLabel("Ошибка: "+err.Error(), t.Danger))
+		ctx.EquipmentPanel.Refresh()
+		return
+	}
+
+	ctx.EquipmentPanel.Add(buildEquippedCard(ctx, items))
+	ctx.EquipmentPanel.Add(buildCombatStatsCard(ctx))
+	ctx.EquipmentPanel.Add(buildInventoryCard(ctx, items))
+	ctx.EquipmentPanel.Refresh()
+}
+
+// buildEquippedCard shows one row per slot with the item worn there.
+func buildEquippedCard(ctx *Context, items []models.InventoryItem) *fyne.Container {
+	t := components.T()
+	rows := []fyne.CanvasObject{components.MakeTitle("Экипировка", t.Accent, components.TextHeadingMD), widget.NewSeparator()}
+
+	equipped := make(map[models.EquipmentSlot]models.InventoryItem)
+	for _, it := range items {
+		if it.Equipped {
+			equipped[it.Item.Slot] = it
+		}
+	}
+	for _, slot := range models.AllEquipmentSlots {
+		slotLabel := components.MakeLabel(fmt.Sprintf("%s %s:", slot.Icon(), slot.DisplayName()), t.TextSecondary)
+		it, ok := equipped[slot]
+		if !ok {
+			rows = append(rows, container.NewHBox(slotLabel, components.MakeLabel("пусто", t.TextMuted)))
+			continue
+		}
+		itemID := it.ID
+		unequipBtn := widget.NewButton("Снять", func() {
+			setEquipped(ctx, itemID, false)
+		})
+		unequipBtn.Importance = widget.LowImportance
+		info := container.NewHBox(
+			slotLabel,
+			components.MakeLabel(it.Item.Name, t.Gold),
+			components.MakeLabel(it.Item.Effects.Summary(), t.TextSecondary),
+		)
+		rows = append(rows, container.NewBorder(nil, nil, nil, unequipBtn, info))
+	}
+	return components.MakeCard(container.NewVBox(rows...))
+}
+
+// buildCombatStatsCard shows the stats fights start with, equipment included.
+func buildCombatStatsCard(ctx *Context) *fyne.Container {
+	t := components.T()
+	header := components.MakeTitle("Боевые характеристики", t.Accent, components.TextHeadingMD)
+	stats, err := ctx.Engine.GetCombatStats()
+	if err != nil {
+		return components.MakeCard(components.MakeLabel("Ошибка: "+err.Error(), t.Danger))
+	}
+	effects, err := ctx.Engine.GetEquipmentEffects()
+	if err != nil {
+		return components.MakeCard(components.MakeLabel("Ошибка: "+err.Error(), t.Danger))
+	}
+
+	statLine := func(stat models.StatType, value, bonus int) fyne.CanvasObject {
+		text := fmt.Sprintf("%s %s: %d", stat.Icon(), stat.DisplayName(), value)
+		if bonus != 0 {
+			text += fmt.Sprintf(" (%+d)", bonus)
+		}
+		return components.MakeLabel(text, t.Text)
+	}
+	rows := []fyne.CanvasObject{
+		header, widget.NewSeparator(),
+		statLine(models.StatStrength, stats.STR, effects.STR),
+		statLine(models.StatAgility, stats.AGI, effects.AGI),
+		statLine(models.StatIntellect, stats.INT, effects.INT),
+		statLine(models.StatEndurance, stats.STA, effects.STA),
+		components.MakeLabel(fmt.Sprintf("HP: %d · Шанс крита: %.1f%% · Время показа: %+d%%",
+			memory.MaxHP(stats), memory.PlayerCritChance(stats)*100, effects.ShowTimePercent), t.Success),
+	}
+	return components.MakeCard(container.NewVBox(rows...))
+}
+
+// buildInventoryCard lists the items that are not worn with a button to put them on.
+func buildInventoryCard(ctx *Context, items []models.InventoryItem) *fyne.Container {
+	t := components.T()
+	rows := []fyne.CanvasObject{components.MakeTitle("Инвентарь", t.Accent, components.TextHeadingMD), widget.NewSeparator()}
+
+	count := 0
+	for _, it := range items {
+		if it.Equipped {
+			continue
+		}
+		count++
+		itemID := it.ID
+		equipBtn := widget.NewButton("Надеть", func() {
+			setEquipped(ctx, itemID, true)
+		})
+		info := container.NewVBox(
+			container.NewHBox(
+				components.MakeLabel(it.Item.Slot.Icon()+" "+it.Item.Name, t.Text),
+				layout.NewSpacer(),
+				components.MakeLabel(it.Item.Effects.Summary(), t.TextSecondary),
+			),
+			components.MakeLabel(fmt.Sprintf("Добыто: %s, %s", it.Source, it.AcquiredAt.Local().Format("02.01.2006")), t.TextMuted),
+		)
+		rows = append(rows, container.NewBorder(nil, nil, nil, equipBtn, info))
+	}
+	if count == 0 {
+		rows = append(rows, components.MakeEmptyState("Предметы выпадают из боссов и экспедиций, пройденных на золото."))
+	}
+	return components.MakeCard(container.NewVBox(rows...))
+}
+
+func setEquipped(ctx *Context, itemID int64, equipped bool) {
+	var err error
+	if equipped {
+		err = ctx.Engine.EquipItem(itemID)
+	} else {
+		err = ctx.Engine.UnequipItem(itemID)
+	}
+	if err != nil {
+		dialog.ShowError(err, ctx.Window)
+		return
+	}
+	RefreshEquipment(ctx)
+}
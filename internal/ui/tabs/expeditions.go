@@ -2,16 +2,19 @@ package tabs
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"solo-leveling/internal/game"
 	"solo-leveling/internal/models"
 	"solo-leveling/internal/ui/components"
 )
@@ -30,7 +33,11 @@ func buildExpeditionsHeader(ctx *Context) fyne.CanvasObject {
 		showImportExpeditionsJSONDialog(ctx)
 	})
 	importBtn.Importance = widget.MediumImportance
-	return container.NewBorder(nil, nil, title, container.NewHBox(layout.NewSpacer(), importBtn))
+	exportBtn := widget.NewButtonWithIcon("Экспорт JSON", theme.DocumentSaveIcon(), func() {
+		showExportExpeditionsJSONDialog(ctx)
+	})
+	exportBtn.Importance = widget.MediumImportance
+	return container.NewBorder(nil, nil, title, container.NewHBox(layout.NewSpacer(), importBtn, exportBtn))
 }
 
 func RefreshExpeditions(ctx *Context) {
@@ -45,7 +52,7 @@ func RefreshExpeditions(ctx *Context) {
 		ctx.ExpeditionsPanel.Add(components.MakeLabel("Ошибка обновления: "+err.Error(), t.Danger))
 	}
 
-	expeditions, err := ctx.Engine.DB.GetAllExpeditions()
+	expeditions, err := ctx.Engine.DB.GetAllExpeditions(ctx.Engine.Character.ID)
 	if err != nil {
 		ctx.ExpeditionsPanel.Add(components.MakeLabel("Ошибка: "+err.Error(), t.Danger))
 		ctx.ExpeditionsPanel.Refresh()
@@ -58,14 +65,21 @@ func RefreshExpeditions(ctx *Context) {
 		ctx.ExpeditionsPanel.Add(widget.NewSeparator())
 	}
 
+	lastTier := make(map[int64]models.ExpeditionTier, len(completed))
+	for _, c := range completed {
+		if _, ok := lastTier[c.ExpeditionID]; !ok {
+			lastTier[c.ExpeditionID] = c.Tier
+		}
+	}
+
 	for _, ex := range expeditions {
-		ctx.ExpeditionsPanel.Add(buildExpeditionCard(ctx, ex))
+		ctx.ExpeditionsPanel.Add(buildExpeditionCard(ctx, ex, lastTier[ex.ID]))
 	}
 
 	ctx.ExpeditionsPanel.Refresh()
 }
 
-func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
+func buildExpeditionCard(ctx *Context, ex models.Expedition, tier models.ExpeditionTier) *fyne.Container {
 	t := components.T()
 	statusIcon := ""
 	statusText := ""
@@ -84,6 +98,14 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 		statusIcon = "⛔"
 		statusText = "Провалена"
 		statusColor = t.Danger
+	case models.ExpeditionPaused:
+		statusIcon = "⏸"
+		statusText = "На паузе"
+		statusColor = t.Blue
+	case models.ExpeditionAbandoned:
+		statusIcon = "🏳"
+		statusText = "Покинута"
+		statusColor = t.TextSecondary
 	default:
 		statusIcon = "•"
 		statusText = string(ex.Status)
@@ -97,6 +119,12 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 	deadlineText := "Дедлайн: без ограничения"
 	if ex.Deadline != nil {
 		deadlineText = "Дедлайн: " + ex.Deadline.Local().Format("02.01.2006")
+		if ex.Extensions > 0 {
+			deadlineText += fmt.Sprintf(" (продлён, −%.0f%% награды)", float64(ex.Extensions)*models.ExpeditionExtensionPenalty*100)
+		}
+	}
+	if ex.Status == models.ExpeditionPaused && ex.PausedAt != nil {
+		deadlineText += " | пауза с " + ex.PausedAt.Local().Format("02.01.2006 15:04")
 	}
 	deadlineLabel := components.MakeLabel(deadlineText, t.TextSecondary)
 
@@ -105,6 +133,21 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 		t.Gold,
 	)
 
+	var rewardsLabel fyne.CanvasObject = layout.NewSpacer()
+	if rewards := game.ExpeditionRewards(&ex); len(rewards) > 0 {
+		rewardsLabel = components.MakeLabel("Трофеи: "+formatRewards(rewards), t.Purple)
+	}
+	var tiersLabel fyne.CanvasObject = layout.NewSpacer()
+	if ex.Tiers.Enabled() {
+		tiersText := "Ранги к дедлайну: " + formatTiers(ex.Tiers) + " — бронза и серебро платят долю EXP"
+		if len(game.ExpeditionRewards(&ex)) > 0 {
+			tiersText += ", трофеи и предмет — только за золото"
+		} else {
+			tiersText += ", предмет — только за золото"
+		}
+		tiersLabel = components.MakeLabel(tiersText, t.TextSecondary)
+	}
+
 	completedTasks, totalTasks, percent, err := ctx.Engine.GetExpeditionProgress(ex.ID)
 	progressText := components.MakeLabel("Прогресс: 0 / 0 задач (0%)", t.Accent)
 	if err == nil {
@@ -113,7 +156,10 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 	}
 	progressBar := components.MakeEXPBar(completedTasks, max(1, totalTasks), t.Accent)
 
-	contentItems := []fyne.CanvasObject{nameText, statusBadge, descText, deadlineLabel, rewardText, progressText, progressBar}
+	contentItems := []fyne.CanvasObject{nameText, statusBadge, descText, deadlineLabel, rewardText, rewardsLabel, tiersLabel, progressText, progressBar}
+	if ex.Status == models.ExpeditionActive || ex.Status == models.ExpeditionPaused {
+		contentItems = append(contentItems, buildExpeditionBurnDown(ctx, ex))
+	}
 
 	if len(ex.Tasks) > 0 {
 		contentItems = append(contentItems, widget.NewSeparator())
@@ -128,6 +174,14 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 				fmt.Sprintf("  %s %s (%d/%d)", icon, task.Title, task.ProgressCurrent, max(1, task.ProgressTarget)),
 				color,
 			)
+			if ex.Status == models.ExpeditionActive && !task.IsCompleted {
+				logBtn := widget.NewButtonWithIcon("+N", theme.ContentAddIcon(), func() {
+					showLogTaskQuantityDialog(ctx, task)
+				})
+				logBtn.Importance = widget.LowImportance
+				contentItems = append(contentItems, container.NewBorder(nil, nil, nil, logBtn, line))
+				continue
+			}
 			contentItems = append(contentItems, line)
 		}
 	}
@@ -149,8 +203,42 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 		contentItems = append(contentItems, startBtn)
 	}
 
+	if ex.Status == models.ExpeditionAbandoned && !ex.IsRepeatable {
+		contentItems = append(contentItems, components.MakeLabel("Экспедиция покинута. Одноразовую экспедицию нельзя начать заново.", t.TextSecondary))
+	}
+	if ex.Status == models.ExpeditionAbandoned && ex.IsRepeatable {
+		restartBtn := widget.NewButtonWithIcon("Начать заново", theme.MediaReplayIcon(), func() {
+			if _, err := ctx.Engine.StartExpedition(ex.ID); err != nil {
+				dialog.ShowError(err, ctx.Window)
+				return
+			}
+			RefreshExpeditions(ctx)
+			RefreshQuests(ctx)
+		})
+		contentItems = append(contentItems, restartBtn)
+	}
+
+	if ex.Status == models.ExpeditionActive || ex.Status == models.ExpeditionPaused {
+		contentItems = append(contentItems, buildExpeditionRunControls(ctx, ex))
+	}
+
+	if ex.RunID > 0 {
+		historyBtn := widget.NewButtonWithIcon("История попыток", theme.HistoryIcon(), func() {
+			showExpeditionRunsDialog(ctx, ex)
+		})
+		historyBtn.Importance = widget.LowImportance
+		contentItems = append(contentItems, historyBtn)
+	}
+
 	if ex.Status == models.ExpeditionCompleted {
-		contentItems = append(contentItems, components.MakeLabel("Экспедиция завершена. Награды выданы.", t.Gold))
+		msg := "Экспедиция завершена. Награды выданы."
+		if tier != models.TierNone && tier != models.TierGold {
+			msg = fmt.Sprintf("Экспедиция закрыта по дедлайну: %s %s (%.0f%% EXP, без трофеев и предмета).",
+				tier.Icon(), tier.DisplayName(), tier.PayoutFraction()*100)
+		} else if tier == models.TierGold {
+			msg = tier.Icon() + " " + msg
+		}
+		contentItems = append(contentItems, components.MakeLabel(msg, t.Gold))
 	}
 	if ex.Status == models.ExpeditionFailed {
 		contentItems = append(contentItems, components.MakeLabel("Экспедиция провалена по дедлайну. Награды не выдаются.", t.Danger))
@@ -159,6 +247,196 @@ func buildExpeditionCard(ctx *Context, ex models.Expedition) *fyne.Container {
 	return components.MakeCard(container.NewVBox(contentItems...))
 }
 
+// buildExpeditionBurnDown shows units per day, required pace and projected finish of the current run.
+func buildExpeditionBurnDown(ctx *Context, ex models.Expedition) fyne.CanvasObject {
+	t := components.T()
+	forecast, err := ctx.Engine.GetExpeditionForecast(ex.ID)
+	if err != nil {
+		return components.MakeLabel("Ошибка прогноза: "+err.Error(), t.Danger)
+	}
+
+	paceText := fmt.Sprintf("Темп: %.1f ед./день | Осталось: %d из %d ед.", forecast.RecentPace, forecast.UnitsLeft(), forecast.UnitsTotal)
+	if forecast.RequiredPace > 0 {
+		paceText += fmt.Sprintf(" | Нужно: %.1f ед./день", forecast.RequiredPace)
+	}
+	items := []fyne.CanvasObject{components.MakeLabel(paceText, t.TextSecondary)}
+
+	forecastText := ""
+	forecastColor := t.Success
+	switch {
+	case forecast.UnitsLeft() == 0:
+	case forecast.ProjectedFinish != nil:
+		forecastText = "Прогноз завершения: " + forecast.ProjectedFinish.Local().Format("02.01.2006")
+	default:
+		forecastText = "Прогноз завершения: нет темпа для расчёта"
+		forecastColor = t.TextSecondary
+	}
+	if forecast.AtRisk {
+		forecastText = "⚠ Под угрозой срыва дедлайна. " + forecastText
+		forecastColor = t.Danger
+	}
+	if forecastText != "" {
+		items = append(items, components.MakeLabel(forecastText, forecastColor))
+	}
+
+	for _, day := range forecast.Daily {
+		bar := canvas.NewRectangle(t.Accent)
+		bar.SetMinSize(fyne.NewSize(float32(math.Min(8+float64(day.Units*8), 160)), 6))
+		items = append(items, container.NewHBox(
+			components.MakeLabel(day.Date.Format("02.01"), t.TextSecondary),
+			bar,
+			components.MakeLabel(fmt.Sprintf("  +%d ед.", day.Units), t.TextSecondary),
+		))
+	}
+	return container.NewVBox(items...)
+}
+
+// buildExpeditionRunControls returns pause/resume, extend and abandon buttons.
+func buildExpeditionRunControls(ctx *Context, ex models.Expedition) fyne.CanvasObject {
+	refresh := func() {
+		RefreshExpeditions(ctx)
+		RefreshQuests(ctx)
+	}
+
+	var pauseBtn *widget.Button
+	if ex.Status == models.ExpeditionPaused {
+		pauseBtn = widget.NewButtonWithIcon("Продолжить", theme.MediaPlayIcon(), func() {
+			if _, err := ctx.Engine.ResumeExpedition(ex.ID); err != nil {
+				dialog.ShowError(err, ctx.Window)
+				return
+			}
+			refresh()
+		})
+		pauseBtn.Importance = widget.HighImportance
+	} else {
+		pauseBtn = widget.NewButtonWithIcon("Пауза", theme.MediaPauseIcon(), func() {
+			if err := ctx.Engine.PauseExpedition(ex.ID); err != nil {
+				dialog.ShowError(err, ctx.Window)
+				return
+			}
+			refresh()
+		})
+	}
+
+	extendBtn := widget.NewButtonWithIcon("Продлить", theme.HistoryIcon(), func() {
+		showExtendDeadlineDialog(ctx, ex)
+	})
+	if ex.Deadline == nil || ex.Extensions >= models.MaxExpeditionExtensions {
+		extendBtn.Disable()
+	}
+
+	abandonBtn := widget.NewButtonWithIcon("Покинуть", theme.CancelIcon(), func() {
+		dialog.ShowConfirm("Покинуть экспедицию?",
+			abandonPrompt(ex),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := ctx.Engine.AbandonExpedition(ex.ID); err != nil {
+					dialog.ShowError(err, ctx.Window)
+					return
+				}
+				refresh()
+			}, ctx.Window)
+	})
+	abandonBtn.Importance = widget.LowImportance
+
+	return container.NewHBox(pauseBtn, extendBtn, layout.NewSpacer(), abandonBtn)
+}
+
+// abandonPrompt warns that a one-shot expedition cannot be restarted once abandoned.
+func abandonPrompt(ex models.Expedition) string {
+	msg := fmt.Sprintf("Покинуть \"%s\"? Прогресс забега будет закрыт без наград, задания уйдут в архив.", ex.Name)
+	if !ex.IsRepeatable {
+		msg += " Экспедиция одноразовая: начать её заново будет нельзя."
+	}
+	return msg
+}
+
+func showExtendDeadlineDialog(ctx *Context, ex models.Expedition) {
+	t := components.T()
+	options := make([]string, 0, models.MaxExpeditionExtensionDays)
+	for d := 1; d <= models.MaxExpeditionExtensionDays; d++ {
+		options = append(options, fmt.Sprintf("%d", d))
+	}
+	daysSelect := widget.NewSelect(options, nil)
+	daysSelect.SetSelected("3")
+
+	hint := components.MakeLabel(
+		fmt.Sprintf("Продлить можно %d раз(а). Штраф: −%.0f%% итоговой награды.",
+			models.MaxExpeditionExtensions, models.ExpeditionExtensionPenalty*100),
+		t.Warning,
+	)
+
+	formItems := []*widget.FormItem{
+		widget.NewFormItem("Дней", daysSelect),
+		widget.NewFormItem("", hint),
+	}
+	dialog.ShowForm("Продлить дедлайн", "Продлить", "Отмена", formItems, func(ok bool) {
+		if !ok {
+			return
+		}
+		deadline, err := ctx.Engine.ExtendDeadline(ex.ID, parseIntWithDefault(daysSelect.Selected, 3))
+		if err != nil {
+			dialog.ShowError(err, ctx.Window)
+			return
+		}
+		dialog.ShowInformation("Дедлайн продлён", "Новый дедлайн: "+deadline.Local().Format("02.01.2006 15:04"), ctx.Window)
+		RefreshExpeditions(ctx)
+	}, ctx.Window)
+}
+
+func showLogTaskQuantityDialog(ctx *Context, task models.ExpeditionTask) {
+	t := components.T()
+	remaining := max(0, task.ProgressTarget-task.ProgressCurrent)
+
+	quantityEntry := widget.NewEntry()
+	quantityEntry.SetPlaceHolder("Количество, например 42")
+
+	perUnit := max(1, task.RewardEXP)
+	hint := components.MakeLabel(
+		fmt.Sprintf("Осталось: %d | +%d EXP за единицу (%s)", remaining, perUnit, task.TargetStat.DisplayName()),
+		t.TextSecondary,
+	)
+
+	formItems := []*widget.FormItem{
+		widget.NewFormItem("Задача", components.MakeLabel(task.Title, t.Text)),
+		widget.NewFormItem("Прогресс", hint),
+		widget.NewFormItem("Количество", quantityEntry),
+	}
+
+	dialog.ShowForm("Записать прогресс", "Записать", "Отмена", formItems, func(ok bool) {
+		if !ok {
+			return
+		}
+		quantity := parseIntWithDefault(quantityEntry.Text, 0)
+		result, err := ctx.Engine.LogExpeditionTaskQuantity(task.ID, quantity)
+		if err != nil {
+			dialog.ShowError(err, ctx.Window)
+			return
+		}
+
+		msg := fmt.Sprintf("+%d к задаче \"%s\" (%d/%d)\n\n+%d EXP к %s %s",
+			result.Applied, task.Title, result.Task.ProgressCurrent, max(1, result.Task.ProgressTarget),
+			result.EXPAwarded, task.TargetStat.Icon(), task.TargetStat.DisplayName())
+		if result.LeveledUp {
+			msg += fmt.Sprintf("\n\nУРОВЕНЬ ПОВЫШЕН! %s: %d -> %d",
+				task.TargetStat.DisplayName(), result.OldLevel, result.NewLevel)
+		}
+		if result.ExpeditionCompleted {
+			msg += fmt.Sprintf("\n\nЭКСПЕДИЦИЯ ЗАВЕРШЕНА: %s", result.ExpeditionName)
+		}
+		dialog.ShowInformation("Прогресс записан", msg, ctx.Window)
+
+		if ctx.RefreshAll != nil {
+			ctx.RefreshAll()
+			return
+		}
+		RefreshQuests(ctx)
+		RefreshExpeditions(ctx)
+	}, ctx.Window)
+}
+
 func formatRewardStats(stats map[models.StatType]int) string {
 	if len(stats) == 0 {
 		return "нет"
@@ -176,6 +454,27 @@ func formatRewardStats(stats map[models.StatType]int) string {
 	return strings.Join(parts, ", ")
 }
 
+func formatTiers(tiers models.ExpeditionTiers) string {
+	var parts []string
+	for _, tier := range []struct {
+		tier      models.ExpeditionTier
+		threshold int
+	}{{models.TierBronze, tiers.Bronze}, {models.TierSilver, tiers.Silver}, {models.TierGold, tiers.Gold}} {
+		if tier.threshold > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d%%", tier.tier.Icon(), tier.threshold))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatRewards(rewards []models.Reward) string {
+	parts := make([]string, 0, len(rewards))
+	for _, r := range rewards {
+		parts = append(parts, r.Label())
+	}
+	return strings.Join(parts, ", ")
+}
+
 func max(a int, b int) int {
 	if a > b {
 		return a
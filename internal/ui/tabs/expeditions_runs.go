@@ -0,0 +1,100 @@
+package tabs
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"solo-leveling/internal/models"
+	"solo-leveling/internal/ui/components"
+)
+
+// showExpeditionRunsDialog lists every run of an expedition so attempts can be compared.
+func showExpeditionRunsDialog(ctx *Context, ex models.Expedition) {
+	t := components.T()
+	runs, err := ctx.Engine.DB.GetExpeditionRuns(ctx.Engine.Character.ID, ex.ID)
+	if err != nil {
+		dialog.ShowError(err, ctx.Window)
+		return
+	}
+
+	list := container.NewVBox()
+	if len(runs) == 0 {
+		list.Add(components.MakeLabel("Попыток пока не было.", t.TextSecondary))
+	}
+	now := time.Now()
+	for i, run := range runs {
+		if i > 0 {
+			list.Add(widget.NewSeparator())
+		}
+		outcome, outcomeColor := expeditionRunOutcome(run)
+		header := components.MakeLabel(fmt.Sprintf("Попытка #%d · %s", len(runs)-i, outcome), outcomeColor)
+		header.TextStyle = fyne.TextStyle{Bold: true}
+
+		period := run.StartedAt.Local().Format("02.01.2006") + " — "
+		if run.EndedAt != nil {
+			period += run.EndedAt.Local().Format("02.01.2006")
+		} else {
+			period += "…"
+		}
+		summary := components.MakeLabel(
+			fmt.Sprintf("%s | %s | +%d EXP | задач: %d / %d",
+				period, formatRunDuration(run.Duration(now)), run.EXPEarned, run.CompletedTasks(), len(run.Tasks)),
+			t.TextSecondary,
+		)
+		list.Add(container.NewVBox(header, summary))
+
+		for _, task := range run.Tasks {
+			icon := "[ ]"
+			color := t.TextSecondary
+			if task.IsCompleted {
+				icon = "[✓]"
+				color = t.Success
+			}
+			line := components.MakeLabel(
+				fmt.Sprintf("  %s %s (%d/%d)", icon, task.Title, task.ProgressCurrent, max(1, task.ProgressTarget)),
+				color,
+			)
+			line.TextSize = components.TextBodySM
+			list.Add(line)
+		}
+	}
+
+	d := dialog.NewCustom("История попыток: "+ex.Name, "Закрыть", container.NewVScroll(list), ctx.Window)
+	d.Resize(fyne.NewSize(560, 480))
+	d.Show()
+}
+
+func expeditionRunOutcome(run models.ExpeditionRun) (string, color.Color) {
+	t := components.T()
+	switch run.Status {
+	case models.ExpeditionCompleted:
+		tier := run.Tier
+		if tier == models.TierNone {
+			tier = models.TierGold
+		}
+		return tier.Icon() + " " + tier.DisplayName(), t.Gold
+	case models.ExpeditionFailed:
+		return "⛔ Провалена", t.Danger
+	case models.ExpeditionAbandoned:
+		return "🏳 Покинута", t.TextSecondary
+	case models.ExpeditionPaused:
+		return "⏸ На паузе", t.Blue
+	default:
+		return "🧭 В процессе", t.Success
+	}
+}
+
+func formatRunDuration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%d д. %d ч.", days, hours)
+	}
+	return fmt.Sprintf("%d ч.", hours)
+}
@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"solo-leveling/internal/game"
 	"solo-leveling/internal/models"
 	"solo-leveling/internal/ui/components"
 )
@@ -48,7 +51,9 @@ func RefreshProgress(ctx *Context) {
 		if err == nil && battleStats.TotalBattles > 0 {
 			bCard := buildBattleStatsCard(battleStats)
 			ctx.StatsPanel.Add(bCard)
+			ctx.StatsPanel.Add(buildRecentBattlesCard(ctx))
 		}
+		ctx.StatsPanel.Add(buildPracticeCard(ctx))
 	}
 
 	chartCard := buildActivityChart(ctx)
@@ -123,6 +128,146 @@ func buildBattleStatsCard(stats *models.BattleStatistics) *fyne.Container {
 	return components.MakeCard(content)
 }
 
+// recentBattlesLimit is how many battles the history card lists.
+const recentBattlesLimit = 10
+
+func buildRecentBattlesCard(ctx *Context) *fyne.Container {
+	t := components.T()
+	header := components.MakeTitle("Последние бои", t.Accent, components.TextHeadingMD)
+
+	battles, err := ctx.Engine.GetBattleHistory(recentBattlesLimit)
+	if err != nil {
+		return components.MakeCard(components.MakeLabel("Ошибка загрузки боёв", t.Danger))
+	}
+
+	rows := []fyne.CanvasObject{header, widget.NewSeparator()}
+	for _, b := range battles {
+		resultText, resultColor := "Поражение", t.Danger
+		if b.Result == models.BattleWin {
+			resultText, resultColor = "Победа", t.Success
+		}
+		info := container.NewHBox(
+			components.MakeLabel(b.FoughtAt.Local().Format("02.01 15:04"), t.TextSecondary),
+			components.MakeLabel(b.EnemyName, t.Text),
+			components.MakeLabel(resultText, resultColor),
+			components.MakeLabel(fmt.Sprintf("Точность: %.0f%%", b.Accuracy), t.TextSecondary),
+		)
+		if ctx.ShowBattleReplay == nil {
+			rows = append(rows, info)
+			continue
+		}
+		replayBtn := widget.NewButtonWithIcon("Повтор", theme.MediaPlayIcon(), func() {
+			ctx.ShowBattleReplay(b)
+		})
+		replayBtn.Importance = widget.LowImportance
+		rows = append(rows, container.NewBorder(nil, nil, nil, replayBtn, info))
+	}
+
+	return components.MakeCard(container.NewVBox(rows...))
+}
+
+// practiceRunsLimit is how many practice runs the practice card lists.
+const practiceRunsLimit = 5
+
+// buildPracticeCard starts practice fights and shows the practice accuracy trend.
+func buildPracticeCard(ctx *Context) *fyne.Container {
+	t := components.T()
+	header := components.MakeTitle("Тренировка", t.Accent, components.TextHeadingMD)
+	hint := components.MakeLabel("Без попыток и без влияния на башню — только память.", t.TextSecondary)
+	rows := []fyne.CanvasObject{header, widget.NewSeparator(), hint}
+
+	defeated, err := ctx.Engine.DB.GetDefeatedEnemies(ctx.Engine.Character.ID)
+	if err == nil && len(defeated) > 0 {
+		names := make([]string, 0, len(defeated))
+		ids := make(map[string]int64, len(defeated))
+		for _, d := range defeated {
+			names = append(names, d.Name)
+			ids[d.Name] = d.EnemyID
+		}
+		enemySelect := widget.NewSelect(names, nil)
+		enemySelect.SetSelected(names[len(names)-1])
+		fightBtn := widget.NewButton("Тренироваться", func() {
+			if ctx.StartPractice != nil && enemySelect.Selected != "" {
+				ctx.StartPractice(ids[enemySelect.Selected])
+			}
+		})
+		rows = append(rows, container.NewBorder(nil, nil, nil, fightBtn, enemySelect))
+	}
+
+	var gridOptions []string
+	for g := game.PracticeMinGrid; g <= game.PracticeMaxGrid; g++ {
+		gridOptions = append(gridOptions, fmt.Sprintf("%dx%d", g, g))
+	}
+	cellsSelect := widget.NewSelect(nil, nil)
+	gridSelect := widget.NewSelect(gridOptions, func(selected string) {
+		grid := practiceGridFromOption(selected)
+		var cellOptions []string
+		for c := game.PracticeMinCells; c <= game.PracticeMaxCells(grid); c++ {
+			cellOptions = append(cellOptions, fmt.Sprintf("%d", c))
+		}
+		cellsSelect.Options = cellOptions
+		if n, _ := strconv.Atoi(cellsSelect.Selected); n > game.PracticeMaxCells(grid) || n == 0 {
+			cellsSelect.SetSelected(cellOptions[len(cellOptions)/2])
+		}
+		cellsSelect.Refresh()
+	})
+	gridSelect.SetSelected(gridOptions[1])
+	customBtn := widget.NewButton("Своё поле", func() {
+		cells, _ := strconv.Atoi(cellsSelect.Selected)
+		if ctx.StartCustomPractice != nil {
+			ctx.StartCustomPractice(practiceGridFromOption(gridSelect.Selected), cells)
+		}
+	})
+	rows = append(rows, container.NewBorder(nil, nil, nil, customBtn,
+		container.NewHBox(components.MakeLabel("Поле:", t.Text), gridSelect, components.MakeLabel("Клеток:", t.Text), cellsSelect)))
+
+	progress, err := ctx.Engine.GetPracticeProgress(practiceRunsLimit)
+	if err != nil {
+		rows = append(rows, components.MakeLabel("Ошибка загрузки тренировок", t.Danger))
+		return components.MakeCard(container.NewVBox(rows...))
+	}
+	if len(progress.Runs) == 0 {
+		return components.MakeCard(container.NewVBox(rows...))
+	}
+
+	rows = append(rows, widget.NewSeparator())
+	if progress.HasTrend() {
+		trendColor := t.Success
+		if progress.Delta() < 0 {
+			trendColor = t.Danger
+		}
+		rows = append(rows, components.MakeLabel(
+			fmt.Sprintf("Точность: %.0f%% (было %.0f%%, %+.1f п.п.)", progress.Recent, progress.Previous, progress.Delta()),
+			trendColor,
+		))
+	} else {
+		rows = append(rows, components.MakeLabel(fmt.Sprintf("Точность: %.0f%%", progress.Recent), t.Text))
+	}
+	for i, run := range progress.Runs {
+		if i >= practiceRunsLimit {
+			break
+		}
+		resultText, resultColor := "Поражение", t.Danger
+		if run.Result == models.BattleWin {
+			resultText, resultColor = "Победа", t.Success
+		}
+		rows = append(rows, container.NewHBox(
+			components.MakeLabel(run.PlayedAt.Local().Format("02.01 15:04"), t.TextSecondary),
+			components.MakeLabel(run.EnemyName, t.Text),
+			components.MakeLabel(resultText, resultColor),
+			components.MakeLabel(fmt.Sprintf("Точность: %.0f%%", run.Accuracy), t.TextSecondary),
+		))
+	}
+	return components.MakeCard(container.NewVBox(rows...))
+}
+
+// practiceGridFromOption parses a "NxN" field option.
+func practiceGridFromOption(option string) int {
+	var grid int
+	fmt.Sscanf(option, "%dx", &grid)
+	return grid
+}
+
 func buildActivityChart(ctx *Context) *fyne.Container {
 	t := components.T()
 	header := components.MakeTitle("Активность 30 дней", t.Accent, components.TextHeadingMD)
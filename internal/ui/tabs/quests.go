@@ -567,7 +567,12 @@ func buildQuestCardClassic(ctx *Context, q models.Quest) *fyne.Container {
 		}, ctx.Window)
 	})
 
-	topRow := container.NewHBox(rankBadge, titleText, dailyIndicator, layout.NewSpacer(), completeBtn, failBtn, deleteBtn)
+	actionItems := []fyne.CanvasObject{rankBadge, titleText, dailyIndicator, layout.NewSpacer()}
+	if onLog := questLogQuantityAction(ctx, q); onLog != nil {
+		actionItems = append(actionItems, widget.NewButtonWithIcon("+N", theme.ContentAddIcon(), onLog))
+	}
+	actionItems = append(actionItems, completeBtn, failBtn, deleteBtn)
+	topRow := container.NewHBox(actionItems...)
 	content := container.NewVBox(topRow, statText, rewardText, descLabel)
 	return components.MakeCard(content)
 }
@@ -619,13 +624,30 @@ func buildQuestCardSystem(ctx *Context, q models.Quest) *fyne.Container {
 		Priority:    q.Rank == models.RankA || q.Rank == models.RankS,
 	}
 	actions := components.QuestCardSystemActions{
-		OnComplete: onComplete,
-		OnFail:     onFail,
-		OnDelete:   onDelete,
+		OnComplete:    onComplete,
+		OnFail:        onFail,
+		OnDelete:      onDelete,
+		OnLogQuantity: questLogQuantityAction(ctx, q),
 	}
 	return components.MakeQuestCardSystem(data, actions)
 }
 
+// questLogQuantityAction returns a "+N" handler for expedition quests, nil otherwise.
+func questLogQuantityAction(ctx *Context, q models.Quest) func() {
+	if q.ExpeditionTaskID == nil {
+		return nil
+	}
+	taskID := *q.ExpeditionTaskID
+	return func() {
+		task, err := ctx.Engine.DB.GetExpeditionTaskByID(ctx.Engine.Character.ID, taskID)
+		if err != nil {
+			dialog.ShowError(err, ctx.Window)
+			return
+		}
+		showLogTaskQuantityDialog(ctx, *task)
+	}
+}
+
 func completeQuest(ctx *Context, q models.Quest) {
 	result, err := ctx.Engine.CompleteQuest(q.ID)
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"solo-leveling/internal/game"
+	"solo-leveling/internal/game/combat"
 	"solo-leveling/internal/models"
 	"solo-leveling/internal/ui/components"
 )
@@ -56,8 +57,14 @@ func RefreshToday(ctx *Context) {
 	// --- Middle zone: compact streak line ---
 	streakLine := buildStreakLine(ctx)
 
-	// --- Top block = cards + streak ---
+	// --- Top block = cards + streak (+ expeditions at risk) ---
 	topBlock := container.NewVBox(topRow, streakLine)
+	if challengeLine := buildDailyChallengeLine(ctx); challengeLine != nil {
+		topBlock.Add(challengeLine)
+	}
+	if riskLine := buildExpeditionRiskLine(ctx); riskLine != nil {
+		topBlock.Add(riskLine)
+	}
 
 	// --- Bottom zone: quests fill all remaining space ---
 	questsHeader := components.MakeSectionHeader("Задания на сегодня")
@@ -353,10 +360,14 @@ func buildEnemyDayCard(ctx *Context) *fyne.Container {
 	// Description + attack
 	var infoItems []fyne.CanvasObject
 	if enemy != nil {
-		zoneLabel := components.MakeLabel(
-			fmt.Sprintf("Zone %d · %s", enemyZone, zoneBiomeName(enemyZone)),
-			components.T().Accent,
-		)
+		zoneText := fmt.Sprintf("Zone %d · %s", enemyZone, zoneBiomeName(enemyZone))
+		if enemy.Endless {
+			zoneText = game.FloorName(enemy.Floor)
+			if record, err := ctx.Engine.GetEndlessRecord(); err == nil && record > 0 {
+				zoneText += fmt.Sprintf(" · рекорд: этаж %d", record)
+			}
+		}
+		zoneLabel := components.MakeLabel(zoneText, components.T().Accent)
 		zoneLabel.TextSize = 12
 		infoItems = append(infoItems, zoneLabel)
 	}
@@ -374,6 +385,15 @@ func buildEnemyDayCard(ctx *Context) *fyne.Container {
 		infoItems = append(infoItems, statsLabel)
 	}
 
+	// First-win reward still on offer
+	if enemy != nil {
+		if reward, err := ctx.Engine.GetFirstWinReward(*enemy); err == nil && reward != nil {
+			rewardLabel := components.MakeLabel("Награда за первую победу: "+firstWinRewardText(*reward), components.T().Gold)
+			rewardLabel.TextSize = 12
+			infoItems = append(infoItems, rewardLabel)
+		}
+	}
+
 	// Difficulty bar
 	if enemy != nil {
 		diffSection := buildDifficultySection(ctx, enemy)
@@ -385,6 +405,10 @@ func buildEnemyDayCard(ctx *Context) *fyne.Container {
 
 	// Fight button
 	canFight := ctx.Features.Combat && enemy != nil
+	attempts, attemptsErr := ctx.Engine.GetAttemptStatus()
+	if attemptsErr != nil || !attempts.CanFight() {
+		canFight = false
+	}
 
 	var ctaSection fyne.CanvasObject
 	if !ctx.Features.Combat {
@@ -408,6 +432,13 @@ func buildEnemyDayCard(ctx *Context) *fyne.Container {
 			ctaBtn.Disable()
 		}
 		ctaSection = ctaBtn
+		if attemptsErr != nil {
+			hint := components.MakeLabel("Не удалось загрузить попытки", components.T().Danger)
+			hint.TextSize = 12
+			ctaSection = container.NewVBox(hint, ctaBtn)
+		} else if attempts.Enabled {
+			ctaSection = container.NewVBox(buildAttemptsLine(attempts), ctaBtn)
+		}
 	}
 
 	content := container.NewVBox(
@@ -468,7 +499,77 @@ func buildDifficultySection(ctx *Context, enemy *models.Enemy) fyne.CanvasObject
 	bar := container.NewStack(barBg, container.New(&progressBarLayout{ratio: diffRatio}, barFill))
 
 	headerRow := container.NewHBox(label, layout.NewSpacer(), diffLabel)
-	return container.NewVBox(headerRow, bar)
+	section := container.NewVBox(headerRow, bar)
+
+	// Biome and role modifiers change how the fight plays.
+	for _, mod := range combat.Modifiers(*enemy) {
+		name := canvas.NewText(mod.DisplayName(), dt.Warning)
+		name.TextSize = 11
+		name.TextStyle = fyne.TextStyle{Bold: true}
+		desc := components.MakeLabel(mod.Description(), dt.TextSecondary)
+		desc.TextSize = 11
+		section.Add(container.NewHBox(name, desc))
+	}
+	if adaptive, err := ctx.Engine.GetAdaptiveDifficulty(*enemy); err == nil && adaptive != nil {
+		section.Add(buildAdaptiveLine(adaptive))
+	}
+	return section
+}
+
+// buildAdaptiveLine shows how adaptive difficulty changes the field and why.
+func buildAdaptiveLine(adaptive *game.AdaptiveDifficulty) fyne.CanvasObject {
+	dt := components.T()
+	name := canvas.NewText("Адаптация", dt.Accent)
+	name.TextSize = 11
+	name.TextStyle = fyne.TextStyle{Bold: true}
+
+	var text string
+	switch {
+	case adaptive.Needed > 0:
+		text = fmt.Sprintf("нужно ещё боёв: %d", adaptive.Needed)
+	case adaptive.Shift.IsZero():
+		text = fmt.Sprintf("без изменений — точность %.0f%% за %d боёв, шанс ~%.0f%% при цели %.0f–%.0f%%",
+			adaptive.Accuracy, adaptive.Battles, adaptive.EstimatedWinRate, adaptive.TargetMin, adaptive.TargetMax)
+	default:
+		text = fmt.Sprintf("клетки %+d, показ %+d%% — точность %.0f%% за %d боёв, шанс ~%.0f%% при цели %.0f–%.0f%%",
+			adaptive.Shift.Cells, adaptive.Shift.ShowTimePercent, adaptive.Accuracy, adaptive.Battles,
+			adaptive.EstimatedWinRate, adaptive.TargetMin, adaptive.TargetMax)
+	}
+	desc := components.MakeLabel(text, dt.TextSecondary)
+	desc.TextSize = 11
+	return container.NewHBox(name, desc)
+}
+
+func buildAttemptsLine(status game.AttemptStatus) fyne.CanvasObject {
+	text := fmt.Sprintf("Попытки: %d/%d", status.Attempts, models.MaxAttempts)
+	if status.Reserve > 0 {
+		text += fmt.Sprintf(" (+%d в запасе)", status.Reserve)
+	}
+	if status.DailyPending > 0 {
+		text += fmt.Sprintf(", +%d за сегодня", status.DailyPending)
+	}
+	textColor := components.T().TextSecondary
+	if !status.CanFight() {
+		text = "Нет попыток — выполни задание, чтобы получить новые"
+		textColor = components.T().Danger
+	}
+	label := components.MakeLabel(text, textColor)
+	label.TextSize = 12
+	return label
+}
+
+func firstWinRewardText(reward models.BattleReward) string {
+	var parts []string
+	if reward.Badge != "" {
+		parts = append(parts, reward.Badge)
+	}
+	if reward.Title != "" {
+		parts = append(parts, "титул «"+reward.Title+"»")
+	}
+	if reward.Cosmetic != "" {
+		parts = append(parts, reward.Cosmetic)
+	}
+	return strings.Join(parts, ", ")
 }
 
 func zoneBiomeName(zone int) string {
@@ -530,6 +631,77 @@ func buildStreakLine(ctx *Context) fyne.CanvasObject {
 	return container.NewStack(bg, container.New(layout.NewCustomPaddedLayout(6, 6, 10, 10), row))
 }
 
+// buildDailyChallengeLine offers today's challenge fight with its modifiers and streak.
+func buildDailyChallengeLine(ctx *Context) fyne.CanvasObject {
+	if !ctx.Features.Combat {
+		return nil
+	}
+	t := components.T()
+	challenge, err := ctx.Engine.GetDailyChallenge()
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(challenge.Enemy.Modifiers))
+	for _, mod := range challenge.Enemy.Modifiers {
+		names = append(names, mod.DisplayName())
+	}
+	titleLabel := components.MakeTitle("🎯 Испытание дня", t.Accent, 14)
+	info := components.MakeLabel(
+		fmt.Sprintf("%s · %s", challenge.Enemy.Name, strings.Join(names, ", ")),
+		t.TextSecondary,
+	)
+	info.TextSize = components.TextBodyMD
+	row := container.NewHBox(titleLabel, info)
+	if challenge.Streak > 0 {
+		streak := canvas.NewText(fmt.Sprintf("серия: %d дн.", challenge.Streak), t.Gold)
+		streak.TextSize = components.TextBodyMD
+		row.Add(streak)
+	}
+
+	var action fyne.CanvasObject
+	switch {
+	case challenge.Result != nil && challenge.Result.Result == models.BattleWin:
+		action = components.MakeLabel("Пройдено", t.Gold)
+	case challenge.Played():
+		action = components.MakeLabel("Попытка использована", t.TextMuted)
+	default:
+		action = widget.NewButton("Принять вызов", func() {
+			if ctx.StartDailyChallenge != nil {
+				ctx.StartDailyChallenge()
+			}
+		})
+	}
+
+	bg := canvas.NewRectangle(t.BGCard)
+	bg.CornerRadius = components.RadiusMD
+	bg.StrokeWidth = components.BorderThin
+	bg.StrokeColor = t.Border
+	line := container.NewBorder(nil, nil, nil, action, row)
+	return container.NewStack(bg, container.New(layout.NewCustomPaddedLayout(6, 6, 10, 10), line))
+}
+
+// buildExpeditionRiskLine flags active expeditions projected to miss their deadline.
+func buildExpeditionRiskLine(ctx *Context) fyne.CanvasObject {
+	t := components.T()
+	atRisk, err := ctx.Engine.GetExpeditionsAtRisk()
+	if err != nil || len(atRisk) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(atRisk))
+	for _, exp := range atRisk {
+		names = append(names, exp.Name)
+	}
+	label := components.MakeLabel("⚠ Экспедиции под угрозой: "+strings.Join(names, ", "), t.Danger)
+	label.TextStyle = fyne.TextStyle{Bold: true}
+
+	bg := canvas.NewRectangle(t.BGCard)
+	bg.CornerRadius = components.RadiusMD
+	bg.StrokeWidth = components.BorderThin
+	bg.StrokeColor = t.Danger
+	return container.NewStack(bg, container.New(layout.NewCustomPaddedLayout(6, 6, 10, 10), label))
+}
+
 // =============================================================================
 // Today's Quests
 // =============================================================================
@@ -692,6 +864,9 @@ func makeTopCard(content fyne.CanvasObject, minSize fyne.Size) *fyne.Container {
 func resolveEnemyImagePath(enemy models.Enemy) string {
 	slugName := enemyImageSlug(enemy.Name)
 	candidates := []string{}
+	if enemy.Image != "" {
+		candidates = append(candidates, filepath.Join("assets", "enemies", enemy.Image))
+	}
 	for _, ext := range []string{".jpg", ".jpeg", ".png"} {
 		candidates = append(candidates,
 			filepath.Join("assets", "enemies", fmt.Sprintf("enemy_%d%s", enemy.ID, ext)),
@@ -701,6 +876,9 @@ func resolveEnemyImagePath(enemy models.Enemy) string {
 	}
 	if exePath, err := os.Executable(); err == nil {
 		exeDir := filepath.Dir(exePath)
+		if enemy.Image != "" {
+			candidates = append(candidates, filepath.Join(exeDir, "assets", "enemies", enemy.Image))
+		}
 		for _, ext := range []string{".jpg", ".jpeg", ".png"} {
 			candidates = append(candidates,
 				filepath.Join(exeDir, "assets", "enemies", fmt.Sprintf("enemy_%d%s", enemy.ID, ext)),
@@ -10,6 +10,8 @@ import (
 	"solo-leveling/internal/config"
 	"solo-leveling/internal/database"
 	"solo-leveling/internal/game"
+	"solo-leveling/internal/game/catalog"
+	"solo-leveling/internal/models"
 	"solo-leveling/internal/sim"
 	"solo-leveling/internal/ui"
 )
@@ -18,6 +20,9 @@ func main() {
 	if runSeedEnemiesCLI() {
 		return
 	}
+	if runExpeditionsCLI() {
+		return
+	}
 
 	// Headless simulation mode — no DB, no UI
 	if sim.RunCLIAutoTune() {
@@ -41,6 +46,14 @@ func main() {
 		log.Fatalf("Failed to initialize game engine: %v", err)
 	}
 	features := config.DefaultFeatures()
+	if features.BattleAttempts {
+		rules := models.DefaultAttemptRules()
+		engine.AttemptRules = &rules
+	}
+	if features.AdaptiveDifficulty {
+		rules := models.DefaultAdaptiveRules()
+		engine.Adaptive = &rules
+	}
 
 	// Seed preset expeditions if not yet created.
 	if err := engine.InitExpeditions(); err != nil {
@@ -75,6 +88,13 @@ func main() {
 		log.Printf("Warning: failed to init enemies: %v", err)
 	}
 
+	// Grant today's free battle attempt.
+	if granted, err := engine.ClaimDailyAttempt(); err != nil {
+		log.Printf("Warning: failed to claim daily attempt: %v", err)
+	} else if granted > 0 {
+		log.Printf("Granted %d daily battle attempt(s)", granted)
+	}
+
 	application := fyneApp.New()
 	application.Settings().SetTheme(&ui.SoloLevelingTheme{})
 
@@ -107,5 +127,68 @@ func runSeedEnemiesCLI() bool {
 		log.Fatalf("Failed to count enemies: %v", err)
 	}
 	fmt.Printf("Enemy catalog ready: %d enemies across 5 zones.\n", count)
+	if dir, err := config.DataDir(); err == nil {
+		if file, err := catalog.ReadFile(dir); err == nil && file.Tuning != nil {
+			t := file.Tuning
+			fmt.Printf("Stats from auto-tune seed %d (%d runs/eval, %d iterations, power %.2f-%.2f) applied %s.\n",
+				t.Seed, t.RunsPerEval, t.Iterations, t.MinPower, t.MaxPower, t.AppliedAt)
+		}
+	}
+	return true
+}
+
+// runExpeditionsCLI handles --import-expeditions <file> and
+// --export-expeditions [file] (stdout when no file is given).
+func runExpeditionsCLI() bool {
+	args := os.Args[1:]
+	if len(args) == 0 || (args[0] != "--import-expeditions" && args[0] != "--export-expeditions") {
+		return false
+	}
+
+	db, err := database.New()
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	engine, err := game.NewEngine(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize game engine: %v", err)
+	}
+
+	if args[0] == "--export-expeditions" {
+		raw, err := engine.ExportExpeditionsJSON()
+		if err != nil {
+			log.Fatalf("Failed to export expeditions: %v", err)
+		}
+		if len(args) < 2 {
+			fmt.Println(string(raw))
+			return true
+		}
+		if err := os.WriteFile(args[1], raw, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", args[1], err)
+		}
+		fmt.Printf("Exported expeditions to %s\n", args[1])
+		return true
+	}
+
+	if len(args) < 2 {
+		log.Fatalf("Usage: --import-expeditions <file.json>")
+	}
+	raw, err := os.ReadFile(args[1])
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", args[1], err)
+	}
+	result, err := engine.ImportExpeditionsJSON(raw)
+	if err != nil {
+		log.Fatalf("Failed to import expeditions: %v", err)
+	}
+	fmt.Printf("Imported expeditions: %d\n", len(result.Created))
+	for _, importErr := range result.Errors {
+		fmt.Printf("  error %v\n", importErr)
+	}
+	if len(result.Errors) > 0 {
+		os.Exit(1)
+	}
 	return true
 }